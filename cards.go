@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// cardPreset은 카메라 카드의 디렉토리 구조와 클립 이름 규칙이다.
+type cardPreset struct {
+	Name  string
+	Label string
+	// Clip은 클립 파일 또는 클립 디렉토리의 이름에 맞는 정규식이다.
+	// REEL 그룹이 있다면 그 값을 릴 이름으로 쓰고, 없다면 카드 루트의 이름을 쓴다.
+	Clip *regexp.Regexp
+	// Dirs가 비어있지 않으면 이 이름을 가진 디렉토리 바로 아래에서만 클립을 찾는다.
+	Dirs []string
+}
+
+// cardPresets는 지원하는 카메라 카드의 구조들로, auto일 때 이 순서대로 확인한다.
+var cardPresets = []cardPreset{
+	{
+		// A001R1AB/A001C003_220101_R1AB.mxf 또는 ARRIRAW 클립 디렉토리
+		Name:  "arri",
+		Label: "ARRI",
+		Clip:  regexp.MustCompile(`^(?P<REEL>[A-Z]\d{3})C\d{3}_\d{6}_[0-9A-Z]{4}(?:\.(?i:mxf|mov))?$`),
+	},
+	{
+		// A001_0101XX.RDM/A001_C001_0101AB.RDC/A001_C001_0101AB_001.R3D
+		Name:  "red",
+		Label: "RED",
+		Clip:  regexp.MustCompile(`^(?P<REEL>[A-Z]\d{3})_C\d{3}_[0-9A-Z]{6}\.(?i:rdc)$`),
+	},
+	{
+		// XDROOT/Clip/A001C001_230101AB.MXF 또는 PRIVATE/M4ROOT/CLIP/C0001.MP4
+		Name:  "sony",
+		Label: "Sony",
+		Clip:  regexp.MustCompile(`^(?:(?P<REEL>[A-Z]\d{3})C\d{3}_[0-9A-Z]+|C\d{4})\.(?i:mxf|mp4)$`),
+		Dirs:  []string{"clip"},
+	},
+	{
+		// A001_08151234_C001.braw
+		Name:  "bmd",
+		Label: "Blackmagic",
+		Clip:  regexp.MustCompile(`^(?P<REEL>[A-Z]\d{3})_\d{8}_C\d{3}\.(?i:braw|mov)$`),
+	},
+}
+
+// cardClip은 카메라 카드에서 찾은 클립 하나와 그 클립에서 얻은 변수들이다.
+type cardClip struct {
+	Path string
+	Env  map[string]string
+}
+
+// cardSearchDepth는 카드 루트에서 클립을 찾을 최대 깊이이다.
+const cardSearchDepth = 4
+
+// findCardClips는 카드 루트 root 아래에서 preset의 클립들을 찾는다.
+// 클립 디렉토리 안이나 숨김 디렉토리에는 들어가지 않는다.
+func findCardClips(root string, preset cardPreset) ([]cardClip, error) {
+	clips := make([]cardClip, 0)
+	var find func(dir string, depth int) error
+	find = func(dir string, depth int) error {
+		ents, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		inClipDir := len(preset.Dirs) == 0
+		for _, d := range preset.Dirs {
+			if strings.EqualFold(filepath.Base(dir), d) {
+				inClipDir = true
+			}
+		}
+		for _, ent := range ents {
+			name := ent.Name()
+			if strings.HasPrefix(name, ".") {
+				continue
+			}
+			path := filepath.Join(dir, name)
+			if inClipDir {
+				if m := preset.Clip.FindStringSubmatch(name); m != nil {
+					reel := ""
+					if i := preset.Clip.SubexpIndex("REEL"); i >= 0 {
+						reel = m[i]
+					}
+					if reel == "" {
+						reel = filepath.Base(root)
+					}
+					clip := strings.TrimSuffix(name, filepath.Ext(name))
+					clips = append(clips, cardClip{
+						Path: path,
+						Env:  map[string]string{"CLIP": clip, "REEL": reel, "CARD": preset.Label},
+					})
+					continue
+				}
+			}
+			if ent.IsDir() && depth < cardSearchDepth {
+				if err := find(path, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := find(root, 0); err != nil {
+		return nil, err
+	}
+	return clips, nil
+}
+
+// cardClips는 p.Card 설정에 따라 디렉토리 root를 카메라 카드로 보고 그 안의 클립들을 찾는다.
+// auto라면 클립을 찾을 수 있는 첫번째 구조를 쓴다.
+// 카드가 아니거나 클립을 찾지 못했다면 빈 목록을 반환한다.
+func (p *Program) cardClips(root string) ([]cardClip, *cardPreset, error) {
+	for i, preset := range cardPresets {
+		if p.Card != "auto" && p.Card != preset.Name {
+			continue
+		}
+		clips, err := findCardClips(root, preset)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(clips) != 0 {
+			return clips, &cardPresets[i], nil
+		}
+	}
+	return nil, nil, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// catalog은 소스들을 복사하지 않고 그 안의 파일들의 크기와 해시를 p.Copied에 모은다.
+// 작업 기록에 남겨 어떤 드라이브에 무엇이 있는지 목록을 만들어 두고, 나중에 무엇을 가져올지 정할 수 있게 한다.
+// 대상 경로와 관계없이 존재하는 모든 소스를 기록하며, 대상 경로는 비워둔다.
+func (p *Program) catalog() error {
+	p.Copied = make([]IngestFile, 0)
+	// 진행 상황을 보여줄 수 있도록 해시를 계산할 파일들을 먼저 모두 모은다.
+	files := make([]srcFile, 0)
+	var totalBytes int64
+	for _, src := range p.Srcs {
+		if p.SrcSkipped[src] {
+			continue
+		}
+		err := p.walkSource(src, func(f srcFile) error {
+			if f.Link != "" {
+				// 그대로 재현할 심볼릭 링크는 내용이 없다.
+				return nil
+			}
+			files = append(files, f)
+			totalBytes += fileSize(f)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, src)
+		}
+	}
+	p.progress.setTotal(len(files), totalBytes)
+	for _, f := range files {
+		fi, err := os.Stat(f.Real)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, f.Path)
+		}
+		p.progress.startFile(f.Path, fi.Size())
+		hash, err := hashFile(f.Real)
+		p.progress.finishFile()
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, f.Path)
+		}
+		p.Copied = append(p.Copied, IngestFile{Src: f.Path, Size: fi.Size(), Hash: hash})
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"unicode/utf8"
+
+	"gioui.org/layout"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// chooseResult는 파일 선택 창에서 고른 결과이다.
+type chooseResult struct {
+	// Dest는 대상 경로를 고른 것인지 여부이다.
+	Dest  bool
+	Paths []string
+	Err   error
+}
+
+// browse는 다른 고루틴에서 파일 선택 창을 띄운다. 고른 경로들은 takeChosen이 입력에 붙인다.
+// 선택 창은 한번에 하나만 띄운다.
+func (ui *UI) browse(dirs bool) {
+	ui.startChooser(dirs, false)
+}
+
+// browseDest는 다른 고루틴에서 폴더 선택 창을 띄운다. 고른 폴더는 takeChosen이 대상 경로로 채운다.
+func (ui *UI) browseDest() {
+	ui.startChooser(true, true)
+}
+
+func (ui *UI) startChooser(dirs, dest bool) {
+	if ui.browsing {
+		return
+	}
+	ui.browsing = true
+	go func() {
+		paths, err := choosePaths(dirs, !dest)
+		ui.chosen <- chooseResult{Dest: dest, Paths: paths, Err: err}
+		ui.Window.Invalidate()
+	}()
+}
+
+// takeChosen은 파일 선택 창에서 고른 경로들을 입력 에디터 끝에 한 줄씩 붙이거나 대상 경로 에디터에 채우고,
+// 에디터를 바꿨다면 참을 반환한다.
+func (ui *UI) takeChosen() bool {
+	select {
+	case r := <-ui.chosen:
+		ui.browsing = false
+		if r.Err != nil {
+			ui.Notifier.SetText(explainError(r.Err))
+			ui.NotifyIsError = true
+			return false
+		}
+		if len(r.Paths) == 0 {
+			return false
+		}
+		if r.Dest && ui.Locked() {
+			// 선택 창이 떠 있는 동안 분석했다면 분석한 설정을 바꾸지 않는다.
+			ui.Notifier.SetText(tr("destination not changed, cancel the analysis first"))
+			ui.NotifyIsError = true
+			return false
+		}
+		if r.Dest {
+			// 고른 폴더를 채운 뒤에도 ${VAR}를 붙여 고칠 수 있도록 커서를 끝에 둔다.
+			ui.DestEditor.SetText(r.Paths[0])
+			n := utf8.RuneCountInString(r.Paths[0])
+			ui.DestEditor.SetCaret(n, n)
+			return true
+		}
+		appendLines(ui.InputEditor, r.Paths)
+		return true
+	default:
+		return false
+	}
+}
+
+// layoutBrowseButton은 파일 선택 창을 띄우는 버튼을 그린다. 선택 창이 떠 있는 동안에는 비활성화한다.
+func (ui *UI) layoutBrowseButton(btn *widget.Clickable, label string) layout.Widget {
+	return func(gtx C) D {
+		if ui.browsing {
+			gtx = gtx.Disabled()
+		}
+		return material.Button(ui.Theme, btn, label).Layout(gtx)
+	}
+}
@@ -0,0 +1,77 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// choosePaths는 플랫폼의 파일 선택 창을 띄우고 고른 경로들을 반환한다.
+// dirs가 참이면 폴더를, 아니면 파일을 고른다. 창을 닫으면 경로 없이 nil 에러를 반환한다.
+// macOS에서는 osascript를, 그 외에는 zenity나 kdialog를 쓴다.
+func choosePaths(dirs, multiple bool) ([]string, error) {
+	cmds := chooserCommands(dirs, multiple)
+	for _, args := range cmds {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		var out bytes.Buffer
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdout = &out
+		err := cmd.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// 선택 창을 닫았다.
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		paths := make([]string, 0)
+		for _, l := range strings.Split(out.String(), "\n") {
+			if l = strings.TrimRight(l, "\r"); l != "" {
+				paths = append(paths, filepath.Clean(l))
+			}
+		}
+		return paths, nil
+	}
+	return nil, errors.New("no file chooser found, install zenity or kdialog")
+}
+
+// chooserCommands는 파일 선택 창을 띄우는 명령들로, 먼저 찾은 것을 쓴다.
+// 각 명령은 고른 경로를 한 줄에 하나씩 출력한다.
+func chooserCommands(dirs, multiple bool) [][]string {
+	if runtime.GOOS == "darwin" {
+		what := "file"
+		if dirs {
+			what = "folder"
+		}
+		script := "set l to choose " + what
+		if multiple {
+			script += " with multiple selections allowed"
+		}
+		script += "\nif class of l is not list then set l to {l}\n" +
+			"set out to \"\"\n" +
+			"repeat with f in l\nset out to out & POSIX path of f & linefeed\nend repeat\n" +
+			"return out"
+		return [][]string{{"osascript", "-e", script}}
+	}
+	zenity := []string{"zenity", "--file-selection", "--separator=\n"}
+	kdialog := []string{"kdialog", "--getopenfilename", ".", "--separate-output"}
+	if dirs {
+		zenity = append(zenity, "--directory")
+		kdialog = []string{"kdialog", "--getexistingdirectory", "."}
+	}
+	if multiple {
+		zenity = append(zenity, "--multiple")
+		if !dirs {
+			kdialog = append(kdialog, "--multiple")
+		}
+	}
+	return [][]string{zenity, kdialog}
+}
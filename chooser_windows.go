@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	ole32                = windows.NewLazySystemDLL("ole32.dll")
+	procCoCreateInstance = ole32.NewProc("CoCreateInstance")
+)
+
+const (
+	clsidFileOpenDialog = "{DC1C5A9C-E88A-4DDE-A5A1-60F82A20AEF7}"
+	iidFileOpenDialog   = "{D57C7288-D4AD-4768-BE02-9D969532D960}"
+
+	fosPickFolders      = 0x20
+	fosForceFileSystem  = 0x40
+	fosAllowMultiSelect = 0x200
+	fosPathMustExist    = 0x800
+	fosFileMustExist    = 0x1000
+
+	sigdnFileSysPath = 0x80058000
+	// errCancelled는 사용자가 선택 창을 닫았을 때 Show가 반환하는 HRESULT_FROM_WIN32(ERROR_CANCELLED)이다.
+	errCancelled = 0x800704C7
+)
+
+// IFileOpenDialog, IShellItemArray, IShellItem 메소드들의 vtable 위치
+const (
+	vtRelease            = 2
+	vtShow               = 3
+	vtSetOptions         = 9
+	vtGetOptions         = 10
+	vtGetResults         = 27
+	vtArrayGetCount      = 7
+	vtArrayGetItemAt     = 8
+	vtItemGetDisplayName = 5
+)
+
+// comCall은 COM 객체 obj의 vtable에서 i번째 메소드를 부르고 HRESULT를 반환한다.
+func comCall(obj unsafe.Pointer, i int, args ...uintptr) uintptr {
+	vtbl := *(*unsafe.Pointer)(obj)
+	method := *(*uintptr)(unsafe.Add(vtbl, uintptr(i)*unsafe.Sizeof(uintptr(0))))
+	r, _, _ := syscall.SyscallN(method, append([]uintptr{uintptr(obj)}, args...)...)
+	return r
+}
+
+// choosePaths는 윈도우즈의 파일 열기 창(IFileOpenDialog)을 띄우고 고른 경로들을 반환한다.
+// dirs가 참이면 폴더를, 아니면 파일을 고른다. 창을 닫으면 경로 없이 nil 에러를 반환한다.
+func choosePaths(dirs, multiple bool) ([]string, error) {
+	// COM 객체는 만든 스레드에서만 써야 한다.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if err := windows.CoInitializeEx(0, windows.COINIT_APARTMENTTHREADED); err == nil || err == syscall.Errno(1) {
+		defer windows.CoUninitialize()
+	}
+	clsid, err := windows.GUIDFromString(clsidFileOpenDialog)
+	if err != nil {
+		return nil, err
+	}
+	iid, err := windows.GUIDFromString(iidFileOpenDialog)
+	if err != nil {
+		return nil, err
+	}
+	var dlg unsafe.Pointer
+	hr, _, _ := procCoCreateInstance.Call(uintptr(unsafe.Pointer(&clsid)), 0, windows.CLSCTX_INPROC_SERVER, uintptr(unsafe.Pointer(&iid)), uintptr(unsafe.Pointer(&dlg)))
+	if hr != 0 {
+		return nil, syscall.Errno(hr)
+	}
+	defer comCall(dlg, vtRelease)
+	var opts uint32
+	comCall(dlg, vtGetOptions, uintptr(unsafe.Pointer(&opts)))
+	opts |= fosForceFileSystem | fosPathMustExist
+	if dirs {
+		opts |= fosPickFolders
+	} else {
+		opts |= fosFileMustExist
+	}
+	if multiple {
+		opts |= fosAllowMultiSelect
+	}
+	comCall(dlg, vtSetOptions, uintptr(opts))
+	hr = comCall(dlg, vtShow, nativeWindowHandle())
+	if hr == errCancelled {
+		return nil, nil
+	}
+	if hr != 0 {
+		return nil, syscall.Errno(hr)
+	}
+	var items unsafe.Pointer
+	if hr := comCall(dlg, vtGetResults, uintptr(unsafe.Pointer(&items))); hr != 0 {
+		return nil, syscall.Errno(hr)
+	}
+	defer comCall(items, vtRelease)
+	var n uint32
+	comCall(items, vtArrayGetCount, uintptr(unsafe.Pointer(&n)))
+	paths := make([]string, 0, n)
+	for i := uint32(0); i < n; i++ {
+		var item unsafe.Pointer
+		if comCall(items, vtArrayGetItemAt, uintptr(i), uintptr(unsafe.Pointer(&item))) != 0 {
+			continue
+		}
+		var name *uint16
+		if comCall(item, vtItemGetDisplayName, sigdnFileSysPath, uintptr(unsafe.Pointer(&name))) == 0 {
+			paths = append(paths, windows.UTF16PtrToString(name))
+			windows.CoTaskMemFree(unsafe.Pointer(name))
+		}
+		comCall(item, vtRelease)
+	}
+	if n != 0 && len(paths) == 0 {
+		return nil, errors.New("chosen items are not in the filesystem")
+	}
+	return paths, nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// runCommand는 커맨드라인으로 받은 하위 명령을 실행한다.
+func runCommand(args []string) error {
+	cmd := args[0]
+	args = args[1:]
+	switch cmd {
+	case "export-history":
+		return exportHistoryCommand(args)
+	case "import-history":
+		return importHistoryCommand(args)
+	case "validate-plan":
+		return validatePlanCommand(args)
+	case "diff":
+		return diffCommand(args)
+	case "verify-archive":
+		return verifyArchiveCommand(args)
+	}
+	return fmt.Errorf("unknown command: %s", cmd)
+}
+
+// exportHistoryCommand는 작업 기록을 파일로 내보낸다.
+// 파일 경로가 없거나 - 라면 표준 출력으로 내보낸다.
+//
+//	takein export-history [file]
+func exportHistoryCommand(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: takein export-history [file]")
+	}
+	hist, err := historyFile()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 || args[0] == "-" {
+		return exportHistory(hist, os.Stdout)
+	}
+	f, err := os.Create(args[0])
+	if err != nil {
+		return err
+	}
+	err = exportHistory(hist, f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// importHistoryCommand는 다른 곳에서 내보낸 작업 기록들을 현재 기록에 합친다.
+//
+//	takein import-history file...
+func importHistoryCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: takein import-history file...")
+	}
+	hist, err := historyFile()
+	if err != nil {
+		return err
+	}
+	for _, file := range args {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		added, skipped, err := importHistory(hist, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%v: %s", err, file)
+		}
+		fmt.Printf("%s: %d runs imported, %d already exist\n", file, added, skipped)
+	}
+	return nil
+}
+
+// validatePlanCommand는 계획 파일들이 plan.schema.json을 따르는지 검사한다.
+// 문제가 있는 파일이 하나라도 있으면 에러를 반환한다.
+//
+//	takein validate-plan file...
+func validatePlanCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: takein validate-plan file...")
+	}
+	invalid := 0
+	for _, file := range args {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		errs := validatePlan(data)
+		if len(errs) != 0 {
+			invalid++
+		}
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+		}
+	}
+	if invalid != 0 {
+		return fmt.Errorf("%d of %d plans are invalid", invalid, len(args))
+	}
+	return nil
+}
+
+// diffCommand는 소스 디렉토리와 그것이 복사된 대상 디렉토리를 비교해 차이를 출력한다.
+// 차이가 하나라도 있으면 에러를 반환한다.
+//
+//	takein diff src dest
+func diffCommand(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: takein diff src dest")
+	}
+	d, err := diffTrees(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	for _, line := range d.lines() {
+		fmt.Println(line)
+	}
+	if n := d.count(); n != 0 {
+		return fmt.Errorf("%d missing, %d extra, %d size differs", len(d.Missing), len(d.Extra), len(d.SizeDiffers))
+	}
+	return nil
+}
+
+// verifyArchiveCommand는 디렉토리들 안으로 복사된 파일들을 다시 해시해 작업 기록의 해시와 비교하고,
+// 파일마다 결과를 출력한다. 바뀌었거나 없는 파일이 하나라도 있으면 에러를 반환한다.
+//
+//	takein verify-archive dir...
+func verifyArchiveCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: takein verify-archive dir...")
+	}
+	hist, err := historyFile()
+	if err != nil {
+		return err
+	}
+	recs, err := readHistory(hist)
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, hist)
+	}
+	checked, failed := 0, 0
+	for _, dir := range args {
+		results, err := verifyArchive(recs, dir, os.Stdout)
+		if err != nil {
+			return fmt.Errorf("%v: %s", err, dir)
+		}
+		if len(results) == 0 {
+			fmt.Fprintf(os.Stderr, "%s: no hashed files in the history, ingest with HashHistory on\n", dir)
+		}
+		for _, r := range results {
+			checked++
+			if r.Status != "ok" {
+				failed++
+			}
+		}
+	}
+	if failed != 0 {
+		return fmt.Errorf("%d of %d files failed verification", failed, checked)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// condition은 분석한 키 값들을 검사하는 식이다.
+// 비교들을 &&로 묶은 것들을 ||로 묶은 형태로, &&가 ||보다 먼저 계산된다.
+//
+//	PART == "audio"
+//	SHOW != "abc" && NAME =~ "^plate_"
+//	EXT == "wav" || EXT == "aif"
+type condition [][]comparison
+
+// comparison은 키의 값과 문자열 또는 정규식의 비교 하나이다.
+// 분석되지 않은 키의 값은 빈 문자열로 본다.
+type comparison struct {
+	Key   string
+	Op    string // ==, !=, =~, !~
+	Value string
+	re    *regexp.Regexp
+}
+
+// parseCondition은 식을 분석한다.
+// 값은 큰따옴표로 감싸거나, 공백이 없다면 그대로 쓸 수 있다.
+func parseCondition(expr string) (condition, error) {
+	toks, err := condTokens(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+	cond := condition{nil}
+	for i := 0; i < len(toks); {
+		if i+2 >= len(toks) {
+			return nil, fmt.Errorf("incomplete comparison in condition: %s", expr)
+		}
+		c := comparison{Key: toks[i], Op: toks[i+1], Value: toks[i+2]}
+		switch c.Op {
+		case "==", "!=":
+		case "=~", "!~":
+			c.re, err = regexp.Compile(c.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %s", err, expr)
+			}
+		default:
+			return nil, fmt.Errorf("unknown operator %q in condition: %s", c.Op, expr)
+		}
+		cond[len(cond)-1] = append(cond[len(cond)-1], c)
+		i += 3
+		if i == len(toks) {
+			break
+		}
+		switch toks[i] {
+		case "&&":
+		case "||":
+			cond = append(cond, nil)
+		default:
+			return nil, fmt.Errorf("expected && or || but got %q in condition: %s", toks[i], expr)
+		}
+		i++
+		if i == len(toks) {
+			return nil, fmt.Errorf("incomplete comparison in condition: %s", expr)
+		}
+	}
+	return cond, nil
+}
+
+// condTokens는 식을 키, 연산자, 값 토큰으로 나눈다.
+func condTokens(expr string) ([]string, error) {
+	toks := make([]string, 0)
+	for {
+		expr = strings.TrimLeft(expr, " \t")
+		if expr == "" {
+			return toks, nil
+		}
+		if len(expr) >= 2 {
+			switch expr[:2] {
+			case "==", "!=", "=~", "!~", "&&", "||":
+				toks = append(toks, expr[:2])
+				expr = expr[2:]
+				continue
+			}
+		}
+		if expr[0] == '"' {
+			q, err := strconv.QuotedPrefix(expr)
+			if err != nil {
+				return nil, fmt.Errorf("unterminated string in condition: %s", expr)
+			}
+			v, err := strconv.Unquote(q)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, v)
+			expr = expr[len(q):]
+			continue
+		}
+		end := strings.IndexAny(expr, " \t=!&|\"")
+		if end == -1 {
+			end = len(expr)
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("unexpected %q in condition", expr[:1])
+		}
+		toks = append(toks, expr[:end])
+		expr = expr[end:]
+	}
+}
+
+// Match는 env가 식을 만족하는지 확인한다.
+func (cond condition) Match(env map[string]string) bool {
+	for _, and := range cond {
+		ok := true
+		for _, c := range and {
+			if !c.Match(env) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Match는 env가 비교를 만족하는지 확인한다.
+func (c comparison) Match(env map[string]string) bool {
+	v := env[c.Key]
+	switch c.Op {
+	case "==":
+		return v == c.Value
+	case "!=":
+		return v != c.Value
+	case "=~":
+		return c.re.MatchString(v)
+	case "!~":
+		return !c.re.MatchString(v)
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+func TestParseConditionErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"PART ==",
+		"PART = audio",
+		`PART == "audio`,
+		"PART == audio &&",
+		"PART == audio EXT == wav",
+		`NAME =~ "("`,
+	} {
+		if _, err := parseCondition(expr); err == nil {
+			t.Errorf("parseCondition(%q): want error", expr)
+		}
+	}
+}
+
+func TestConditionMatch(t *testing.T) {
+	env := map[string]string{"PART": "audio", "EXT": "wav", "NAME": "plate_bg01", "SHOW": "abc"}
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{`PART == "audio"`, true},
+		{"PART == audio", true},
+		{"PART != audio", false},
+		{`NAME =~ "^plate_"`, true},
+		{`NAME !~ "^plate_"`, false},
+		{`SHOW != "abc" && NAME =~ "^plate_"`, false},
+		{`EXT == "mov" || EXT == "wav"`, true},
+		// &&가 ||보다 먼저 계산된다.
+		{`EXT == "mov" && PART == "audio" || SHOW == "abc"`, true},
+		{`EXT == "mov" || PART == "video" && SHOW == "abc"`, false},
+		// 분석되지 않은 키의 값은 빈 문자열이다.
+		{`VER == ""`, true},
+		{`VER != ""`, false},
+	}
+	for _, c := range cases {
+		cond, err := parseCondition(c.expr)
+		if err != nil {
+			t.Errorf("parseCondition(%q): %v", c.expr, err)
+			continue
+		}
+		if got := cond.Match(env); got != c.want {
+			t.Errorf("%q.Match = %v, want %v", c.expr, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// existingDestSummary는 이미 있는 대상 디렉토리들에 더해질 파일들을 요약한다.
+// 이미 있는 대상 디렉토리에 복사할 소스가 없다면 빈 문자열을 반환한다.
+// 빼기로 한 소스와 이미 대상 경로에 있어 건너뛸 파일은 세지 않는다.
+func (p *Program) existingDestSummary() string {
+	dirs, files := 0, 0
+	partial := false
+	for dd, srcs := range p.DestDirSrcs {
+		if !p.DestDirExists[dd] {
+			continue
+		}
+		used := false
+		for _, src := range srcs {
+			if p.SrcSkipped[src] {
+				continue
+			}
+			used = true
+			switch {
+			case p.SrcIsDir[src]:
+				if p.SrcCounting[src] {
+					partial = true
+					continue
+				}
+				files += p.SrcDirFileCount[src] - p.SrcDestExisting[src]
+			case !p.SrcDestExists[src] && !p.SrcLinked[src]:
+				files++
+			}
+		}
+		if used {
+			dirs++
+		}
+	}
+	if dirs == 0 {
+		return ""
+	}
+	line := fmt.Sprintf(tr("%d destinations already exist, "), dirs)
+	if dirs == 1 {
+		line = tr("1 destination already exists, ")
+	}
+	if partial {
+		line += tr("at least ")
+	}
+	if files == 1 {
+		return line + tr("1 file will be added")
+	}
+	return line + fmt.Sprintf(tr("%d files will be added"), files)
+}
+
+// requestRun은 이미 있는 대상 디렉토리에 쓰게 된다면 먼저 확인을 받고, 아니면 바로 복사한다.
+func (ui *UI) requestRun() {
+	if ui.Program.Method != "catalog" {
+		if summary := ui.Program.existingDestSummary(); summary != "" {
+			ui.ConfirmText = summary
+			return
+		}
+	}
+	ui.run(0)
+}
+
+// layoutConfirm은 복사하기 전에 확인을 받는 창을 분석 화면 위에 그린다.
+func (ui *UI) layoutConfirm(gtx C, main layout.Widget) D {
+	return ui.layoutModal(gtx, main, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(material.H6(ui.Theme, tr("Write into existing destinations?")).Layout),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(material.Body1(ui.Theme, ui.ConfirmText).Layout),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{}.Layout(gtx,
+					layout.Flexed(1, layout.Spacer{}.Layout),
+					layout.Rigid(material.Button(ui.Theme, ui.ConfirmBackButton, tr("Back")).Layout),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout),
+					layout.Rigid(material.Button(ui.Theme, ui.ConfirmRunButton, tr("Run")).Layout),
+				)
+			}),
+		)
+	})
+}
+
+// layoutModal은 main을 어둡게 덮고 그 가운데에 card를 그린다.
+// card가 떠 있는 동안에는 아래의 화면을 누를 수 없다.
+func (ui *UI) layoutModal(gtx C, main, card layout.Widget) D {
+	return layout.Stack{}.Layout(gtx,
+		layout.Expanded(func(gtx C) D {
+			return main(gtx.Disabled())
+		}),
+		layout.Expanded(func(gtx C) D {
+			paint.FillShape(gtx.Ops, colors.Scrim, clip.Rect{Max: gtx.Constraints.Min}.Op())
+			return D{Size: gtx.Constraints.Min}
+		}),
+		layout.Expanded(func(gtx C) D {
+			return layout.Center.Layout(gtx, func(gtx C) D {
+				gtx.Constraints.Max.X = gtx.Dp(480)
+				return layout.Background{}.Layout(gtx,
+					func(gtx C) D {
+						paint.FillShape(gtx.Ops, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, clip.Rect{Max: gtx.Constraints.Min}.Op())
+						return D{Size: gtx.Constraints.Min}
+					},
+					func(gtx C) D {
+						return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+							return layout.UniformInset(unit.Dp(12)).Layout(gtx, card)
+						})
+					},
+				)
+			})
+		}),
+	)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// dirCount는 디렉토리 소스 하나를 끝까지 방문해 센 결과이다.
+type dirCount struct {
+	Src string
+	// File이 참이면 디렉토리를 센 것이 아니라 파일 소스 하나의 해시를 비교한 결과로, Dups와 ManifestProblems, Err만 쓴다.
+	File bool
+	// DestDir는 셀 때의 대상 디렉토리로, Existing은 이 디렉토리를 기준으로 센 것이다.
+	DestDir  string
+	Files    int
+	Bytes    int64
+	Stats    srcStats
+	Excluded int
+	// Existing은 대상 경로에 이미 있는 파일의 수이다.
+	Existing int
+	Seqs     []*sequence
+	// Dups는 작업 기록에서 같은 내용이 이미 복사된 것을 찾은 파일들이다.
+	Dups []string
+	// ManifestProblems는 업체의 해시 목록과 맞지 않는 파일들이다.
+	ManifestProblems []string
+	// PathProblems는 대상 경로를 만들 수 없는 파일들로, 많으면 maxPathProblems개까지만 담는다.
+	PathProblems []string
+	// Ignored는 디렉토리 안에서 특수 파일이나 깨진 심볼릭 링크처럼 복사하지 않고 건너뛸 파일들이다.
+	Ignored []ignoredFile
+	Err     error
+}
+
+// maxPathProblems는 디렉토리 소스 하나에서 보여줄 대상 경로 문제의 최대 수이다.
+const maxPathProblems = 20
+
+// countDir는 디렉토리 소스 src 안의 복사할 파일들을 모두 세고 그 크기를 합한다.
+// destDir이 이미 있다면(destExists) 그 안에 이미 있는 파일들도 센다.
+// 분석 화면을 멈추지 않도록 다른 고루틴에서 부를 수 있게 p의 상태는 바꾸지 않고,
+// 세는 동안 UI 고루틴이 바꿀 수 있는 DestDir, DestDirExists 맵도 읽지 않는다.
+// ctx가 취소되면 세는 것을 멈추고 ctx의 에러를 반환한다.
+func (p *Program) countDir(ctx context.Context, src, destDir string, destExists bool) dirCount {
+	// walkSource가 제외하거나 건너뛴 파일들을 기록하는 것들만 따로 쓴다.
+	q := *p
+	q.SrcExcluded = make(map[string]int)
+	q.Ignored = nil
+	q.ignoredSeen = make(map[string]bool)
+	c := dirCount{Src: src, DestDir: destDir}
+	files := make([]string, 0)
+	srcFiles := make([]srcFile, 0)
+	c.Err = q.walkSource(src, func(f srcFile) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		files = append(files, f.Path)
+		srcFiles = append(srcFiles, f)
+		c.Files++
+		if f.Link == "" {
+			if fi, err := os.Stat(f.Real); err == nil {
+				c.Bytes += fi.Size()
+				c.Stats.add(fileStats(f.Path, fi))
+			}
+			if q.manifest != nil {
+				if prob, err := q.manifest.verify(f.Path); err == nil && prob != "" {
+					c.ManifestProblems = append(c.ManifestProblems, prob)
+				}
+			}
+			if q.Ingested != nil {
+				if prior, ok, err := q.Ingested.find(f.Real); err == nil && ok {
+					c.Dups = append(c.Dups, prior.describe(f.Path))
+				}
+			}
+		}
+		return nil
+	})
+	c.Excluded = q.SrcExcluded[src]
+	c.Ignored = q.Ignored
+	if destDir != "" && c.Err == nil {
+		// 복사할 때와 같은 방법으로 대상 경로를 정해, 디렉토리 안 깊은 곳의 파일들까지 대상 경로를 만들 수 있는지 검사하고
+		// 대상 디렉토리가 이미 있다면 이미 있는 파일을 센다.
+		if dests, err := q.destPaths(destDir, srcFiles); err == nil {
+			windows := windowsRules(destDir)
+			bad := 0
+			for _, d := range dests {
+				if ctx.Err() != nil {
+					break
+				}
+				if err := checkDestPath(d, windows, q.MaxPathLen); err != nil {
+					if bad < maxPathProblems {
+						c.PathProblems = append(c.PathProblems, d+" ("+err.Error()+")")
+					}
+					bad++
+				}
+				if !destExists {
+					continue
+				}
+				if _, err := os.Lstat(d); err == nil {
+					c.Existing++
+				}
+			}
+			if bad > maxPathProblems {
+				c.PathProblems = append(c.PathProblems, fmt.Sprintf("... and %d more in %s", bad-maxPathProblems, src))
+			}
+		}
+	}
+	c.Seqs, _ = detectSequences(files)
+	return c
+}
+
+// checkFile은 파일 소스 src를 업체의 해시 목록과 비교하고, history가 참이면 그 내용이 이미 다른 곳으로
+// 복사되었는지 작업 기록의 해시와도 비교한다.
+// 파일 전체의 해시를 구해야 하므로 countDir처럼 다른 고루틴에서 부르며, p의 상태는 바꾸지 않는다.
+func (p *Program) checkFile(ctx context.Context, src string, history bool) dirCount {
+	c := dirCount{Src: src, File: true}
+	if err := ctx.Err(); err != nil {
+		c.Err = err
+		return c
+	}
+	if p.manifest != nil {
+		prob, err := p.manifest.verify(src)
+		if err != nil {
+			c.Err = fmt.Errorf("%w: %s", err, src)
+			return c
+		}
+		if prob != "" {
+			c.ManifestProblems = append(c.ManifestProblems, prob)
+		}
+	}
+	if p.Ingested != nil && history {
+		prior, ok, err := p.Ingested.find(src)
+		if err != nil {
+			c.Err = fmt.Errorf("%w: %s", err, src)
+			return c
+		}
+		if ok {
+			c.Dups = append(c.Dups, prior.describe(src))
+		}
+	}
+	return c
+}
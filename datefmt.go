@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	_ "time/tzdata" // 윈도우즈처럼 시간대 데이터베이스가 없는 시스템에서도 TimeZone을 쓸 수 있게 한다.
+)
+
+// dateTokens는 yyyyMMdd 형식의 날짜 토큰과 그에 해당하는 Go의 레이아웃이다.
+// 긴 토큰부터 바꿔야 yyyy가 yy 두 개로 바뀌지 않는다.
+var dateTokens = []struct{ tok, layout string }{
+	{"yyyy", "2006"},
+	{"yy", "06"},
+	{"MM", "01"},
+	{"dd", "02"},
+	{"HH", "15"},
+	{"mm", "04"},
+	{"ss", "05"},
+}
+
+// dateLayout은 설정된 날짜 형식을 time.Format의 레이아웃으로 바꾼다.
+// 형식은 2006-01-02 같은 Go의 레이아웃이나 yyyyMMdd 같은 형식으로 쓸 수 있다.
+// 비어있다면 기본 형식인 060102를 사용한다.
+func dateLayout(format string) string {
+	format = strings.TrimSpace(format)
+	if format == "" {
+		return "060102"
+	}
+	yyyy := false
+	for _, t := range []string{"yy", "MM", "dd", "HH"} {
+		if strings.Contains(format, t) {
+			yyyy = true
+			break
+		}
+	}
+	if !yyyy {
+		return format
+	}
+	var b strings.Builder
+	for len(format) > 0 {
+		matched := false
+		for _, t := range dateTokens {
+			if strings.HasPrefix(format, t.tok) {
+				b.WriteString(t.layout)
+				format = format[len(t.tok):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(format[0])
+			format = format[1:]
+		}
+	}
+	return b.String()
+}
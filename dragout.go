@@ -0,0 +1,96 @@
+package main
+
+import (
+	"image"
+	"io"
+	"strings"
+
+	"gioui.org/f32"
+	"gioui.org/gesture"
+	"gioui.org/io/clipboard"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// dragHandle은 결과 화면에서 경로를 다른 프로그램으로 끌어내는 손잡이의 상태이다.
+// 끌어내기를 지원하지 않는 플랫폼에서는 대신 경로를 클립보드에 복사하는 버튼이 된다.
+type dragHandle struct {
+	drag  gesture.Drag
+	start f32.Point
+	// started는 이번 누름에서 이미 끌기를 시작했는지를 나타낸다.
+	started bool
+	copy    widget.Clickable
+}
+
+// destDragHandle은 대상 디렉토리 dd를 끌어내는 손잡이이다.
+func (ui *UI) destDragHandle(dd string) *dragHandle {
+	if ui.dragHandles == nil {
+		ui.dragHandles = make(map[string]*dragHandle)
+	}
+	h := ui.dragHandles[dd]
+	if h == nil {
+		h = new(dragHandle)
+		ui.dragHandles[dd] = h
+	}
+	return h
+}
+
+// dragDests는 손잡이를 눌러 조금 움직이면 그 대상 디렉토리를 다른 프로그램으로 끌기 시작한다.
+// 끌어내기를 지원하지 않는다면 버튼을 누를 때 그 경로를 클립보드에 복사한다.
+func (ui *UI) dragDests(gtx C) {
+	for dd, h := range ui.dragHandles {
+		if !dragOutSupported {
+			if h.copy.Clicked(gtx) {
+				gtx.Execute(clipboard.WriteCmd{Type: "application/text", Data: io.NopCloser(strings.NewReader(dd))})
+				ui.Notifier.SetText(tr("path copied to the clipboard"))
+				ui.NotifyIsError = false
+			}
+			continue
+		}
+		for {
+			e, ok := h.drag.Update(gtx.Metric, gtx.Source, gesture.Both)
+			if !ok {
+				break
+			}
+			switch e.Kind {
+			case pointer.Press:
+				h.start = e.Position
+				h.started = false
+			case pointer.Drag:
+				d := e.Position.Sub(h.start)
+				slop := float32(gtx.Dp(unit.Dp(8)))
+				if !h.started && d.X*d.X+d.Y*d.Y > slop*slop {
+					h.started = true
+					go ui.dragOut([]string{dd})
+				}
+			}
+		}
+	}
+}
+
+// layoutDragHandle은 대상 디렉토리 dd를 끌어내는 손잡이를 그린다.
+// 끌어내기를 지원하지 않는 플랫폼에서는 경로를 복사하는 버튼을 그린다.
+func (ui *UI) layoutDragHandle(gtx C, dd string) D {
+	h := ui.destDragHandle(dd)
+	if !dragOutSupported {
+		btn := material.Button(ui.Theme, &h.copy, tr("Copy path"))
+		btn.TextSize = unit.Sp(12)
+		btn.Inset = layout.UniformInset(unit.Dp(6))
+		return btn.Layout(gtx)
+	}
+	return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(2), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+		dims := layout.UniformInset(unit.Dp(6)).Layout(gtx, func(gtx C) D {
+			lbl := material.Body2(ui.Theme, tr("Drag"))
+			lbl.TextSize = unit.Sp(12)
+			return lbl.Layout(gtx)
+		})
+		defer clip.Rect(image.Rectangle{Max: dims.Size}).Push(gtx.Ops).Pop()
+		pointer.CursorGrab.Add(gtx.Ops)
+		h.drag.Add(gtx.Ops)
+		return dims
+	})
+}
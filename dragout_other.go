@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+// dragOutSupported는 이 플랫폼에서 경로를 다른 프로그램으로 끌어다 놓을 수 있는지를 나타낸다.
+// Gio의 끌어다 놓기는 창 안에서만 동작하므로 윈도우즈 외에서는 지원하지 않고,
+// 손잡이 대신 경로를 클립보드에 복사하는 버튼을 보여준다.
+const dragOutSupported = false
+
+// dragOut은 지원하지 않는 플랫폼에서 불리지 않는다.
+func (ui *UI) dragOut(paths []string) {}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	procSHParseDisplayName = shell32.NewProc("SHParseDisplayName")
+	procSHCreateDataObject = shell32.NewProc("SHCreateDataObject")
+	procSHDoDragDrop       = shell32.NewProc("SHDoDragDrop")
+	procILFree             = shell32.NewProc("ILFree")
+	procOleInitialize      = ole32.NewProc("OleInitialize")
+)
+
+const (
+	iidDataObject  = "{0000010E-0000-0000-C000-000000000046}"
+	dropEffectCopy = 1
+	dropEffectLink = 4
+)
+
+// dragOutSupported는 이 플랫폼에서 경로를 다른 프로그램으로 끌어다 놓을 수 있는지를 나타낸다.
+const dragOutSupported = true
+
+// dragOut은 paths를 탐색기에서 끌어온 파일처럼 다른 프로그램에 놓을 수 있도록 끌기를 시작한다.
+// 셸이 만든 데이터 객체(CF_HDROP 등)와 기본 드롭 소스를 쓴다.
+// 놓거나 취소할 때까지 창 스레드에서 기다리므로 다른 고루틴에서 불러야 한다.
+func (ui *UI) dragOut(paths []string) {
+	hwnd := nativeWindowHandle()
+	if hwnd == 0 || len(paths) == 0 {
+		return
+	}
+	ui.Window.Run(func() {
+		// 끌어다 놓기는 OLE가 초기화된 스레드에서만 된다. 이미 초기화되어 있다면 아무것도 하지 않는다.
+		procOleInitialize.Call(0)
+		pidls := make([]uintptr, 0, len(paths))
+		defer func() {
+			for _, pidl := range pidls {
+				procILFree.Call(pidl)
+			}
+		}()
+		for _, path := range paths {
+			p, err := windows.UTF16PtrFromString(path)
+			if err != nil {
+				return
+			}
+			var pidl uintptr
+			hr, _, _ := procSHParseDisplayName.Call(uintptr(unsafe.Pointer(p)), 0, uintptr(unsafe.Pointer(&pidl)), 0, 0)
+			if hr != 0 {
+				return
+			}
+			pidls = append(pidls, pidl)
+		}
+		iid, err := windows.GUIDFromString(iidDataObject)
+		if err != nil {
+			return
+		}
+		var obj unsafe.Pointer
+		hr, _, _ := procSHCreateDataObject.Call(0, uintptr(len(pidls)), uintptr(unsafe.Pointer(&pidls[0])), 0, uintptr(unsafe.Pointer(&iid)), uintptr(unsafe.Pointer(&obj)))
+		if hr != 0 {
+			return
+		}
+		defer comCall(obj, vtRelease)
+		var effect uint32
+		procSHDoDragDrop.Call(hwnd, uintptr(obj), 0, dropEffectCopy|dropEffectLink, uintptr(unsafe.Pointer(&effect)))
+	})
+}
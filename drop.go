@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"gioui.org/widget"
+)
+
+// takeDrops는 창에 끌어다 놓은 파일과 폴더의 경로들을 입력 에디터 끝에 한 줄씩 붙이고,
+// 붙인 경로가 있다면 참을 반환한다.
+func (ui *UI) takeDrops() bool {
+	added := false
+	for {
+		select {
+		case paths := <-ui.drops:
+			appendLines(ui.InputEditor, paths)
+			added = true
+		default:
+			return added
+		}
+	}
+}
+
+// appendLines는 에디터의 마지막 줄 뒤에 lines를 붙이고 커서를 끝으로 옮긴다.
+func appendLines(ed *widget.Editor, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	text := ed.Text()
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	text += strings.Join(lines, "\n") + "\n"
+	ed.SetText(text)
+	n := utf8.RuneCountInString(text)
+	ed.SetCaret(n, n)
+}
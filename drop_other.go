@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import "gioui.org/io/event"
+
+// inputHint는 입력 칸이 비어있을 때 보여주는 힌트이다.
+// 끌어다 놓을 수 없으므로 붙여넣기와 파일 선택을 안내한다.
+const inputHint = "paths to copy, paste them or use Add files…"
+
+// acceptDrops는 창이 파일 끌어다 놓기를 받도록 한다.
+// Gio는 윈도우즈 외의 플랫폼에서 파일 끌어다 놓기를 지원하지 않으므로 아무것도 하지 않고,
+// 대신 inputHint로 붙여넣기를 안내한다.
+func (ui *UI) acceptDrops(e event.Event) {}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"gioui.org/app"
+	"gioui.org/io/event"
+	"golang.org/x/sys/windows"
+)
+
+var (
+	shell32               = windows.NewLazySystemDLL("shell32.dll")
+	comctl32              = windows.NewLazySystemDLL("comctl32.dll")
+	procDragAcceptFiles   = shell32.NewProc("DragAcceptFiles")
+	procDragQueryFileW    = shell32.NewProc("DragQueryFileW")
+	procDragFinish        = shell32.NewProc("DragFinish")
+	procSetWindowSubclass = comctl32.NewProc("SetWindowSubclass")
+	procDefSubclassProc   = comctl32.NewProc("DefSubclassProc")
+)
+
+const (
+	wmDropFiles = 0x0233
+	// dropSubclassID는 창 프로시저를 가로챌 때 쓰는 서브클래스 식별자이다.
+	dropSubclassID = 0x74616b65
+)
+
+// inputHint는 입력 칸이 비어있을 때 보여주는 힌트이다.
+const inputHint = "paths to copy, paste or drop them here"
+
+// dropProcCallback은 창 메시지를 받는 콜백이다. 콜백은 만들 수 있는 수가 제한되어 있으므로 한번만 만든다.
+var dropProcCallback = syscall.NewCallback(dropProc)
+
+// nativeWindow는 네이티브 창과 창 스레드에서 받은 경로들을 전달할 UI이다.
+var nativeWindow struct {
+	sync.Mutex
+	ui   *UI
+	hwnd uintptr
+}
+
+// nativeWindowHandle은 네이티브 창의 핸들이다. 창이 아직 없다면 0이다.
+func nativeWindowHandle() uintptr {
+	nativeWindow.Lock()
+	defer nativeWindow.Unlock()
+	return nativeWindow.hwnd
+}
+
+// acceptDrops는 네이티브 창이 만들어지면 WM_DROPFILES를 받도록 창 프로시저를 서브클래싱한다.
+// Gio는 파일 끌어다 놓기를 직접 지원하지 않으므로 Win32 창에 직접 등록한다.
+func (ui *UI) acceptDrops(e event.Event) {
+	ve, ok := e.(app.Win32ViewEvent)
+	if !ok {
+		return
+	}
+	if ve.HWND == 0 {
+		// 창이 닫혔다.
+		nativeWindow.Lock()
+		nativeWindow.hwnd = 0
+		nativeWindow.Unlock()
+		return
+	}
+	hwnd := ve.HWND
+	nativeWindow.Lock()
+	nativeWindow.ui = ui
+	nativeWindow.hwnd = hwnd
+	nativeWindow.Unlock()
+	// 창 프로시저는 창 스레드에서만 바꿀 수 있다.
+	// 창 스레드가 이벤트를 넘겨주는 동안 기다리지 않도록 다른 고루틴에서 요청한다.
+	go ui.Window.Run(func() {
+		procSetWindowSubclass.Call(hwnd, dropProcCallback, dropSubclassID, 0)
+		procDragAcceptFiles.Call(hwnd, 1)
+	})
+}
+
+func dropProc(hwnd, msg, wParam, lParam, id, data uintptr) uintptr {
+	if msg == wmWindowPosChanged {
+		// 창을 닫을 때 저장할 수 있도록 창의 위치를 기록해둔다.
+		rememberPosition(hwnd)
+	}
+	if msg != wmDropFiles {
+		r, _, _ := procDefSubclassProc.Call(hwnd, msg, wParam, lParam)
+		return r
+	}
+	hdrop := wParam
+	n, _, _ := procDragQueryFileW.Call(hdrop, 0xFFFFFFFF, 0, 0)
+	paths := make([]string, 0, n)
+	for i := uintptr(0); i < n; i++ {
+		size, _, _ := procDragQueryFileW.Call(hdrop, i, 0, 0)
+		buf := make([]uint16, size+1)
+		procDragQueryFileW.Call(hdrop, i, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+		paths = append(paths, windows.UTF16ToString(buf))
+	}
+	procDragFinish.Call(hdrop)
+	nativeWindow.Lock()
+	ui := nativeWindow.ui
+	nativeWindow.Unlock()
+	if ui != nil && len(paths) != 0 {
+		select {
+		case ui.drops <- paths:
+		default:
+		}
+		ui.Window.Invalidate()
+	}
+	return 0
+}
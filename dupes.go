@@ -0,0 +1,85 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"time"
+)
+
+// hashFile은 파일 내용의 SHA-256 해시를 16진수로 반환한다.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// priorIngest는 작업 기록에 남은 이전에 복사된 파일 하나와 그 작업의 시간이다.
+type priorIngest struct {
+	IngestFile
+	Time time.Time
+}
+
+// contentIndex는 작업 기록에서 해시가 남은 파일들을 크기별로 모은 것이다.
+// 크기가 같은 파일만 해시를 계산해 비교하면 되므로 대부분의 소스는 읽지 않아도 된다.
+type contentIndex map[int64][]priorIngest
+
+// newContentIndex는 작업 기록들에서 해시가 남은 파일들을 모은다.
+func newContentIndex(recs []*IngestRecord) contentIndex {
+	idx := make(contentIndex)
+	for _, rec := range recs {
+		for _, f := range rec.Files {
+			if f.Hash == "" {
+				continue
+			}
+			idx[f.Size] = append(idx[f.Size], priorIngest{IngestFile: f, Time: rec.Time})
+		}
+	}
+	return idx
+}
+
+// find는 path와 같은 내용으로 이전에 복사된 파일을 찾는다. 가장 최근의 것을 반환한다.
+// path 자신이 이전에 복사되었거나 목록에 기록된 파일이라면 다른 곳에 있는 것이 아니므로 무시한다.
+func (idx contentIndex) find(path string) (priorIngest, bool, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return priorIngest{}, false, err
+	}
+	priors := idx[fi.Size()]
+	if len(priors) == 0 {
+		return priorIngest{}, false, nil
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		return priorIngest{}, false, err
+	}
+	var found priorIngest
+	ok := false
+	for _, prior := range priors {
+		if prior.Hash != hash || prior.Dest == path || (prior.Dest == "" && prior.Src == path) {
+			continue
+		}
+		if !ok || prior.Time.After(found.Time) {
+			found = prior
+			ok = true
+		}
+	}
+	return found, ok, nil
+}
+
+// describe는 path가 이미 복사된 곳을 분석 결과에 보여줄 형식으로 나타낸다.
+// 목록만 만든 기록이라면 대상 경로 대신 목록을 만든 소스 경로를 보여준다.
+func (prior priorIngest) describe(path string) string {
+	if prior.Dest == "" {
+		return path + " was cataloged as " + prior.Src + " on " + prior.Time.Local().Format("2006-01-02 15:04")
+	}
+	return path + " was ingested as " + prior.Dest + " on " + prior.Time.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// errorKind는 흔한 파일시스템 에러의 종류로, 사용자에게 해결 방법을 알려주기 위해 쓴다.
+type errorKind int
+
+const (
+	errOther errorKind = iota
+	errPermission
+	errNoSpace
+	errReadOnly
+	errStale
+	errCrossDevice
+	errHungMount
+)
+
+// errorHints는 에러의 종류별로 사용자가 할 수 있는 일이다.
+var errorHints = map[errorKind]string{
+	errPermission:  "check that you can read the source and write to the destination",
+	errNoSpace:     "free up space at the destination or split the batch",
+	errReadOnly:    "the destination is mounted read-only, pick another destination or remount it writable",
+	errStale:       "the network share was remounted or went away, remount it and analyze again",
+	errCrossDevice: "hardlinks can't cross filesystems, use the copy method",
+	errHungMount:   "the mount isn't responding, check the network share or raise StatTimeout",
+}
+
+// classifyError는 err의 종류를 찾는다. 운영체제별 에러 번호는 errnoKind가 확인한다.
+func classifyError(err error) errorKind {
+	var stale *staleMountError
+	if errors.As(err, &stale) {
+		return errHungMount
+	}
+	if kind := errnoKind(err); kind != errOther {
+		return kind
+	}
+	if errors.Is(err, fs.ErrPermission) {
+		return errPermission
+	}
+	return errOther
+}
+
+// explainError는 알림에 보여줄 에러 메시지로, 흔한 에러라면 해결 방법을 덧붙인다.
+func explainError(err error) string {
+	if hint, ok := errorHints[classifyError(err)]; ok {
+		return err.Error() + " (" + tr(hint) + ")"
+	}
+	return err.Error()
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errnoKind는 유닉스 계열 시스템의 에러 번호로 에러의 종류를 찾는다.
+func errnoKind(err error) errorKind {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return errOther
+	}
+	switch errno {
+	case syscall.EACCES, syscall.EPERM:
+		return errPermission
+	case syscall.ENOSPC, syscall.EDQUOT:
+		return errNoSpace
+	case syscall.EROFS:
+		return errReadOnly
+	case syscall.ESTALE:
+		return errStale
+	case syscall.EXDEV:
+		return errCrossDevice
+	}
+	return errOther
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// errnoKind는 윈도우즈의 에러 코드로 에러의 종류를 찾는다.
+func errnoKind(err error) errorKind {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return errOther
+	}
+	switch errno {
+	case windows.ERROR_ACCESS_DENIED:
+		return errPermission
+	case windows.ERROR_DISK_FULL, windows.ERROR_HANDLE_DISK_FULL:
+		return errNoSpace
+	case windows.ERROR_WRITE_PROTECT:
+		return errReadOnly
+	case windows.ERROR_NETNAME_DELETED, windows.ERROR_BAD_NETPATH, windows.ERROR_UNEXP_NET_ERR:
+		return errStale
+	case windows.ERROR_NOT_SAME_DEVICE:
+		return errCrossDevice
+	}
+	return errOther
+}
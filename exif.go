@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// exifExts는 EXIF 촬영 날짜를 읽을 사진 파일의 확장자들이다.
+// RAW 파일들은 TIFF 구조를 가진 것들만 읽을 수 있다.
+var exifExts = map[string]bool{
+	"jpg": true, "jpeg": true, "tif": true, "tiff": true, "dng": true,
+	"cr2": true, "nef": true, "nrw": true, "arw": true, "orf": true, "pef": true, "srw": true,
+}
+
+// errNoExif는 파일에서 EXIF 날짜를 찾지 못했음을 뜻한다.
+var errNoExif = errors.New("no exif date")
+
+const (
+	exifTagDateTime         = 0x0132
+	exifTagExifIFD          = 0x8769
+	exifTagDateTimeOriginal = 0x9003
+)
+
+// isExifPhoto는 path가 EXIF 촬영 날짜를 읽을 사진 파일인지 확인한다.
+func isExifPhoto(path string) bool {
+	name := baseName(path)
+	i := strings.LastIndex(name, ".")
+	if i <= 0 {
+		return false
+	}
+	return exifExts[strings.ToLower(name[i+1:])]
+}
+
+// readExifDate는 사진 파일의 촬영 시각을 읽는다.
+// DateTimeOriginal이 없으면 DateTime을 쓰고, 둘 다 없다면 errNoExif를 반환한다.
+// EXIF 날짜에는 시간대가 없으므로 loc의 시각으로 본다.
+func readExifDate(path string, loc *time.Location) (time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+	base, err := exifTIFFOffset(f)
+	if err != nil {
+		return time.Time{}, err
+	}
+	tr := io.NewSectionReader(f, base, 1<<62)
+	var bo binary.ByteOrder
+	var head [8]byte
+	if _, err := tr.ReadAt(head[:], 0); err != nil {
+		return time.Time{}, errNoExif
+	}
+	switch string(head[:2]) {
+	case "II":
+		bo = binary.LittleEndian
+	case "MM":
+		bo = binary.BigEndian
+	default:
+		return time.Time{}, errNoExif
+	}
+	ifd0 := int64(bo.Uint32(head[4:]))
+	tags, err := readIFD(tr, bo, ifd0)
+	if err != nil {
+		return time.Time{}, errNoExif
+	}
+	date := ""
+	if off, ok := tags[exifTagExifIFD]; ok {
+		if sub, err := readIFD(tr, bo, int64(bo.Uint32(off.value[:]))); err == nil {
+			if t, ok := sub[exifTagDateTimeOriginal]; ok {
+				date = t.ascii(tr, bo)
+			}
+		}
+	}
+	if date == "" {
+		if t, ok := tags[exifTagDateTime]; ok {
+			date = t.ascii(tr, bo)
+		}
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	t, err := time.ParseInLocation("2006:01:02 15:04:05", date, loc)
+	if err != nil {
+		return time.Time{}, errNoExif
+	}
+	return t, nil
+}
+
+// exifTIFFOffset은 파일 안에서 EXIF의 TIFF 구조가 시작되는 위치를 찾는다.
+// JPEG는 APP1 세그먼트 안에, TIFF 기반의 파일은 파일 처음에 있다.
+func exifTIFFOffset(r io.ReaderAt) (int64, error) {
+	var head [4]byte
+	if _, err := r.ReadAt(head[:], 0); err != nil {
+		return 0, errNoExif
+	}
+	if string(head[:2]) == "II" || string(head[:2]) == "MM" {
+		return 0, nil
+	}
+	if head[0] != 0xFF || head[1] != 0xD8 {
+		return 0, errNoExif
+	}
+	off := int64(2)
+	for {
+		var seg [10]byte
+		if _, err := r.ReadAt(seg[:4], off); err != nil {
+			return 0, errNoExif
+		}
+		if seg[0] != 0xFF {
+			return 0, errNoExif
+		}
+		marker := seg[1]
+		size := int64(binary.BigEndian.Uint16(seg[2:4]))
+		// SOS 이후로는 이미지 데이터이다.
+		if marker == 0xDA || marker == 0xD9 {
+			return 0, errNoExif
+		}
+		if marker == 0xE1 {
+			if _, err := r.ReadAt(seg[4:10], off+4); err == nil && bytes.Equal(seg[4:10], []byte("Exif\x00\x00")) {
+				return off + 10, nil
+			}
+		}
+		off += 2 + size
+	}
+}
+
+// ifdEntry는 TIFF IFD 항목 하나이다.
+type ifdEntry struct {
+	typ   uint16
+	count uint32
+	value [4]byte
+}
+
+// ascii는 ASCII 형식 항목의 값을 읽는다.
+func (e ifdEntry) ascii(r io.ReaderAt, bo binary.ByteOrder) string {
+	if e.typ != 2 || e.count == 0 || e.count > 256 {
+		return ""
+	}
+	b := e.value[:]
+	if e.count > 4 {
+		b = make([]byte, e.count)
+		if _, err := r.ReadAt(b, int64(bo.Uint32(e.value[:]))); err != nil {
+			return ""
+		}
+	} else {
+		b = b[:e.count]
+	}
+	return strings.TrimRight(string(b), "\x00 ")
+}
+
+// readIFD는 off 위치의 IFD 항목들을 태그별로 읽는다.
+func readIFD(r io.ReaderAt, bo binary.ByteOrder, off int64) (map[uint16]ifdEntry, error) {
+	var n [2]byte
+	if _, err := r.ReadAt(n[:], off); err != nil {
+		return nil, err
+	}
+	count := int(bo.Uint16(n[:]))
+	buf := make([]byte, count*12)
+	if _, err := r.ReadAt(buf, off+2); err != nil {
+		return nil, err
+	}
+	tags := make(map[uint16]ifdEntry, count)
+	for i := 0; i < count; i++ {
+		b := buf[i*12:]
+		e := ifdEntry{typ: bo.Uint16(b[2:]), count: bo.Uint32(b[4:])}
+		copy(e.value[:], b[8:12])
+		tags[bo.Uint16(b)] = e
+	}
+	return tags, nil
+}
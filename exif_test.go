@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// tiffWithDates는 IFD0에 DateTime을, original이 있다면 Exif IFD에 DateTimeOriginal을 가진
+// 리틀 엔디안 TIFF 구조를 만든다.
+func tiffWithDates(dateTime, original string) []byte {
+	bo := binary.LittleEndian
+	var b bytes.Buffer
+	b.WriteString("II")
+	binary.Write(&b, bo, uint16(42))
+	binary.Write(&b, bo, uint32(8))
+	// IFD0: DateTime과 Exif IFD 위치, 다음 IFD 없음
+	ifd0 := 8
+	exifIFD := ifd0 + 2 + 2*12 + 4
+	dates := exifIFD + 2 + 12 + 4
+	binary.Write(&b, bo, uint16(2))
+	entry := func(tag, typ uint16, count, value uint32) {
+		binary.Write(&b, bo, tag)
+		binary.Write(&b, bo, typ)
+		binary.Write(&b, bo, count)
+		binary.Write(&b, bo, value)
+	}
+	entry(exifTagDateTime, 2, 20, uint32(dates))
+	entry(exifTagExifIFD, 4, 1, uint32(exifIFD))
+	binary.Write(&b, bo, uint32(0))
+	typ := uint16(2)
+	if original == "" {
+		// ASCII가 아닌 항목은 날짜로 읽지 않는다.
+		typ = 3
+	}
+	binary.Write(&b, bo, uint16(1))
+	entry(exifTagDateTimeOriginal, typ, 20, uint32(dates+20))
+	binary.Write(&b, bo, uint32(0))
+	b.WriteString(dateTime + "\x00")
+	b.WriteString(original + "\x00")
+	return b.Bytes()
+}
+
+// jpegWithExif는 APP1 세그먼트에 tiff를 가진 JPEG 파일의 앞부분을 만든다.
+func jpegWithExif(tiff []byte) []byte {
+	var b bytes.Buffer
+	b.Write([]byte{0xFF, 0xD8})
+	// EXIF가 아닌 APP0 세그먼트는 건너뛴다.
+	b.Write([]byte{0xFF, 0xE0, 0x00, 0x04, 'J', 'F'})
+	b.Write([]byte{0xFF, 0xE1})
+	binary.Write(&b, binary.BigEndian, uint16(2+6+len(tiff)))
+	b.WriteString("Exif\x00\x00")
+	b.Write(tiff)
+	b.Write([]byte{0xFF, 0xDA})
+	return b.Bytes()
+}
+
+func TestReadExifDate(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		name string
+		data []byte
+		want string
+		err  error
+	}{
+		{"a.jpg", jpegWithExif(tiffWithDates("2024:05:01 10:00:00", "2024:04:30 09:15:30")), "2024-04-30 09:15:30", nil},
+		{"b.tif", tiffWithDates("2024:05:01 10:00:00", "2024:04:30 09:15:30"), "2024-04-30 09:15:30", nil},
+		{"c.dng", tiffWithDates("2024:05:01 10:00:00", ""), "2024-05-01 10:00:00", nil},
+		{"d.jpg", []byte{0xFF, 0xD8, 0xFF, 0xDA, 0, 2}, "", errNoExif},
+		{"e.jpg", []byte("not a photo"), "", errNoExif},
+	}
+	for _, c := range cases {
+		path := filepath.Join(dir, c.name)
+		if err := os.WriteFile(path, c.data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := readExifDate(path, time.UTC)
+		if c.err != nil {
+			if !errors.Is(err, c.err) {
+				t.Errorf("readExifDate(%s) error = %v, want %v", c.name, err, c.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("readExifDate(%s): %v", c.name, err)
+			continue
+		}
+		if s := got.Format("2006-01-02 15:04:05"); s != c.want {
+			t.Errorf("readExifDate(%s) = %s, want %s", c.name, s, c.want)
+		}
+	}
+}
+
+func TestIsExifPhoto(t *testing.T) {
+	cases := map[string]bool{
+		"/card/DCIM/IMG_0001.JPG": true,
+		"/card/raw/A001.cr2":      true,
+		"/show/plate.1001.exr":    false,
+		"/show/.jpg":              false,
+		"/show/README":            false,
+	}
+	for path, want := range cases {
+		if got := isExifPhoto(path); got != want {
+			t.Errorf("isExifPhoto(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// reportRow는 내보내는 보고서의 한 줄로, 소스 하나와 그 결과이다.
+type reportRow struct {
+	Src    string `json:"src"`
+	Dest   string `json:"dest"`
+	Status string `json:"status"`
+	Size   int64  `json:"size,omitempty"`
+	Hash   string `json:"hash,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// report는 내보내는 보고서 전체이다.
+type report struct {
+	// Summary는 복사 작업의 통계로, 복사하기 전에는 nil이다.
+	Summary *runStats   `json:"summary,omitempty"`
+	Files   []reportRow `json:"files"`
+}
+
+// report는 분석 결과나 복사 결과로 보고서를 만든다.
+func (p *Program) report() report {
+	r := report{Files: p.reportRows()}
+	if len(p.Outcomes) != 0 {
+		st := p.runStats()
+		r.Summary = &st
+	}
+	return r
+}
+
+// reportRows는 분석 결과나 복사 결과를 보고서의 줄들로 만든다.
+// 복사하기 전에는 소스마다, 복사한 뒤에는 처리한 파일마다 한 줄이다.
+func (p *Program) reportRows() []reportRow {
+	rows := make([]reportRow, 0)
+	if len(p.Outcomes) != 0 {
+		copied := make(map[string]IngestFile)
+		for _, f := range p.Copied {
+			copied[f.Dest] = f
+		}
+		for _, o := range p.Outcomes {
+			f := copied[o.Dest]
+			rows = append(rows, reportRow{Src: o.Src, Dest: o.Dest, Status: o.Status, Size: f.Size, Hash: f.Hash, Error: o.Err})
+		}
+		return rows
+	}
+	if p.Done {
+		status := map[string]string{
+			"copy":    "copied",
+			"catalog": "cataloged",
+		}[p.Method]
+		if status == "" {
+			status = "linked"
+		}
+		for _, f := range p.Copied {
+			rows = append(rows, reportRow{Src: f.Src, Dest: f.Dest, Status: status, Size: f.Size, Hash: f.Hash})
+		}
+		for _, d := range p.Linked {
+			rows = append(rows, reportRow{Dest: d, Status: "already linked"})
+		}
+		return rows
+	}
+	for _, src := range p.plannedSources() {
+		dest := p.DestDir[src]
+		if !p.SrcIsDir[src] {
+			dest = filepath.Join(dest, filepath.Base(src))
+		}
+		status := "planned"
+		switch {
+		case p.SrcSkipped[src]:
+			status = "left out"
+		case p.SrcLinked[src]:
+			status = "already linked"
+		case p.SrcDestExists[src]:
+			status = "exists"
+		}
+		rows = append(rows, reportRow{Src: src, Dest: dest, Status: status, Size: p.SrcBytes[src]})
+	}
+	return rows
+}
+
+// exportReport는 보고서를 설정 디렉토리 아래 reports 디렉토리에 CSV와 JSON으로 저장하고,
+// 확장자를 뺀 파일 경로를 반환한다. 스프레드시트나 데이터베이스로 가져가기 위함이다.
+// 통계가 있다면 CSV에서는 -summary.csv 파일에 따로 저장한다.
+func exportReport(rep report, t time.Time) (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "reports")
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	base := filepath.Join(dir, "report-"+t.Format("20060102-150405"))
+	data, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(base+".json", append(data, '\n'), 0644)
+	if err != nil {
+		return "", err
+	}
+	records := [][]string{{"src", "dest", "status", "size", "hash", "error"}}
+	for _, r := range rep.Files {
+		records = append(records, []string{r.Src, r.Dest, r.Status, strconv.FormatInt(r.Size, 10), r.Hash, r.Error})
+	}
+	err = writeCSV(base+".csv", records)
+	if err != nil {
+		return "", err
+	}
+	if st := rep.Summary; st != nil {
+		err = writeCSV(base+"-summary.csv", [][]string{
+			{"name", "value"},
+			{"elapsed_seconds", strconv.FormatFloat(st.Elapsed.Seconds(), 'f', 1, 64)},
+			{"bytes_written", strconv.FormatInt(st.Bytes, 10)},
+			{"bytes_per_second", strconv.FormatFloat(st.Rate(), 'f', 0, 64)},
+			{"copied", strconv.Itoa(st.Copied)},
+			{"linked", strconv.Itoa(st.Linked)},
+			{"skipped", strconv.Itoa(st.Skipped)},
+			{"failed", strconv.Itoa(st.Failed)},
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return base, nil
+}
+
+// writeCSV는 records를 CSV 파일 path로 저장한다.
+func writeCSV(path string, records [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	w.WriteAll(records)
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
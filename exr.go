@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// exrMagic은 OpenEXR 파일의 처음 4바이트이다.
+const exrMagic = 20000630
+
+// exrMaxAttrSize보다 큰 속성 값은 읽지 않고 건너뛴다.
+const exrMaxAttrSize = 1 << 20
+
+// defaultExrAttrs는 설정이 없을 때 변수로 만들 EXR 헤더 속성들이다.
+var defaultExrAttrs = []string{"owner", "comments", "dataWindow"}
+
+// isExr는 path가 OpenEXR 파일의 이름인지 확인한다.
+func isExr(path string) bool {
+	return strings.HasSuffix(strings.ToLower(baseName(path)), ".exr")
+}
+
+// exrVar는 EXR 헤더 속성 이름을 대상 경로 패턴의 변수 이름으로 바꾼다.
+// 예) dataWindow → EXR_DATAWINDOW
+func exrVar(attr string) string {
+	return "EXR_" + strings.ToUpper(attr)
+}
+
+// readExrHeader는 OpenEXR 파일의 (첫 파트의) 헤더에서 attrs 속성들을 읽어 변수들로 만든다.
+// 헤더에 없거나 값을 글자로 나타낼 수 없는 속성은 결과에 포함하지 않는다.
+//
+// box2i 속성(dataWindow, displayWindow)은 2048x1080 처럼 크기로 나타낸다.
+func readExrHeader(path string, attrs []string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	var head [2]uint32
+	if err := binary.Read(r, binary.LittleEndian, &head); err != nil {
+		return nil, fmt.Errorf("invalid exr header: %s", path)
+	}
+	if head[0] != exrMagic {
+		return nil, fmt.Errorf("not an exr file: %s", path)
+	}
+	want := make(map[string]bool)
+	for _, a := range attrs {
+		want[a] = true
+	}
+	meta := make(map[string]string)
+	for {
+		name, err := r.ReadString(0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exr header: %s", path)
+		}
+		name = strings.TrimSuffix(name, "\x00")
+		if name == "" {
+			// 헤더의 끝
+			return meta, nil
+		}
+		typ, err := r.ReadString(0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exr header: %s", path)
+		}
+		typ = strings.TrimSuffix(typ, "\x00")
+		var size int32
+		if err := binary.Read(r, binary.LittleEndian, &size); err != nil || size < 0 {
+			return nil, fmt.Errorf("invalid exr header: %s", path)
+		}
+		if !want[name] || size > exrMaxAttrSize {
+			if _, err := r.Discard(int(size)); err != nil {
+				return nil, fmt.Errorf("invalid exr header: %s", path)
+			}
+			continue
+		}
+		val := make([]byte, size)
+		if _, err := io.ReadFull(r, val); err != nil {
+			return nil, fmt.Errorf("invalid exr header: %s", path)
+		}
+		if v, ok := exrValue(typ, val); ok {
+			meta[exrVar(name)] = v
+		}
+	}
+}
+
+// exrValue는 EXR 헤더 속성 값을 글자로 나타낸다.
+func exrValue(typ string, val []byte) (string, bool) {
+	le := binary.LittleEndian
+	i32 := func(i int) int32 { return int32(le.Uint32(val[i*4:])) }
+	f32 := func(i int) float64 { return float64(math.Float32frombits(le.Uint32(val[i*4:]))) }
+	fmtFloat := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 32) }
+	switch typ {
+	case "string":
+		return strings.TrimSpace(string(val)), true
+	case "int":
+		if len(val) == 4 {
+			return strconv.Itoa(int(i32(0))), true
+		}
+	case "float":
+		if len(val) == 4 {
+			return fmtFloat(f32(0)), true
+		}
+	case "double":
+		if len(val) == 8 {
+			return strconv.FormatFloat(math.Float64frombits(le.Uint64(val)), 'f', -1, 64), true
+		}
+	case "box2i":
+		if len(val) == 16 {
+			w := i32(2) - i32(0) + 1
+			h := i32(3) - i32(1) + 1
+			return strconv.Itoa(int(w)) + "x" + strconv.Itoa(int(h)), true
+		}
+	case "v2i":
+		if len(val) == 8 {
+			return strconv.Itoa(int(i32(0))) + "," + strconv.Itoa(int(i32(1))), true
+		}
+	case "v2f":
+		if len(val) == 8 {
+			return fmtFloat(f32(0)) + "," + fmtFloat(f32(1)), true
+		}
+	case "rational":
+		if len(val) == 8 {
+			n, d := i32(0), int64(le.Uint32(val[4:]))
+			if d == 0 {
+				return "", false
+			}
+			return strconv.FormatFloat(math.Round(float64(n)/float64(d)*1000)/1000, 'f', -1, 64), true
+		}
+	}
+	return "", false
+}
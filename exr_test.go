@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// exrAttr는 시험용 EXR 헤더에 쓸 속성 하나이다.
+type exrAttr struct {
+	name, typ string
+	val       []byte
+}
+
+// writeExr는 attrs를 헤더로 가진 EXR 파일을 만든다. 헤더 뒤의 이미지 데이터는 없다.
+func writeExr(t *testing.T, attrs []exrAttr) string {
+	t.Helper()
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, []uint32{exrMagic, 2})
+	for _, a := range attrs {
+		b.WriteString(a.name + "\x00" + a.typ + "\x00")
+		binary.Write(&b, binary.LittleEndian, int32(len(a.val)))
+		b.Write(a.val)
+	}
+	b.WriteByte(0)
+	path := filepath.Join(t.TempDir(), "plate.1001.exr")
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func le32(vs ...int32) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, vs)
+	return b.Bytes()
+}
+
+func TestReadExrHeader(t *testing.T) {
+	path := writeExr(t, []exrAttr{
+		{"owner", "string", []byte("stormfx ")},
+		{"channels", "chlist", make([]byte, 40)},
+		{"dataWindow", "box2i", le32(0, 0, 2047, 1079)},
+		{"framesPerSecond", "rational", le32(24000, 1001)},
+		{"pixelAspectRatio", "float", le32(int32(math.Float32bits(1)))},
+	})
+	got, err := readExrHeader(path, []string{"owner", "dataWindow", "framesPerSecond", "pixelAspectRatio", "comments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"EXR_OWNER":            "stormfx",
+		"EXR_DATAWINDOW":       "2048x1080",
+		"EXR_FRAMESPERSECOND":  "23.976",
+		"EXR_PIXELASPECTRATIO": "1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readExrHeader = %v, want %v", got, want)
+	}
+}
+
+func TestReadExrHeaderInvalid(t *testing.T) {
+	dir := t.TempDir()
+	notExr := filepath.Join(dir, "a.exr")
+	os.WriteFile(notExr, []byte("not an exr file at all"), 0644)
+	short := filepath.Join(dir, "b.exr")
+	os.WriteFile(short, []byte{0x76, 0x2f}, 0644)
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, []uint32{exrMagic, 2})
+	b.WriteString("owner\x00string\x00")
+	cut := filepath.Join(dir, "c.exr")
+	os.WriteFile(cut, b.Bytes(), 0644)
+	for _, path := range []string{notExr, short, cut} {
+		if _, err := readExrHeader(path, defaultExrAttrs); err == nil {
+			t.Errorf("readExrHeader(%s): want error", filepath.Base(path))
+		}
+	}
+}
+
+func TestExrValue(t *testing.T) {
+	cases := []struct {
+		typ  string
+		val  []byte
+		want string
+		ok   bool
+	}{
+		{"int", le32(-3), "-3", true},
+		{"v2i", le32(1920, 1080), "1920,1080", true},
+		{"rational", le32(24, 0), "", false},
+		{"box2i", le32(0, 0), "", false},
+		{"chlist", nil, "", false},
+	}
+	for _, c := range cases {
+		got, ok := exrValue(c.typ, c.val)
+		if got != c.want || ok != c.ok {
+			t.Errorf("exrValue(%s) = %q, %v, want %q, %v", c.typ, got, ok, c.want, c.ok)
+		}
+	}
+}
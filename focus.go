@@ -0,0 +1,78 @@
+package main
+
+import (
+	"gioui.org/io/event"
+	"gioui.org/io/key"
+)
+
+// focusOrder는 Tab으로 포커스를 옮길 에디터와 버튼들의 순서이다.
+// 키 → 구분자 → 입력 → 대상 경로 → 버튼 순서로, 지금 화면에 보이고 쓸 수 있는 것들만 넣는다.
+func (ui *UI) focusOrder() []event.Tag {
+	if ui.ConfirmText != "" {
+		return []event.Tag{ui.ConfirmBackButton, ui.ConfirmRunButton}
+	}
+	p := ui.Program
+	order := make([]event.Tag, 0)
+	if !ui.Locked() {
+		if ui.ParseRadio.Value == "regex" {
+			order = append(order, ui.PathRegexEditor, ui.NameRegexEditor)
+		} else {
+			order = append(order, ui.PathKeyEditor, ui.PathSeparatorEditor, ui.NameKeyEditor, ui.NameSeparatorEditor)
+		}
+	}
+	switch {
+	case !p.Analyzed:
+		order = append(order, ui.InputEditor)
+	case !p.Done:
+		order = append(order, ui.FilterEditor)
+		if ui.OverrideSrc != "" {
+			order = append(order, ui.OverrideEditor, ui.ApplyDestButton, ui.CloseDestButton)
+		}
+	}
+	if !ui.Locked() {
+		order = append(order, ui.DestEditor, ui.BrowseDestButton)
+	}
+	switch {
+	case p.Done:
+		order = append(order, ui.CopyButton, ui.ExportButton, ui.OKButton)
+	case p.Analyzed:
+		order = append(order, ui.CopyButton, ui.ExportButton, ui.CancelButton, ui.SavePlanButton)
+		if !p.Offline {
+			if len(p.failedOutcomes()) != 0 {
+				order = append(order, ui.RetryButton)
+			}
+			if p.Method != "catalog" {
+				order = append(order, ui.TestButton)
+			}
+			order = append(order, ui.RunButton)
+		}
+	default:
+		order = append(order, ui.BrowseButton, ui.BrowseDirsButton, ui.PreviewButton, ui.SuggestButton, ui.AnalyzeButton)
+	}
+	return order
+}
+
+// moveFocus는 focusOrder에서 지금 포커스를 가진 것의 다음(back이면 이전)으로 포커스를 옮긴다.
+// 순서에 없는 것이 포커스를 가지고 있다면 처음(back이면 마지막)으로 옮긴다.
+func (ui *UI) moveFocus(gtx C, back bool) {
+	order := ui.focusOrder()
+	if len(order) == 0 {
+		return
+	}
+	next := 0
+	if back {
+		next = len(order) - 1
+	}
+	for i, tag := range order {
+		if !gtx.Focused(tag) {
+			continue
+		}
+		if back {
+			next = (i - 1 + len(order)) % len(order)
+		} else {
+			next = (i + 1) % len(order)
+		}
+		break
+	}
+	gtx.Execute(key.FocusCmd{Tag: order[next]})
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"gioui.org/font"
+	"gioui.org/font/gofont"
+	"gioui.org/font/opentype"
+)
+
+// textFont는 결과 화면의 글자들에 쓰는 글꼴이다.
+// loadFonts로 한글 글꼴을 읽었다면 Go 글꼴에 없는 글자를 그 글꼴로 그리도록 Typeface에 함께 적는다.
+var textFont = gofont.Collection()[0].Font
+
+// cjkFontPaths는 Fonts 설정이 없을 때 찾아볼 운영체제별 한글 글꼴 파일들이다. 처음 찾은 하나만 쓴다.
+func cjkFontPaths() []string {
+	switch runtime.GOOS {
+	case "windows":
+		dir := os.Getenv("WINDIR")
+		if dir == "" {
+			dir = `C:\Windows`
+		}
+		return []string{
+			filepath.Join(dir, "Fonts", "malgun.ttf"),
+			filepath.Join(dir, "Fonts", "NotoSansKR-VF.ttf"),
+			filepath.Join(dir, "Fonts", "gulim.ttc"),
+		}
+	case "darwin":
+		return []string{
+			"/System/Library/Fonts/AppleSDGothicNeo.ttc",
+			"/Library/Fonts/NotoSansKR-Regular.otf",
+		}
+	default:
+		return []string{
+			"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
+			"/usr/share/fonts/noto-cjk/NotoSansCJK-Regular.ttc",
+			"/usr/share/fonts/google-noto-cjk/NotoSansCJK-Regular.ttc",
+			"/usr/share/fonts/truetype/noto/NotoSansKR-Regular.ttf",
+			"/usr/share/fonts/truetype/nanum/NanumGothic.ttf",
+			"/usr/share/fonts/nanum/NanumGothic.ttf",
+		}
+	}
+}
+
+// loadFonts는 Go 글꼴에 paths의 글꼴 파일들을 더한 글꼴 모음을 만들고, textFont가 그 글꼴들을 쓰게 한다.
+// paths가 비어있으면 cjkFontPaths에서 처음 찾은 글꼴을 쓴다.
+// 읽을 수 없는 글꼴은 건너뛰고, 그 에러들을 함께 반환한다.
+func loadFonts(paths []string) ([]font.FontFace, []error) {
+	collection := gofont.Collection()
+	errs := make([]error, 0)
+	if len(paths) == 0 {
+		for _, p := range cjkFontPaths() {
+			if _, err := os.Stat(p); err == nil {
+				paths = []string{p}
+				break
+			}
+		}
+	}
+	families := []string{string(textFont.Typeface)}
+	seen := map[font.Typeface]bool{textFont.Typeface: true}
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		faces, err := opentype.ParseCollection(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s", err, p))
+			continue
+		}
+		collection = append(collection, faces...)
+		for _, f := range faces {
+			if !seen[f.Font.Typeface] {
+				seen[f.Font.Typeface] = true
+				families = append(families, string(f.Font.Typeface))
+			}
+		}
+	}
+	textFont.Typeface = font.Typeface(strings.Join(families, ", "))
+	return collection, errs
+}
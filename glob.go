@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// hasGlobMeta는 path에 글롭 패턴 문자가 있는지 확인한다.
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// expandInputPaths는 입력 경로들 중 글롭 패턴을 파일시스템에서 찾아 실제 경로들로 바꾼다.
+// 패턴에 맞는 경로가 하나도 없다면 그 패턴은 unmatched로 반환된다.
+//
+// 파일 이름에 [ 같은 문자가 들어있을 수 있으므로, 그 경로가 실제로 존재한다면 패턴으로 보지 않는다.
+func expandInputPaths(paths []string) (expanded, unmatched []string, err error) {
+	expanded = make([]string, 0, len(paths))
+	unmatched = make([]string, 0)
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if !hasGlobMeta(path) {
+			expanded = append(expanded, path)
+			continue
+		}
+		if _, err := safeLstat(path); err == nil {
+			expanded = append(expanded, path)
+			continue
+		}
+		matches, err := expandGlob(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(matches) == 0 {
+			unmatched = append(unmatched, path)
+			continue
+		}
+		expanded = append(expanded, matches...)
+	}
+	return expanded, unmatched, nil
+}
+
+// expandGlob은 글롭 패턴에 맞는 경로들을 찾는다.
+// filepath.Match의 문법에 더해, ** 요소는 0개 이상의 디렉토리에 맞는다.
+// 응답하지 않는 마운트에서 멈추지 않도록 디렉토리를 읽을 때마다 제한 시간을 두고, 그 마운트의 에러를 반환한다.
+//
+//	/show/abc/plates/*.exr
+//	/show/abc/**/comp_v???
+func expandGlob(pattern string) ([]string, error) {
+	segs := strings.Split(filepath.ToSlash(pattern), "/")
+	// 패턴 문자가 없는 앞부분에서부터 찾기 시작한다.
+	i := 0
+	for i < len(segs)-1 && !hasGlobMeta(segs[i]) {
+		i++
+	}
+	root := filepath.FromSlash(strings.Join(segs[:i], "/") + "/")
+	if !strings.Contains(pattern, "**") {
+		return withTimeout(root, func() ([]string, error) {
+			return filepath.Glob(pattern)
+		})
+	}
+	found := make(map[string]bool)
+	if err := globSegments(root, segs[i:], found); err != nil {
+		return nil, err
+	}
+	matches := make([]string, 0, len(found))
+	for m := range found {
+		matches = append(matches, m)
+	}
+	sortNatural(matches)
+	return matches, nil
+}
+
+// globSegments는 dir 아래에서 남은 패턴 요소들에 맞는 경로들을 found에 모은다.
+// 읽을 수 없는 디렉토리는 filepath.Glob처럼 조용히 건너뛰지만, 응답하지 않는 마운트라면 그 에러를 반환한다.
+func globSegments(dir string, segs []string, found map[string]bool) error {
+	if len(segs) == 0 {
+		found[filepath.Clean(dir)] = true
+		return nil
+	}
+	seg := segs[0]
+	if seg == "" {
+		// 연속된 슬래시 또는 끝의 슬래시
+		return globSegments(dir, segs[1:], found)
+	}
+	ents, readErr := safeReadDir(dir)
+	var stale *staleMountError
+	if errors.As(readErr, &stale) {
+		return readErr
+	}
+	if seg == "**" {
+		if err := globSegments(dir, segs[1:], found); err != nil {
+			return err
+		}
+		if readErr != nil {
+			return nil
+		}
+		for _, ent := range ents {
+			// 심볼릭 링크 디렉토리는 따라가지 않아 무한 반복을 피한다.
+			if ent.IsDir() {
+				if err := globSegments(filepath.Join(dir, ent.Name()), segs, found); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if readErr != nil {
+		return nil
+	}
+	for _, ent := range ents {
+		ok, err := filepath.Match(seg, ent.Name())
+		if err != nil || !ok {
+			continue
+		}
+		if err := globSegments(filepath.Join(dir, ent.Name()), segs[1:], found); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExpandGlob(t *testing.T) {
+	root := t.TempDir()
+	for _, rel := range []string{
+		"plates/plate.1001.exr",
+		"plates/plate.1002.exr",
+		"plates/notes.txt",
+		"shots/sh010/comp_v001/a.exr",
+		"shots/sh020/comp_v002/b.exr",
+		"shots/sh020/work/comp_v003/c.exr",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cases := []struct {
+		pattern string
+		want    []string
+	}{
+		{"plates/*.exr", []string{"plates/plate.1001.exr", "plates/plate.1002.exr"}},
+		{"plates/*.mov", nil},
+		{"shots/*/comp_v00[12]", []string{"shots/sh010/comp_v001", "shots/sh020/comp_v002"}},
+		{"shots/**/comp_v???", []string{"shots/sh010/comp_v001", "shots/sh020/comp_v002", "shots/sh020/work/comp_v003"}},
+		{"**/c.exr", []string{"shots/sh020/work/comp_v003/c.exr"}},
+	}
+	for _, c := range cases {
+		got, err := expandGlob(filepath.Join(root, filepath.FromSlash(c.pattern)))
+		if err != nil {
+			t.Errorf("expandGlob(%q): %v", c.pattern, err)
+			continue
+		}
+		want := make([]string, 0)
+		for _, rel := range c.want {
+			want = append(want, filepath.Join(root, filepath.FromSlash(rel)))
+		}
+		if got == nil {
+			got = []string{}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expandGlob(%q) = %q, want %q", c.pattern, got, want)
+		}
+	}
+}
+
+func TestExpandInputPaths(t *testing.T) {
+	root := t.TempDir()
+	// 이름에 [ ]가 있는 경로는 존재한다면 패턴으로 보지 않는다.
+	literal := filepath.Join(root, "take[1].mov")
+	if err := os.WriteFile(literal, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(root, "*.exr")
+	expanded, unmatched, err := expandInputPaths([]string{literal, missing, "  " + root + "  "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expanded, []string{literal, root}) {
+		t.Errorf("expanded = %q", expanded)
+	}
+	if !reflect.DeepEqual(unmatched, []string{missing}) {
+		t.Errorf("unmatched = %q", unmatched)
+	}
+}
@@ -6,6 +6,8 @@ require (
 	gioui.org v0.7.1
 	gioui.org/x v0.7.1
 	github.com/BurntSushi/toml v1.5.0
+	golang.org/x/sys v0.22.0
+	golang.org/x/text v0.16.0
 )
 
 require (
@@ -16,6 +18,4 @@ require (
 	golang.org/x/exp v0.0.0-20240707233637-46b078467d37 // indirect
 	golang.org/x/exp/shiny v0.0.0-20240707233637-46b078467d37 // indirect
 	golang.org/x/image v0.18.0 // indirect
-	golang.org/x/sys v0.22.0 // indirect
-	golang.org/x/text v0.16.0 // indirect
 )
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"image"
+	"time"
+
+	"gioui.org/io/event"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+)
+
+// 설정 칸의 도움말이다. 한 줄짜리 힌트로는 부족한 문법을 예와 함께 설명한다.
+const (
+	pathKeysHelp = `Keys for each part of the source path, separated by spaces.
+_ skips a part. ... stands for any number of parts, and keys after it count from the end.
+An absolute path starts with an empty part before the first /.
+
+Example: _ _ _ _ SHOW ... NAME
+/mnt/storm/show/alpha/in/0410/A001.mov
+→ SHOW=alpha, NAME=A001.mov`
+
+	nameKeysHelp = `Keys for each part of the source name, split by the separators on the right.
+_ and ... work the same as for the path.
+
+Example: SEQ SCENE SHOT PART VER ... with separators . _
+S01_0010_SH020_comp_v003.1001.exr
+→ SEQ=S01, SCENE=0010, SHOT=SH020, PART=comp, VER=v003`
+
+	separatorsHelp = `Characters or strings that split into parts, separated by spaces.
+For paths, / and \ both split, so Windows and Unix paths parse alike.
+
+Example: . _
+S01_0010.exr → S01, 0010, exr`
+
+	destHelp = `Parsed keys are filled in as ${KEY}.
+${SHOW:lower}, ${SHOT:upper} and ${VER:pad3} change the value, ${PART:-main} gives a fallback.
+Always available: ${DATE} ${TIME} ${USER} ${HOSTNAME} ${BASENAME} ${EXT} ${PARENT}
+
+Example: /mnt/storm/show/${SHOW}/shot/${SEQ}/${SCENE}_${SHOT}/out/
+→ /mnt/storm/show/alpha/shot/S01/0010_SH020/out/`
+)
+
+// helpDelay는 마우스를 올린 뒤 도움말을 보여주기까지 기다리는 시간이고,
+// helpLongPress와 helpShowTime은 길게 누를 때 필요한 시간과 그 뒤에 도움말을 보여주는 시간이다.
+const (
+	helpDelay     = 500 * time.Millisecond
+	helpLongPress = 500 * time.Millisecond
+	helpShowTime  = 5 * time.Second
+)
+
+// helpArea는 마우스를 올리거나 길게 누르면 도움말을 보여주는 영역의 상태이다.
+type helpArea struct {
+	// hoverAt은 마우스가 영역에 들어온 시각으로, 영역 밖에 있거나 누른 뒤라면 0이다.
+	hoverAt time.Time
+	pressAt time.Time
+	// shownUntil은 길게 눌러 보여준 도움말을 닫을 시각이다.
+	shownUntil time.Time
+}
+
+// helpAreas는 도움말이 있는 설정 칸들이다.
+type helpAreas struct {
+	pathKeys helpArea
+	pathSeps helpArea
+	nameKeys helpArea
+	nameSeps helpArea
+	dest     helpArea
+}
+
+// update는 포인터 이벤트로 상태를 바꾸고, 도움말을 보여줄지와 다시 그려야 할 시각을 반환한다.
+func (h *helpArea) update(gtx C) (visible bool, next time.Time) {
+	for {
+		ev, ok := gtx.Event(pointer.Filter{
+			Target: h,
+			Kinds:  pointer.Enter | pointer.Leave | pointer.Press | pointer.Release | pointer.Cancel,
+		})
+		if !ok {
+			break
+		}
+		e, ok := ev.(pointer.Event)
+		if !ok {
+			continue
+		}
+		switch e.Kind {
+		case pointer.Enter:
+			h.hoverAt = gtx.Now
+		case pointer.Leave, pointer.Cancel:
+			h.hoverAt = time.Time{}
+			h.pressAt = time.Time{}
+		case pointer.Press:
+			// 눌러서 에디터에 입력하기 시작하면 도움말을 가린다.
+			h.hoverAt = time.Time{}
+			h.shownUntil = time.Time{}
+			h.pressAt = gtx.Now
+		case pointer.Release:
+			if !h.pressAt.IsZero() && gtx.Now.Sub(h.pressAt) >= helpLongPress {
+				h.shownUntil = gtx.Now.Add(helpShowTime)
+			}
+			h.pressAt = time.Time{}
+		}
+	}
+	switch {
+	case gtx.Now.Before(h.shownUntil):
+		return true, h.shownUntil
+	case !h.pressAt.IsZero():
+		at := h.pressAt.Add(helpLongPress)
+		return !gtx.Now.Before(at), at
+	case !h.hoverAt.IsZero():
+		at := h.hoverAt.Add(helpDelay)
+		return !gtx.Now.Before(at), at
+	}
+	return false, time.Time{}
+}
+
+// layoutHelp는 w를 그리고, 그 영역에 마우스를 올리거나 길게 누르면 text를 w의 아래에 보여준다.
+// above가 참이면 창의 아래쪽에 있는 칸을 위해 w의 위에 보여준다.
+func (ui *UI) layoutHelp(gtx C, h *helpArea, text string, above bool, w layout.Widget) D {
+	visible, next := h.update(gtx)
+	if !next.IsZero() && next.After(gtx.Now) {
+		gtx.Execute(op.InvalidateCmd{At: next})
+	}
+	dims := w(gtx)
+	// 아래의 에디터나 버튼도 이벤트를 받을 수 있도록 통과시킨다.
+	area := clip.Rect(image.Rectangle{Max: dims.Size}).Push(gtx.Ops)
+	pass := pointer.PassOp{}.Push(gtx.Ops)
+	event.Op(gtx.Ops, h)
+	pass.Pop()
+	area.Pop()
+	if !visible {
+		return dims
+	}
+	tgtx := gtx
+	tgtx.Constraints.Min = image.Point{}
+	tgtx.Constraints.Max.X = min(gtx.Dp(unit.Dp(480)), gtx.Constraints.Max.X)
+	macro := op.Record(gtx.Ops)
+	tip := ui.layoutTip(tgtx, tr(text))
+	call := macro.Stop()
+	y := dims.Size.Y
+	if above {
+		y = -tip.Size.Y
+	}
+	macro = op.Record(gtx.Ops)
+	op.Offset(image.Pt(0, y)).Add(gtx.Ops)
+	call.Add(gtx.Ops)
+	// 도움말이 다른 위젯들 위에 그려지도록 나중에 그린다.
+	op.Defer(gtx.Ops, macro.Stop())
+	return dims
+}
+
+// layoutTip은 도움말 상자를 그린다.
+func (ui *UI) layoutTip(gtx C, text string) D {
+	return layout.Stack{}.Layout(gtx,
+		layout.Expanded(func(gtx C) D {
+			r := gtx.Dp(unit.Dp(4))
+			paint.FillShape(gtx.Ops, ui.Theme.Fg, clip.UniformRRect(image.Rectangle{Max: gtx.Constraints.Min}, r).Op(gtx.Ops))
+			return D{Size: gtx.Constraints.Min}
+		}),
+		layout.Stacked(func(gtx C) D {
+			return layout.UniformInset(unit.Dp(8)).Layout(gtx, func(gtx C) D {
+				lbl := material.Body2(ui.Theme, text)
+				lbl.Color = ui.Theme.Bg
+				return lbl.Layout(gtx)
+			})
+		}),
+	)
+}
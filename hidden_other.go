@@ -0,0 +1,8 @@
+//go:build !windows
+
+package main
+
+// hiddenAttr는 윈도우즈 외의 시스템에서는 숨김 속성이 따로 없으므로 항상 거짓이다.
+func hiddenAttr(path string) bool {
+	return false
+}
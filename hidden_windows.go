@@ -0,0 +1,16 @@
+package main
+
+import "syscall"
+
+// hiddenAttr는 윈도우즈에서 path에 숨김 속성이 있는지 확인한다.
+func hiddenAttr(path string) bool {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return false
+	}
+	attr, err := syscall.GetFileAttributes(p)
+	if err != nil {
+		return false
+	}
+	return attr&syscall.FILE_ATTRIBUTE_HIDDEN != 0
+}
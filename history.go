@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+)
+
+// IngestRecord는 복사 작업 한번의 기록이다.
+// 기록들은 설정 디렉토리의 history.jsonl 파일에 한 줄에 하나씩 쌓인다.
+type IngestRecord struct {
+	RunID       string       `json:"run_id"`
+	Time        time.Time    `json:"time"`
+	User        string       `json:"user"`
+	Host        string       `json:"host"`
+	Method      string       `json:"method"`
+	DestPattern string       `json:"dest_pattern"`
+	Files       []IngestFile `json:"files"`
+}
+
+// IngestFile은 작업에서 복사된 파일 하나의 기록이다.
+// Size와 Hash는 HashHistory가 켜져 있을 때만 기록된다.
+type IngestFile struct {
+	Src  string `json:"src"`
+	Dest string `json:"dest"`
+	Size int64  `json:"size,omitempty"`
+	Hash string `json:"hash,omitempty"`
+}
+
+// newRunID는 다른 워크스테이션의 기록과 겹치지 않는 작업 아이디를 만든다.
+func newRunID(t time.Time) string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	host, _ := os.Hostname()
+	return t.Format("20060102T150405") + "-" + host + "-" + hex.EncodeToString(b)
+}
+
+// currentUser는 기록에 남길 사용자 이름을 반환한다.
+func currentUser() string {
+	u, err := user.Current()
+	if err != nil {
+		return os.Getenv("USER")
+	}
+	return u.Username
+}
+
+// historyFile은 작업 기록 파일의 경로를 반환한다.
+func historyFile() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// appendHistory는 작업 기록을 기록 파일 끝에 추가한다.
+func appendHistory(file string, recs ...*IngestRecord) error {
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, rec := range recs {
+		err := enc.Encode(rec)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readHistory는 기록 파일의 모든 작업 기록을 읽는다.
+// 파일이 없다면 빈 기록을 반환한다.
+func readHistory(file string) ([]*IngestRecord, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	return decodeHistory(f)
+}
+
+// decodeHistory는 JSONL 형식의 작업 기록들을 읽는다.
+func decodeHistory(r io.Reader) ([]*IngestRecord, error) {
+	recs := make([]*IngestRecord, 0)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 256*1024*1024)
+	n := 0
+	for sc.Scan() {
+		n++
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		rec := new(IngestRecord)
+		err := json.Unmarshal(line, rec)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		recs = append(recs, rec)
+	}
+	return recs, sc.Err()
+}
+
+// exportHistory는 기록 파일의 모든 작업 기록을 w에 JSONL 형식으로 쓴다.
+func exportHistory(file string, w io.Writer) error {
+	recs, err := readHistory(file)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	for _, rec := range recs {
+		err := enc.Encode(rec)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importHistory는 다른 워크스테이션에서 내보낸 작업 기록을 기록 파일에 합친다.
+// 이미 같은 작업 아이디의 기록이 있다면 건너뛴다.
+// 추가된 기록과 건너뛴 기록의 수를 반환한다.
+func importHistory(file string, r io.Reader) (added, skipped int, err error) {
+	recs, err := readHistory(file)
+	if err != nil {
+		return 0, 0, err
+	}
+	seen := make(map[string]bool)
+	for _, rec := range recs {
+		seen[rec.RunID] = true
+	}
+	in, err := decodeHistory(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	news := make([]*IngestRecord, 0)
+	for _, rec := range in {
+		if rec.RunID == "" {
+			return 0, 0, fmt.Errorf("record without run_id")
+		}
+		if seen[rec.RunID] {
+			skipped++
+			continue
+		}
+		seen[rec.RunID] = true
+		news = append(news, rec)
+	}
+	err = appendHistory(file, news...)
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(news), skipped, nil
+}
+
+// checkReingest는 작업 기록 recs에서 이번에 복사할 소스들이 이전에 복사된 적이 있는지 찾아
+// 가장 최근에 언제 어디로 복사되었는지를 p.Reingested에 기록한다.
+// 디렉토리 소스는 그 안의 파일이 하나라도 복사되었다면 복사된 것으로 본다.
+func (p *Program) checkReingest(recs []*IngestRecord) {
+	p.Reingested = make([]string, 0)
+	type prior struct {
+		dest  string
+		time  time.Time
+		files int
+	}
+	srcs := make(map[string]bool)
+	for _, src := range p.Srcs {
+		if _, ok := p.DestDir[src]; ok {
+			srcs[src] = true
+		}
+	}
+	found := make(map[string]*prior)
+	for _, rec := range recs {
+		if rec.Method == "catalog" {
+			// 목록만 만든 기록은 복사한 것이 아니다.
+			continue
+		}
+		for _, f := range rec.Files {
+			// 파일 자신이나 그 파일을 담은 디렉토리 소스를 찾는다.
+			src, dest := filepath.Clean(f.Src), filepath.Clean(f.Dest)
+			for {
+				if srcs[src] {
+					pr := found[src]
+					if pr == nil {
+						pr = &prior{}
+						found[src] = pr
+					}
+					// 가장 최근 작업에서 복사된 파일들만 센다.
+					if rec.Time.After(pr.time) {
+						pr.time = rec.Time
+						pr.dest = dest
+						pr.files = 0
+					}
+					if rec.Time.Equal(pr.time) {
+						pr.files++
+					}
+					break
+				}
+				parent := filepath.Dir(src)
+				if parent == src {
+					break
+				}
+				src = parent
+				dest = filepath.Dir(dest)
+			}
+		}
+	}
+	// 시퀀스는 프레임마다 알리지 않고 한번만 알린다.
+	seen := make(map[*sequence]bool)
+	for src, pr := range found {
+		name := src
+		if seq := p.SrcSeq[src]; seq != nil {
+			if seen[seq] {
+				continue
+			}
+			seen[seq] = true
+			name = seq.String()
+			pr.dest = filepath.Dir(pr.dest)
+		}
+		line := fmt.Sprintf("%s went to %s on %s", name, pr.dest, pr.time.Local().Format("2006-01-02 15:04"))
+		if p.SrcIsDir[src] {
+			line += fmt.Sprintf(" (%d files)", pr.files)
+		}
+		p.Reingested = append(p.Reingested, line)
+	}
+	sortNatural(p.Reingested)
+}
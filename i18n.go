@@ -0,0 +1,305 @@
+package main
+
+// language는 화면에 보여줄 언어로, "en" 또는 "ko"이다.
+var language = "en"
+
+// languages는 언어 버튼을 누를 때 차례로 바뀌는 언어들과 그 버튼에 보여줄 이름이다.
+var languages = []struct {
+	Code string
+	Name string
+}{
+	{"en", "English"},
+	{"ko", "한국어"},
+}
+
+// koMessages는 영어 메시지를 한국어로 옮긴 것이다.
+// 경로나 개수처럼 뒤에 붙는 값은 옮기지 않으므로, 키는 그 앞까지의 문구이다.
+var koMessages = map[string]string{
+	// 설정
+	"profile ":                               "프로필 ",
+	"Default":                                "기본",
+	"find keys by ":                          "키 찾는 방법 ",
+	"Separators":                             "구분자",
+	"Regex":                                  "정규식",
+	"Tools":                                  "도구",
+	"Hide tools":                             "도구 숨기기",
+	"path regex ":                            "경로 정규식 ",
+	"name regex ":                            "이름 정규식 ",
+	"separate path to ":                      "경로 키 ",
+	"separate name to ":                      "이름 키 ",
+	" with ":                                 " 구분자 ",
+	"path environ filter":                    "경로 환경변수 필터",
+	"name environ filter":                    "이름 환경변수 필터",
+	"separators (/ \\)":                      "구분자 (/ \\)",
+	"separators (_ .)":                       "구분자 (_ .)",
+	"filter sources (text or KEY=value)":     "소스 필터 (글자 또는 KEY=value)",
+	"  group by ":                            "  묶는 기준 ",
+	"Destination":                            "대상 경로",
+	"Show":                                   "쇼",
+	"Source":                                 "소스",
+	"Thumbnails":                             "썸네일",
+	"paths to copy, paste or drop them here": "복사할 경로들, 붙여넣거나 여기에 끌어다 놓으세요",
+	"paths to copy, paste them or use Add files…": "복사할 경로들, 붙여넣거나 파일 추가…를 쓰세요",
+	"destination folder":                          "대상 폴더",
+	"Choose…":                                     "선택…",
+	"Link":                                        "링크",
+	"Copy":                                        "복사",
+	"Catalog":                                     "목록만",
+	"symlinks:":                                   "심볼릭 링크:",
+	"Follow":                                      "따라가기",
+	"Keep":                                        "유지",
+	"Read-only":                                   "읽기 전용",
+	"Skip hidden":                                 "숨김 파일 제외",
+	"Flatten":                                     "평평하게",
+	"Offline plan":                                "오프라인 계획",
+	"Wait for missing":                            "없는 소스 기다리기",
+	"Keep going on errors":                        "에러가 나도 계속",
+	"learn from ":                                 "배우기 ",
+	" to ":                                        " → ",
+	"remap values ":                               "값 바꾸기 ",
+	"exclude in directories ":                     "디렉토리에서 제외 ",
+	" include only ":                              " 포함할 것만 ",
+	" max depth ":                                 " 최대 깊이 ",
+	"renumber frames ":                            "프레임 번호 다시 매기기 ",
+	" padding ":                                   " 자릿수 ",
+	"camera card ":                                "카메라 카드 ",
+	"None":                                        "없음",
+	"Auto":                                        "자동",
+	"media metadata ":                             "미디어 메타데이터 ",
+	"Probe with ffprobe (RES, FPS, CODEC)":        "ffprobe로 확인 (RES, FPS, CODEC)",
+	"Read EXR headers (EXR_OWNER, ...)":           "EXR 헤더 읽기 (EXR_OWNER, ...)",
+	"Read EXIF dates (SHOT_DATE)":                 "EXIF 날짜 읽기 (SHOT_DATE)",
+	"vendor manifest ":                            "업체 해시 목록 ",
+	"compare ":                                    "비교 ",
+	"test path ":                                  "시험 경로 ",
+	"destination for ":                            "대상 경로: ",
+	"sample path (first input path if empty)":                          "예시 경로 (비어있으면 첫 입력 경로)",
+	"desired destination for the sample":                               "예시를 보낼 대상 경로",
+	"from,to pairs separated by commas, e.g. DEL,delivery,PRV,preview": "쉼표로 구분한 원래,바꿀 값 쌍, 예: DEL,delivery,PRV,preview",
+	"name globs, e.g. .DS_Store Thumbs.db *.tmp ._*":                   "이름 글롭, 예: .DS_Store Thumbs.db *.tmp ._*",
+	"file name globs, e.g. *.exr *.mov (all if empty)":                 "파일 이름 글롭, 예: *.exr *.mov (비어있으면 모두)",
+	"any": "제한 없음",
+	"first frame like 1001, or offset like +100 or -10 (keep if empty)": "1001 같은 첫 프레임이나 +100, -10 같은 차이 (비어있으면 그대로)",
+	"keep": "그대로",
+	"MHL or md5sum list to verify the sources against (none if empty)": "소스를 확인할 MHL이나 md5sum 목록 (비어있으면 없음)",
+	"source directory":      "소스 디렉토리",
+	"where it was ingested": "가져간 위치",
+	"any path to try the current keys and destination on": "지금의 키와 대상 경로를 시험해볼 아무 경로",
+
+	// 버튼
+	"Add files…":                        "파일 추가…",
+	"Add folders…":                      "폴더 추가…",
+	"Preview":                           "미리보기",
+	"Edit input":                        "입력 수정",
+	"Suggest":                           "제안",
+	"Analyze":                           "분석",
+	"Copy report":                       "보고서 복사",
+	"Export":                            "내보내기",
+	"OK":                                "확인",
+	"Cancel":                            "취소",
+	"Save plan":                         "계획 저장",
+	"Ingest again":                      "다시 가져오기",
+	"Retry failed":                      "실패한 것 다시",
+	"Test run":                          "시험 실행",
+	"Run":                               "실행",
+	"Back":                              "뒤로",
+	"Learn":                             "배우기",
+	"Compare":                           "비교",
+	"Apply":                             "적용",
+	"Close":                             "닫기",
+	"Open destination":                  "대상 경로 열기",
+	"Copy path":                         "경로 복사",
+	"Drag":                              "끌기",
+	"Copying":                           "복사중",
+	"Cataloging":                        "목록 만드는 중",
+	"preparing...":                      "준비중...",
+	"Write into existing destinations?": "이미 있는 대상 경로에 쓸까요?",
+
+	// 알림
+	"please set destination":              "대상 경로를 정해주세요",
+	"destination path cannot be relative": "대상 경로는 상대 경로일 수 없습니다",
+	"paste filepaths to take in.":         "가져올 파일 경로들을 붙여넣어 주세요.",
+	"filepath not found":                  "파일 경로를 찾을 수 없습니다",
+	"dest sample: ":                       "대상 경로 예: ",
+	"path analyzed":                       "경로를 분석했습니다",
+	"path analyzed, but destination pattern changed since last run":           "경로를 분석했지만, 지난 작업 뒤로 대상 경로 패턴이 바뀌었습니다",
+	"path analyzed, but not enough space at the destination":                  "경로를 분석했지만, 대상 경로에 공간이 모자랍니다",
+	"path analyzed, but some files don't match the manifest":                  "경로를 분석했지만, 해시 목록과 맞지 않는 파일이 있습니다",
+	"path analyzed, but the batch would exceed a quota":                       "경로를 분석했지만, 작업이 쿼터를 넘습니다",
+	"path analyzed, but some destinations are not writable":                   "경로를 분석했지만, 쓸 수 없는 대상 경로가 있습니다",
+	"path analyzed, but some sources were already ingested":                   "경로를 분석했지만, 이미 가져온 소스가 있습니다",
+	"path analyzed, but some files were already ingested elsewhere":           "경로를 분석했지만, 이미 다른 곳으로 가져온 파일이 있습니다",
+	"path analyzed, but some destinations can't be linked across filesystems": "경로를 분석했지만, 다른 파일시스템이라 링크할 수 없는 대상 경로가 있습니다",
+	"path analyzed, but some destinations differ only in case":                "경로를 분석했지만, 대소문자만 다른 대상 경로가 있습니다",
+	"path analyzed, but can't run: ":                                          "경로를 분석했지만, 실행할 수 없습니다: ",
+	"files counted, but not enough space at the destination":                  "파일을 셌지만, 대상 경로에 공간이 모자랍니다",
+	"files counted, but the batch would exceed a quota":                       "파일을 셌지만, 작업이 쿼터를 넘습니다",
+	"files counted, but some files don't match the manifest":                  "파일을 셌지만, 해시 목록과 맞지 않는 파일이 있습니다",
+	"files counted, but some files were already ingested elsewhere":           "파일을 셌지만, 이미 다른 곳으로 가져온 파일이 있습니다",
+	"please modify your paths and analyze again":                              "경로를 고친 뒤 다시 분석해주세요",
+	"plan saved: ":                                                      "계획을 저장했습니다: ",
+	"report exported: %s.csv and .json":                                 "보고서를 내보냈습니다: %s.csv, .json",
+	"%s appeared, analyzed again":                                       "%s이(가) 생겨 다시 분석했습니다",
+	"%s and %d more appeared, analyzed again":                           "%s 외 %d개가 생겨 다시 분석했습니다",
+	"suggested from %d paths, rename DIR/KEY placeholders to your keys": "경로 %d개에서 제안했습니다. DIR/KEY 자리를 알맞은 키로 바꾸세요",
+	"... and %d more, run takein diff for the full list":                "... 외 %d개, 전체 목록은 takein diff로 보세요",
+	"%d missing, %d extra, %d size differs":                             "%d개 없음, %d개 더 있음, %d개 크기 다름",
+	"report copied to the clipboard":                                    "보고서를 클립보드에 복사했습니다",
+	"path copied to the clipboard":                                      "경로를 클립보드에 복사했습니다",
+	"test run done, check the copied files and run to copy the rest":    "시험 실행을 마쳤습니다. 복사된 파일들을 확인한 뒤 실행해 나머지를 복사하세요",
+	"done": "완료",
+	"paste sample paths to suggest separators and keys":             "구분자와 키를 제안받을 예시 경로들을 붙여넣어 주세요",
+	"learn needs a sample path and its desired destination":         "배우려면 예시 경로와 그 대상 경로가 필요합니다",
+	"compare needs a source directory and its destination":          "비교하려면 소스 디렉토리와 그 대상 경로가 필요합니다",
+	"destination not changed, cancel the analysis first":            "대상 경로를 바꾸지 않았습니다. 먼저 분석을 취소하세요",
+	"destination changed for ":                                      "대상 경로를 바꿨습니다: ",
+	"no differences":                                                "차이 없음",
+	"error: ":                                                       "에러: ",
+	"record history: %v":                                            "작업 기록 남기기: %v",
+	"paths not analyzed yet":                                        "경로를 아직 분석하지 않았습니다",
+	"offline plan can't be run, analyze again without offline mode": "오프라인 계획은 실행할 수 없습니다. 오프라인 모드를 끄고 다시 분석하세요",
+	"can't run: %s":                                                 "실행할 수 없습니다: %s",
+	"already ingested: %s, check \"Ingest again\" to run anyway":    "이미 가져왔습니다: %s. 그래도 실행하려면 \"다시 가져오기\"를 체크하세요",
+	"%s is still being written, try again later":                    "%s에 아직 쓰는 중입니다. 나중에 다시 해보세요",
+	"%s and %d more files are still being written, try again later": "%s 외 %d개 파일에 아직 쓰는 중입니다. 나중에 다시 해보세요",
+	"%d of %d files failed, first: %s":                              "파일 %[2]d개 중 %[1]d개 실패, 첫번째: %[3]s",
+	"make dirs: %w: %s":                                             "디렉토리 만들기: %w: %s",
+	"symlink file: %w":                                              "심볼릭 링크 만들기: %w",
+	"copy file: %w":                                                 "파일 복사: %w",
+	"link file: %w":                                                 "파일 링크: %w",
+	"make read-only: %w":                                            "읽기 전용으로 바꾸기: %w",
+	"%d of %d files, %s of %s":                                      "파일 %[2]d개 중 %[1]d개, %[4]s 중 %[3]s",
+	"measuring speed...":                                            "속도 재는 중...",
+	", about %s left":                                               ", 약 %s 남음",
+
+	// 도움말
+	pathKeysHelp: `소스 경로의 각 부분에 붙일 키들로, 공백으로 나눈다.
+_ 는 그 부분을 건너뛴다. ... 는 여러 부분을 대신하며, 그 뒤의 키들은 끝에서부터 센다.
+절대 경로는 첫 / 앞에 빈 부분이 있다.
+
+예) _ _ _ _ SHOW ... NAME
+/mnt/storm/show/alpha/in/0410/A001.mov
+→ SHOW=alpha, NAME=A001.mov`,
+	nameKeysHelp: `소스 이름의 각 부분에 붙일 키들로, 오른쪽의 구분자로 나눈다.
+_ 와 ... 는 경로에서와 같다.
+
+예) 구분자가 . _ 일 때 SEQ SCENE SHOT PART VER ...
+S01_0010_SH020_comp_v003.1001.exr
+→ SEQ=S01, SCENE=0010, SHOT=SH020, PART=comp, VER=v003`,
+	separatorsHelp: `부분들을 나눌 문자나 문자열들로, 공백으로 나눈다.
+경로에서는 / 와 \ 가 모두 나누므로 윈도우즈와 유닉스 경로를 같이 분석할 수 있다.
+
+예) . _
+S01_0010.exr → S01, 0010, exr`,
+	destHelp: `분석한 키는 ${KEY}로 넣는다.
+${SHOW:lower}, ${SHOT:upper}, ${VER:pad3}는 값을 바꾸고, ${PART:-main}은 값이 없을 때 쓸 값을 정한다.
+항상 쓸 수 있는 값: ${DATE} ${TIME} ${USER} ${HOSTNAME} ${BASENAME} ${EXT} ${PARENT}
+
+예) /mnt/storm/show/${SHOW}/shot/${SEQ}/${SCENE}_${SHOT}/out/
+→ /mnt/storm/show/alpha/shot/S01/0010_SH020/out/`,
+
+	// 에러 해결 방법
+	"check that you can read the source and write to the destination":                       "소스를 읽고 대상 경로에 쓸 수 있는지 확인하세요",
+	"free up space at the destination or split the batch":                                   "대상 경로의 공간을 비우거나 작업을 나누세요",
+	"the destination is mounted read-only, pick another destination or remount it writable": "대상 경로가 읽기 전용으로 마운트되어 있습니다. 다른 대상 경로를 고르거나 쓰기 가능하게 다시 마운트하세요",
+	"the network share was remounted or went away, remount it and analyze again":            "네트워크 공유가 다시 마운트되었거나 사라졌습니다. 다시 마운트한 뒤 분석하세요",
+	"hardlinks can't cross filesystems, use the copy method":                                "하드 링크는 다른 파일시스템에 만들 수 없습니다. 복사를 쓰세요",
+	"the mount isn't responding, check the network share or raise StatTimeout":              "마운트가 응답하지 않습니다. 네트워크 공유를 확인하거나 StatTimeout을 늘리세요",
+
+	// 분석 결과
+	"Recursive Copy":                           "재귀 복사",
+	"Offline Plan":                             "오프라인 계획",
+	"Manifest Mismatches":                      "해시 목록과 다른 파일",
+	"Not Enough Space":                         "공간 부족",
+	"Over Quota":                               "쿼터 초과",
+	"Not Writable":                             "쓸 수 없음",
+	"Already Ingested":                         "이미 가져옴",
+	"Already Ingested Content":                 "이미 가져온 내용",
+	"Can't Link Across Filesystems":            "다른 파일시스템이라 링크할 수 없음",
+	"Pattern Changed Since Last Run":           "지난 작업 뒤로 바뀐 패턴",
+	"Not Exists":                               "없는 경로",
+	"Invalids":                                 "잘못된 경로",
+	"Frame Gaps":                               "빠진 프레임",
+	"Case Collisions":                          "대소문자 충돌",
+	"took %s, %s written":                      "%s 걸림, %s 씀",
+	" at %s/s on average":                      ", 평균 %s/s",
+	"%d copied, %d linked, %d skipped":         "%d개 복사, %d개 링크, %d개 건너뜀",
+	", %d failed":                              ", %d개 실패",
+	"Rewritten":                                "바뀐 경로",
+	"Camera Cards":                             "카메라 카드",
+	"Merged":                                   "합쳐진 경로",
+	"Ignored":                                  "무시한 파일",
+	"Destination Path Problems":                "대상 경로 문제",
+	" (not checked)":                           " (확인 안 함)",
+	" (to be created)":                         " (새로 만듦)",
+	"checked against ":                         "확인한 목록: ",
+	"checking hashes...":                       "해시 확인 중...",
+	"couldn't check hashes: ":                  "해시를 확인할 수 없음: ",
+	"at least ":                                "적어도 ",
+	"1 file will be added":                     "파일 1개가 더해집니다",
+	"%d files will be added":                   "파일 %d개가 더해집니다",
+	"1 destination already exists, ":           "대상 경로 1개가 이미 있고, ",
+	"%d destinations already exist, ":          "대상 경로 %d개가 이미 있고, ",
+	"showing %d of %d sources matching %q\n\n": "소스 %[2]d개 중 %[3]q에 맞는 %[1]d개\n\n",
+	"these files inside directory sources will fail to copy\n\n": "디렉토리 소스 안의 이 파일들은 복사에 실패합니다\n\n",
+	"files counted, but some destination paths can't be created": "파일을 셌지만 만들 수 없는 대상 경로가 있습니다",
+	"Catalog completed":         "목록 작성 완료",
+	"Test run completed":        "시험 실행 완료",
+	"Copy finished with errors": "복사 중 에러 발생",
+	"Copy completed":            "복사 완료",
+	"can't run until the destination pattern or the sources change\n\n":            "대상 경로 패턴이나 소스가 바뀌기 전에는 실행할 수 없습니다\n\n",
+	"files and destinations are not checked, and the plan can't be run\n\n":        "파일과 대상 경로를 확인하지 않았으며, 이 계획은 실행할 수 없습니다\n\n",
+	"hardlinks will fail here, use the copy method instead\n\n":                    "여기서는 하드 링크가 실패하니 복사를 쓰세요\n\n",
+	"these merge on case-insensitive filesystems such as macOS and SMB shares\n\n": "macOS나 SMB 공유처럼 대소문자를 구분하지 않는 파일시스템에서는 하나로 합쳐집니다\n\n",
+	"check the layout and permissions of these files, then run to copy the rest\n": "이 파일들의 위치와 권한을 확인한 뒤 실행해 나머지를 복사하세요\n",
+	"check \"Ingest again\" to copy them anyway\n\n":                               "그래도 복사하려면 \"다시 가져오기\"를 체크하세요\n\n",
+	" (sequence %s":                                  " (시퀀스 %s",
+	"already linked":                                 "이미 링크됨",
+	"%d frames already linked":                       "프레임 %d개 이미 링크됨",
+	"already exists, will be skipped":                "이미 있어 건너뜀",
+	"%d frames already exist, will be skipped":       "프레임 %d개가 이미 있어 건너뜀",
+	"destination set by hand":                        "직접 정한 대상 경로",
+	"directory, counting files...":                   "디렉토리, 파일 세는 중...",
+	"directory, couldn't count files: %s":            "디렉토리, 파일을 셀 수 없음: %s",
+	"directory, containing %d file, %s":              "디렉토리, 파일 %d개, %s",
+	"directory, containing %d files, %s":             "디렉토리, 파일 %d개, %s",
+	"flattened":                                      "평평하게",
+	"%d excluded":                                    "%d개 제외",
+	"%d already at the destination, will be skipped": "%d개가 이미 대상 경로에 있어 건너뜀",
+	"to ": "대상 경로: ",
+	"%d files, %s hashed and recorded in the history, nothing was copied\n": "파일 %d개, %s의 해시를 작업 기록에 남겼으며, 복사한 것은 없습니다\n",
+	"%d files or directories excluded by filters\n":                         "필터로 제외한 파일이나 디렉토리 %d개\n",
+	"was: ": "이전: ",
+	"now: ": "현재: ",
+}
+
+// tr은 영어 메시지 s를 현재 언어로 옮긴다. 옮긴 것이 없다면 s를 그대로 반환한다.
+func tr(s string) string {
+	if language == "ko" {
+		if t, ok := koMessages[s]; ok {
+			return t
+		}
+	}
+	return s
+}
+
+// setLanguage는 화면의 언어를 code로 바꾼다. 알 수 없는 언어라면 영어를 쓴다.
+func setLanguage(code string) {
+	language = "en"
+	for _, l := range languages {
+		if l.Code == code {
+			language = code
+		}
+	}
+}
+
+// nextLanguage는 현재 언어 다음에 쓸 언어이다.
+func nextLanguage() (code, name string) {
+	for i, l := range languages {
+		if l.Code == language {
+			next := languages[(i+1)%len(languages)]
+			return next.Code, next.Name
+		}
+	}
+	return languages[0].Code, languages[0].Name
+}
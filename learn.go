@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Learned는 예제로부터 추정한 키 설정과 대상 경로 패턴이다.
+type Learned struct {
+	PathKeys []string
+	NameKeys []string
+	Dest     string
+	// Keys는 패턴에 쓰인 키의 수이다.
+	Keys int
+}
+
+// learnToken은 예제 소스 경로의 토큰 하나이다.
+type learnToken struct {
+	name  bool // 이름 토큰이면 참, 경로 토큰이면 거짓
+	index int
+	value string
+	key   string
+}
+
+// learnKeys는 예제 소스 경로 src와 사용자가 원하는 대상 경로 dest를 비교해
+// 어떤 토큰이 어떤 키가 되어야 하는지 추정한다.
+//
+// 소스의 토큰 값이 dest에 나타나면 그 부분을 ${KEY}로 바꾸고, 해당 위치에 키를 배치한다.
+// 현재 키 설정에서 그 위치에 이미 이름이 있다면 그 이름을 쓰고, 아니라면 P1, N1 같은 이름을 만든다.
+// 왼쪽 절반의 토큰은 왼쪽을 기준으로, 오른쪽 절반의 토큰은 ... 뒤에 오른쪽을 기준으로 배치해
+// 깊이가 조금 다른 경로들도 분석할 수 있게 한다.
+func learnKeys(src, dest string, pathSeps, nameSeps, pathKeys, nameKeys []string) (*Learned, error) {
+	pathToks := splitTokens(src, pathSeps)
+	nameToks := splitTokens(baseName(src), nameSeps)
+	// 소스와 대상이 같은 경로로 시작한다면 그 부분은 키가 아니라 공통 루트이다.
+	destToks := splitTokens(dest, pathSeps)
+	common := 0
+	for common < len(pathToks) && common < len(destToks) && pathToks[common] == destToks[common] {
+		common++
+	}
+	pathNames := keyPositions(len(pathToks), pathKeys)
+	nameNames := keyPositions(len(nameToks), nameKeys)
+	toks := make([]*learnToken, 0, len(pathToks)+len(nameToks))
+	for i, v := range pathToks[common:] {
+		i += common
+		toks = append(toks, &learnToken{index: i, value: v, key: pathNames[i]})
+	}
+	for i, v := range nameToks {
+		toks = append(toks, &learnToken{name: true, index: i, value: v, key: nameNames[i]})
+	}
+	// 긴 토큰부터 찾아야 짧은 토큰이 긴 토큰의 일부를 가로채지 않는다.
+	// 길이가 같으면 경로 토큰을 먼저 쓴다.
+	sort.SliceStable(toks, func(i, j int) bool {
+		return len(toks[i].value) > len(toks[j].value)
+	})
+	used := make(map[string]bool)
+	for _, k := range append(append([]string{}, pathKeys...), nameKeys...) {
+		used[k] = true
+	}
+	// segs는 dest를 리터럴과 키로 나눈 것이다. 키 조각은 "${KEY}" 형식이다.
+	segs := []string{dest}
+	isKey := []bool{false}
+	matched := make([]*learnToken, 0)
+	for _, t := range toks {
+		// 한 글자 토큰은 우연히 겹칠 가능성이 크다.
+		if len(t.value) < 2 {
+			continue
+		}
+		found := false
+		for i := 0; i < len(segs); i++ {
+			if isKey[i] || !strings.Contains(segs[i], t.value) {
+				continue
+			}
+			found = true
+			break
+		}
+		if !found {
+			continue
+		}
+		if t.key == "" || t.key == "_" || t.key == "..." {
+			prefix := "P"
+			if t.name {
+				prefix = "N"
+			}
+			for n := t.index + 1; ; n++ {
+				k := prefix + strconv.Itoa(n)
+				if !used[k] {
+					t.key = k
+					break
+				}
+			}
+		}
+		used[t.key] = true
+		newSegs := make([]string, 0, len(segs))
+		newIsKey := make([]bool, 0, len(segs))
+		for i, seg := range segs {
+			if isKey[i] {
+				newSegs = append(newSegs, seg)
+				newIsKey = append(newIsKey, true)
+				continue
+			}
+			parts := strings.Split(seg, t.value)
+			for j, part := range parts {
+				if j != 0 {
+					newSegs = append(newSegs, "${"+t.key+"}")
+					newIsKey = append(newIsKey, true)
+				}
+				newSegs = append(newSegs, part)
+				newIsKey = append(newIsKey, false)
+			}
+		}
+		segs, isKey = newSegs, newIsKey
+		matched = append(matched, t)
+	}
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("no part of the sample path found in the example destination")
+	}
+	l := &Learned{
+		Dest: strings.Join(segs, ""),
+		Keys: len(matched),
+	}
+	pathUsed := make(map[int]string)
+	nameUsed := make(map[int]string)
+	for _, t := range matched {
+		if t.name {
+			nameUsed[t.index] = t.key
+		} else {
+			pathUsed[t.index] = t.key
+		}
+	}
+	l.PathKeys = layoutKeys(len(pathToks), pathUsed)
+	l.NameKeys = layoutKeys(len(nameToks), nameUsed)
+	return l, nil
+}
+
+// keyPositions는 n개의 토큰에 keys를 parseEnvs와 같은 방식으로 배치했을 때
+// 각 토큰 위치의 키 이름을 반환한다. 배치할 수 없다면 빈 이름들을 반환한다.
+func keyPositions(n int, keys []string) []string {
+	names := make([]string, n)
+	idx := -1
+	for i, k := range keys {
+		if k == "..." {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		if len(keys) != n {
+			return names
+		}
+		copy(names, keys)
+		return names
+	}
+	left, right := keys[:idx], keys[idx+1:]
+	if len(left)+len(right) > n {
+		return names
+	}
+	copy(names, left)
+	copy(names[n-len(right):], right)
+	return names
+}
+
+// layoutKeys는 토큰 위치별로 쓰인 키들로 키 목록을 만든다.
+func layoutKeys(n int, used map[int]string) []string {
+	if len(used) == 0 {
+		if n == 0 {
+			return []string{}
+		}
+		return []string{"..."}
+	}
+	lastLeft := -1
+	firstRight := n
+	for i := range used {
+		if i < (n+1)/2 {
+			if i > lastLeft {
+				lastLeft = i
+			}
+		} else if i < firstRight {
+			firstRight = i
+		}
+	}
+	keys := make([]string, 0, n)
+	for i := 0; i <= lastLeft; i++ {
+		keys = append(keys, keyOrSkip(used, i))
+	}
+	if lastLeft+1 < firstRight {
+		keys = append(keys, "...")
+	}
+	for i := firstRight; i < n; i++ {
+		keys = append(keys, keyOrSkip(used, i))
+	}
+	return keys
+}
+
+func keyOrSkip(used map[int]string, i int) string {
+	if k, ok := used[i]; ok {
+		return k
+	}
+	return "_"
+}
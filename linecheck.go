@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gioui.org/font/gofont"
+	"gioui.org/op"
+	"gioui.org/unit"
+	"gioui.org/x/richtext"
+)
+
+// lineCheck는 입력 에디터의 한 줄을 검사한 결과이다.
+type lineCheck struct {
+	Line int
+	// Status는 "ok", "not found", "unparsable" 중 하나이다.
+	Status string
+	Detail string
+}
+
+// lineCheckDelay는 입력이 멈춘 뒤 줄 검사를 시작하기까지 기다리는 시간이다.
+const lineCheckDelay = 300 * time.Millisecond
+
+// lineCheckResult는 다른 고루틴에서 한 줄 검사의 결과와 그 동안 새로 확인한 경로들의 존재 여부이다.
+type lineCheckResult struct {
+	checks []lineCheck
+	exists map[string]bool
+}
+
+// updateLineChecks는 예약된 줄 검사의 시각이 되면 다른 고루틴에서 검사를 시작하고,
+// 끝난 검사의 결과를 입력 에디터 옆에 보여준다.
+func (ui *UI) updateLineChecks(gtx C) {
+	select {
+	case r := <-ui.lineChecks:
+		ui.lineChecks = nil
+		ui.LineChecks = lineCheckSpans(r.checks)
+		if ui.existCache == nil {
+			ui.existCache = make(map[string]bool)
+		}
+		for path, ok := range r.exists {
+			ui.existCache[path] = ok
+		}
+	default:
+	}
+	if ui.lineCheckAt.IsZero() {
+		return
+	}
+	if gtx.Now.Before(ui.lineCheckAt) {
+		gtx.Execute(op.InvalidateCmd{At: ui.lineCheckAt})
+		return
+	}
+	ui.lineCheckAt = time.Time{}
+	p, err := ui.scratchProgram()
+	if err != nil {
+		ui.LineChecks = nil
+		ui.lineChecks = nil
+		return
+	}
+	// 다른 고루틴에서 UI의 캐시를 건드리지 않도록 복사해 쓰고, 새로 확인한 것은 결과와 함께 돌려받는다.
+	cache := make(map[string]bool, len(ui.existCache))
+	for path, ok := range ui.existCache {
+		cache[path] = ok
+	}
+	text := ui.InputEditor.Text()
+	// 이전 검사의 결과는 더이상 필요 없으므로 새 채널로 바꿔 버린다.
+	ch := make(chan lineCheckResult, 1)
+	ui.lineChecks = ch
+	go func() {
+		found := make(map[string]bool)
+		exists := func(path string) (bool, error) {
+			if p.Offline {
+				return true, nil
+			}
+			if ok, checked := cache[path]; checked {
+				return ok, nil
+			}
+			ok, err := statExists(path)
+			if err != nil {
+				return false, err
+			}
+			cache[path] = ok
+			found[path] = ok
+			return ok, nil
+		}
+		ch <- lineCheckResult{checks: checkInputLines(p, text, exists), exists: found}
+		ui.Window.Invalidate()
+	}()
+}
+
+// checkInputLines는 입력 텍스트의 각 경로 줄이 존재하고 대상 경로를 만들 수 있는지 검사한다.
+// 경로가 아닌 줄은 결과에 포함하지 않는다.
+// exists는 경로가 존재하는지 확인하며, 매 입력마다 파일시스템을 다시 보지 않도록 캐시된 함수를 쓸 수 있다.
+func checkInputLines(p *Program, text string, exists func(path string) (bool, error)) []lineCheck {
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	checks := make([]lineCheck, 0)
+	for i, l := range strings.Split(text, "\n") {
+		paths := inputPaths(l)
+		if len(paths) == 0 {
+			continue
+		}
+		c := lineCheck{Line: i + 1}
+		src := p.sourcePath(paths[0])
+		if hasGlobMeta(src) {
+			if ok, _ := exists(src); !ok {
+				matches, err := expandGlob(src)
+				if err != nil || len(matches) == 0 {
+					c.Status = "not found"
+					c.Detail = "no match"
+					checks = append(checks, c)
+					continue
+				}
+				c.Detail = strconv.Itoa(len(matches)) + " matches"
+				src = matches[0]
+			}
+		}
+		src = existingForm(src, exists)
+		ok, err := exists(src)
+		if err != nil {
+			c.Status = "not found"
+			c.Detail = err.Error()
+			checks = append(checks, c)
+			continue
+		}
+		if !ok {
+			c.Status = "not found"
+			checks = append(checks, c)
+			continue
+		}
+		env, err := p.Env(src)
+		if err == nil {
+			err = p.checkVocabulary(env)
+		}
+		if err == nil {
+			_, err = p.destDirectoryFor(src, env)
+		}
+		if err != nil {
+			c.Status = "unparsable"
+			c.Detail = err.Error()
+			checks = append(checks, c)
+			continue
+		}
+		c.Status = "ok"
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// statExists는 path가 존재하는지 확인한다.
+func statExists(path string) (bool, error) {
+	_, err := safeStat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// lineCheckSpans는 줄 검사 결과를 입력 에디터 옆에 표시할 수 있게 만든다.
+func lineCheckSpans(checks []lineCheck) []richtext.SpanStyle {
+	res := make([]richtext.SpanStyle, 0, len(checks)*2)
+	for _, c := range checks {
+		mark := "✓ "
+		clr := colors.OK
+		switch c.Status {
+		case "not found":
+			mark = "✗ "
+			clr = colors.Error
+		case "unparsable":
+			mark = "! "
+			clr = colors.Warning
+		}
+		text := strconv.Itoa(c.Line) + " " + mark + c.Status
+		if c.Detail != "" {
+			text += ": " + c.Detail
+		}
+		res = append(res, richtext.SpanStyle{
+			Content: text + "\n",
+			Color:   clr,
+			Size:    unit.Sp(13),
+			Font:    gofont.Collection()[0].Font,
+		})
+	}
+	return res
+}
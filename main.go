@@ -1,23 +1,24 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"image/color"
+	"io"
 	"io/fs"
 	"log"
+	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"reflect"
-	"runtime"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"gioui.org/app"
-	"gioui.org/font/gofont"
 	"gioui.org/layout"
 	"gioui.org/op"
 	"gioui.org/text"
@@ -40,17 +41,164 @@ type Config struct {
 	NameSepBy string
 	NameKeys  string
 	Dest      string
+	// Symlinks는 디렉토리 소스 안의 심볼릭 링크 처리 방법이다.
+	// "follow"는 링크가 가리키는 내용을 복사하고, "keep"은 대상 경로에 같은 링크를 만든다.
+	Symlinks string
+	// ReadOnly가 참이면 복사된 파일을 읽기 전용으로 바꾼다.
+	ReadOnly bool
+	// ShowRegistry는 쇼 코드별 저장소 루트를 정의한 파일 경로 또는 http(s) 주소이다.
+	// 설정되어 있으면 대상 경로 패턴에서 ${SHOWROOT}를 사용할 수 있다.
+	ShowRegistry string
+	// ParseMode는 경로에서 키를 찾는 방법이다.
+	// "split"은 구분자와 키 목록을, "regex"는 이름 있는 캡쳐 그룹을 가진 정규식을 사용한다.
+	ParseMode string
+	PathRegex string
+	NameRegex string
+	// Profile은 지금 쓰는 프로필의 이름으로, 비어있으면 설정 파일 맨 위의 값들을 쓴다.
+	Profile string
+	// Profiles는 이름별 프로필들로, 키 분석 방법과 대상 경로 패턴을 쇼나 업체마다 따로 둔다.
+	Profiles map[string]*Profile
+	// LastDest는 기본 프로필로 마지막으로 복사를 실행했을 때의 대상 경로 패턴이다.
+	// 패턴이 바뀌었다면 분석 결과에 경고를 보여준다. 다른 프로필의 것은 Profile.LastDest에 있다.
+	LastDest string
+	// DateFormat은 대상 경로 패턴의 ${DATE} 형식이다.
+	// 2006-01-02 같은 Go의 레이아웃이나 yyyyMMdd 같은 형식을 쓸 수 있다.
+	DateFormat string
+	// TimeZone은 ${DATE}와 ${TIME}을 정할 시간대로, Asia/Seoul 같은 이름을 쓴다.
+	// 비어있으면 컴퓨터의 시간대를 사용한다.
+	TimeZone string
+	// Routes는 소스의 이름이나 분석한 값에 따라 다른 대상 경로 패턴을 쓰기 위한 규칙들로, 순서대로 확인된다.
+	// 맞는 규칙이 없는 소스에는 Dest가 쓰인다.
+	Routes []Route
+	// Vocabulary는 키별로 허용되는 값들이다. 규칙에 맞지 않는 값을 가진 소스는 유효하지 않은 것으로 본다.
+	Vocabulary map[string]KeyRule
+	// Remap은 분석한 값을 대상 경로에 넣기 전에 바꿀 값들로, stringMapper의 형식을 따른다.
+	// 예) "DEL,delivery,PRV,preview"
+	Remap string
+	// Vars는 모든 대상 경로 패턴에서 쓸 수 있는 고정된 값들이다.
+	// 예) STUDIO = "stormfx"
+	Vars map[string]string
+	// OSEnv는 대상 경로 패턴에서 쓸 수 있는 프로세스 환경 변수들의 이름이다.
+	// 목록에 없는 환경 변수는 쓸 수 없다.
+	OSEnv []string
+	// Roots는 대상 경로 패턴에서 @name 으로 쓸 수 있는 루트 경로들이다.
+	// 예) storm = "/mnt/storm"
+	Roots map[string]string
+	// Translate는 운영체제별로 다르게 마운트된 저장소의 루트들로, 입력 경로와 대상 경로 패턴에 적용된다.
+	Translate []PathTranslation
+	// Rewrites는 분석 전에 입력 경로에 적용할 규칙들로, 처음으로 맞는 규칙 하나만 적용된다.
+	Rewrites []Rewrite
+	// Excludes는 디렉토리 소스 안에서 복사하지 않을 파일과 디렉토리 이름의 글롭 패턴들로, 공백으로 구분한다.
+	// 예) ".DS_Store Thumbs.db *.tmp ._*"
+	Excludes string
+	// Includes가 설정되면 디렉토리 소스 안에서 이 글롭 패턴들 중 하나에 맞는 파일만 복사한다.
+	// 예) "*.exr *.mov"
+	Includes string
+	// MaxDepth는 디렉토리 소스를 방문할 깊이이다. 1이면 소스 바로 아래의 파일들만 복사한다.
+	// 0이면 제한하지 않는다.
+	MaxDepth int
+	// SkipHidden가 참이면 디렉토리 소스 안의 숨김 파일과 디렉토리를 복사하지 않는다.
+	SkipHidden bool
+	// Flatten이 참이면 디렉토리 소스 안의 하위 디렉토리 구조를 없애고 모든 파일을 대상 디렉토리 바로 아래에 복사한다.
+	Flatten bool
+	// Renumber는 복사할 때 시퀀스 프레임 번호를 바꾸는 방법으로, parseRenumber의 형식을 따른다.
+	// 예) "1001"은 첫 프레임을 1001로, "+100"은 모든 프레임에 100을 더한다.
+	Renumber string
+	// FramePad가 0보다 크면 복사할 때 시퀀스 프레임 번호를 이 자릿수가 되도록 0으로 채운다.
+	// 프로필마다 다르게 정할 수 있다.
+	FramePad int
+	// Card는 디렉토리 소스를 카메라 카드로 볼 때의 구조로, cardPresets의 이름 또는 "auto"이다.
+	// 비어있으면 카메라 카드로 보지 않는다.
+	Card string
+	// Probe가 참이면 미디어 파일을 ffprobe로 분석해 ${RES}, ${FPS}, ${CODEC}을 쓸 수 있게 한다.
+	Probe bool
+	// FFprobe는 ffprobe 실행 파일의 경로로, 비어있으면 PATH에서 찾는다.
+	FFprobe string
+	// ExrHeaders가 참이면 EXR 파일의 헤더에서 ExrAttrs 속성들을 읽어 ${EXR_OWNER} 같은 변수로 쓸 수 있게 한다.
+	ExrHeaders bool
+	// ExrAttrs는 읽을 EXR 헤더 속성들의 이름으로, 비어있으면 owner, comments, dataWindow를 읽는다.
+	ExrAttrs []string
+	// ExifDate가 참이면 사진 파일의 EXIF 촬영 날짜를 ${SHOT_DATE}로 쓸 수 있게 한다.
+	// 형식은 ${DATE}와 같다.
+	ExifDate bool
+	// Normalize는 대상 경로 이름의 유니코드 정규화 형식으로, "NFC", "NFD", "none" 중 하나이다.
+	// 입력된 경로가 없다면 다른 정규화 형식의 같은 경로도 찾아본다.
+	Normalize string
+	// Sanitize가 참이면 대상 경로에서 윈도우즈나 SMB 공유에서 쓸 수 없는 문자를 _ 로 바꾼다.
+	// 거짓이면 그런 대상 경로를 가진 소스를 유효하지 않은 것으로 본다.
+	Sanitize bool
+	// MaxPathLength는 대상 경로의 최대 길이로, 0이면 검사하지 않는다.
+	MaxPathLength int
+	// StatTimeout은 파일 정보를 기다리는 최대 시간(초)이다. 이 시간 안에 응답하지 않는 마운트 아래의 경로는
+	// 분석하지 않고 "mount not responding"으로 알린다. 0이면 제한 없이 기다린다.
+	StatTimeout int
+	// Offline이 참이면 파일시스템을 전혀 확인하지 않고 경로 분석과 대상 경로만 계산한다.
+	// 다른 곳에 있는 경로들의 이름 규칙을 미리 확인하기 위한 것으로, 복사는 할 수 없다.
+	Offline bool
+	// StableWait이 0보다 크면 복사하기 전에 이 시간(초) 동안 소스 파일들의 크기와 수정 시간이 바뀌지 않는지 확인한다.
+	// 바뀐 파일이 있다면 아직 쓰이고 있는 것으로 보고 복사하지 않는다.
+	StableWait int
+	// WaitMissing이 참이면 분석한 뒤 존재하지 않는 소스들이 생겼는지 계속 확인하고, 생기면 다시 분석해 복사할 목록에 넣는다.
+	WaitMissing bool
+	// WaitInterval은 존재하지 않는 소스들을 다시 확인하기까지 기다리는 시간(초)으로, 0이면 5초이다.
+	WaitInterval int
+	// HashHistory가 참이면 복사한 파일의 크기와 SHA-256 해시를 작업 기록에 남기고,
+	// 분석할 때 이미 다른 곳으로 복사된 것과 같은 내용의 소스를 알린다.
+	HashHistory bool
+	// TestFiles는 시험 실행에서 대상 경로마다 복사할 파일의 수로, 0이면 3개이다.
+	TestFiles int
+	// Thumbnails가 참이면 분석 화면에서 이미지와 비디오 소스의 썸네일을 보여준다.
+	Thumbnails bool
+	// FFmpeg는 썸네일을 만들 ffmpeg 실행 파일의 경로로, 비어있으면 PATH에서 찾는다.
+	FFmpeg string
+	// ContinueOnError가 참이면 복사하지 못한 파일이 있어도 멈추지 않고 나머지 파일들을 복사한다.
+	ContinueOnError bool
+	// HighContrast가 참이면 대비를 높인 색과 두꺼운 테두리를 쓴다.
+	HighContrast bool
+	// Language는 화면에 보여줄 언어로, "en"(영어) 또는 "ko"(한국어)이다. 비어있으면 영어이다.
+	Language string
+	// WindowWidth와 WindowHeight는 처음 여는 창의 크기(dp)로, 0이면 Gio의 기본 크기이다.
+	WindowWidth  float32
+	WindowHeight float32
+	// FixedWindow가 참이면 지난번 창의 크기와 위치를 쓰지 않고 항상 WindowWidth, WindowHeight로 연다.
+	FixedWindow bool
+	// LastWindow는 지난번에 창을 닫을 때의 크기와 위치로, 프로그램이 기록한다.
+	LastWindow windowGeometry
+	// InputSplit은 입력 에디터가 줄 검사 목록과 나누어 쓰는 너비 중 입력 에디터의 비율로, 프로그램이 기록한다.
+	// 0이면 줄 검사 목록을 240dp로 둔다.
+	InputSplit float32
+	// UIScale은 화면 전체의 배율로, 0이면 운영체제가 알려주는 배율을 그대로 쓴다.
+	// Ctrl+, Ctrl-로 바꿀 수 있다.
+	UIScale float32
+	// FontSize는 기본 글자 크기(sp)로, 0이면 16이다. 다른 글자들도 같은 비율로 바뀐다.
+	FontSize float32
+	// Fonts는 Go 글꼴에 없는 글자(한글 등)를 그릴 글꼴 파일(.ttf, .otf, .ttc)들이다.
+	// 비어있으면 운영체제에 있는 한글 글꼴을 찾아 쓴다.
+	Fonts []string
+	// Opener는 결과 화면에서 누른 경로를 열 명령과 그 인자들로, ${PATH}와 ${DIR}를 쓸 수 있다.
+	// 비어있으면 운영체제의 파일 관리자로 보여준다.
+	// 예) ["rv", "${PATH}"]
+	Opener []string
 }
 
 // UI는 프로그램 UI 구성에 필요한 정보들이다.
 type UI struct {
 	Program             *Program
 	Window              *app.Window
+	Config              *Config
 	ConfigFile          string
 	PathSeparatorEditor *widget.Editor
 	PathKeyEditor       *widget.Editor
 	NameSeparatorEditor *widget.Editor
 	NameKeyEditor       *widget.Editor
+	PathRegexEditor     *widget.Editor
+	NameRegexEditor     *widget.Editor
+	RemapEditor         *widget.Editor
+	ExcludeEditor       *widget.Editor
+	IncludeEditor       *widget.Editor
+	MaxDepthEditor      *widget.Editor
+	RenumberEditor      *widget.Editor
+	FramePadEditor      *widget.Editor
 	InputEditor         *widget.Editor
 	DestEditor          *widget.Editor
 	List                *widget.List
@@ -58,16 +206,124 @@ type UI struct {
 	ResultState         richtext.InteractiveText
 	Theme               *material.Theme
 	AnalyzeButton       *widget.Clickable
-	CancelButton        *widget.Clickable
-	RunButton           *widget.Clickable
-	OKButton            *widget.Clickable
-	FromRadio           *widget.Enum
-	MethodRadio         *widget.Enum
-	Notifier            *widget.Editor
-	NotifyIsError       bool
-	BorderColor         color.NRGBA
-	DestColor           color.NRGBA
-	DestHintColor       color.NRGBA
+	SuggestButton       *widget.Clickable
+	PreviewButton       *widget.Clickable
+	BrowseButton        *widget.Clickable
+	BrowseDirsButton    *widget.Clickable
+	BrowseDestButton    *widget.Clickable
+	ToolsButton         *widget.Clickable
+	LearnButton         *widget.Clickable
+	LearnSrcEditor      *widget.Editor
+	LearnDestEditor     *widget.Editor
+	// CompareSrcEditor와 CompareDestEditor는 비교할 소스 디렉토리와 그것이 복사된 대상 디렉토리를 받는다.
+	CompareSrcEditor  *widget.Editor
+	CompareDestEditor *widget.Editor
+	CompareButton     *widget.Clickable
+	// ManifestEditor는 소스들을 확인할 업체의 해시 목록(MHL 또는 md5sum 목록) 파일 경로를 받는다.
+	ManifestEditor *widget.Editor
+	CompareResult  string
+	// SandboxEditor는 입력 경로들과 상관없이 패턴을 시험해 볼 경로를 받는다.
+	SandboxEditor  *widget.Editor
+	SandboxResult  string
+	CancelButton   *widget.Clickable
+	SavePlanButton *widget.Clickable
+	RunButton      *widget.Clickable
+	TestButton     *widget.Clickable
+	OKButton       *widget.Clickable
+	FromRadio      *widget.Enum
+	MethodRadio    *widget.Enum
+	SymlinkRadio   *widget.Enum
+	ParseRadio     *widget.Enum
+	// ProfileRadio는 키 분석 방법과 대상 경로 패턴을 가져올 프로필을 고른다.
+	ProfileRadio  *widget.Enum
+	ReadOnlyCheck *widget.Bool
+	HiddenCheck   *widget.Bool
+	FlattenCheck  *widget.Bool
+	OfflineCheck  *widget.Bool
+	WaitCheck     *widget.Bool
+	ReingestCheck *widget.Bool
+	ContinueCheck *widget.Bool
+	CardRadio     *widget.Enum
+	ProbeCheck    *widget.Bool
+	ExrCheck      *widget.Bool
+	ExifCheck     *widget.Bool
+	Notifier      *widget.Editor
+	NotifyIsError bool
+	BorderColor   color.NRGBA
+	DestColor     color.NRGBA
+	DestHintColor color.NRGBA
+	// ShowRoots는 마지막으로 읽은 쇼 레지스트리이다.
+	ShowRoots map[string]string
+	// ShowTools는 설정을 돕는 도구들을 보여줄지 여부이다.
+	ShowTools bool
+	// ShowPreview가 참이면 입력 에디터 대신 경로 토큰 미리보기를 보여준다.
+	ShowPreview  bool
+	Preview      []richtext.SpanStyle
+	PreviewState richtext.InteractiveText
+	// LineChecks는 입력 에디터의 각 경로 줄을 검사한 결과로, 에디터 옆에 표시된다.
+	LineChecks     []richtext.SpanStyle
+	LineCheckState richtext.InteractiveText
+	LineCheckList  *widget.List
+	// existCache는 줄 검사에서 확인한 경로의 존재 여부이다.
+	// 입력할 때마다 파일시스템을 다시 확인하지 않도록 다음 분석 전까지 유지한다.
+	existCache map[string]bool
+	// lineCheckAt은 예약된 줄 검사를 시작할 시각으로, 예약된 검사가 없다면 0이다.
+	lineCheckAt time.Time
+	// lineChecks는 다른 고루틴에서 줄 검사한 결과를 UI 고루틴으로 전달한다.
+	lineChecks chan lineCheckResult
+	// counts는 디렉토리 소스를 센 결과를 UI 고루틴으로 전달한다.
+	counts chan dirCount
+	// stopCount는 진행중인 디렉토리 세기를 멈춘다.
+	stopCount context.CancelFunc
+	// appeared는 기다리던 소스들 중 새로 생긴 경로들을 UI 고루틴으로 전달한다.
+	appeared chan []string
+	// stopWait는 존재하지 않는 소스들을 기다리는 것을 멈춘다.
+	stopWait context.CancelFunc
+	// drops는 창에 끌어다 놓은 경로들을 UI 고루틴으로 전달한다.
+	drops chan []string
+	// chosen은 파일 선택 창에서 고른 결과를 UI 고루틴으로 전달한다.
+	chosen chan chooseResult
+	// browsing은 파일 선택 창이 떠 있는지 여부이다.
+	browsing bool
+	// OverrideSrc는 대상 디렉토리를 고치고 있는 소스이다. 고치고 있지 않다면 비어 있다.
+	OverrideSrc     string
+	OverrideEditor  *widget.Editor
+	ApplyDestButton *widget.Clickable
+	CloseDestButton *widget.Clickable
+	// ConfirmText는 복사하기 전에 확인을 받을 내용이다. 비어 있다면 확인 창을 띄우지 않는다.
+	ConfirmText       string
+	ConfirmRunButton  *widget.Clickable
+	ConfirmBackButton *widget.Clickable
+	// View는 분석 화면을 어떻게 보여줄지에 대한 설정이다.
+	View         *reportView
+	FilterEditor *widget.Editor
+	GroupRadio   *widget.Enum
+	CopyButton   *widget.Clickable
+	ExportButton *widget.Clickable
+	ThumbCheck   *widget.Bool
+	// ResultLines는 썸네일과 함께 분석 화면을 줄 단위로 그릴 때 각 줄의 상태이다.
+	ResultLines []*richtext.InteractiveText
+	// thumbs는 만들었거나 만들고 있는 썸네일들이다. 만들고 있는 경로의 값은 nil이다.
+	thumbs     map[string]*thumbnail
+	thumbDone  chan *thumbnail
+	thumbSlots chan struct{}
+	// RetryButton은 마지막 복사 작업에서 실패한 파일들만 다시 복사한다.
+	RetryButton *widget.Clickable
+	// copyDone은 진행중인 복사 작업이 끝났을 때 그 에러를 전달한다. 복사중이 아니라면 nil이다.
+	copyDone chan error
+	copyFrom int
+	progress *copyProgress
+	// openDestButtons는 대상 디렉토리별 "Open destination" 버튼이다.
+	openDestButtons map[string]*widget.Clickable
+	dragHandles     map[string]*dragHandle
+	// LanguageButton은 화면의 언어를 다음 언어로 바꾼다.
+	LanguageButton *widget.Clickable
+	// window는 창을 닫을 때 저장할 창의 크기와 모드이다.
+	window windowState
+	// InputSplit은 입력 에디터와 줄 검사 목록 사이의 끌 수 있는 손잡이이다.
+	InputSplit *split
+	// help는 설정 칸들의 도움말 상태이다.
+	help helpAreas
 }
 
 // Result는 복사후 결과를 표시하기 위한 정보이다.
@@ -84,12 +340,23 @@ func (ui *UI) Loop() error {
 		e := ui.Window.Event()
 		switch e := e.(type) {
 		case app.DestroyEvent:
+			if err := ui.saveWindow(); err != nil {
+				log.Print(err)
+			}
 			return e.Err
+		case app.ConfigEvent:
+			ui.trackWindow(e.Config)
 		case app.FrameEvent:
+			// 창 크기는 화면 배율을 적용하기 전의 단위로 저장한다.
+			ui.window.pxPerDp = e.Metric.PxPerDp
 			gtx := app.NewContext(&ops, e)
+			ui.scaleMetric(&gtx)
 			ui.HandleEvent(gtx)
 			ui.Layout(gtx)
 			e.Frame(gtx.Ops)
+		case app.ViewEvent:
+			ui.acceptDrops(e)
+			ui.placeWindow(e)
 		}
 	}
 	return nil
@@ -98,8 +365,27 @@ func (ui *UI) Loop() error {
 // HandleEvent는 발생한 이벤트에 맞게 UI 상태를 수정한다.
 func (ui *UI) HandleEvent(gtx C) {
 	ui.NotifyIsError = false
+	ui.takeCopyDone()
+	ui.handleShortcuts(gtx)
 	dirty := false
-	for _, ed := range []*widget.Editor{ui.InputEditor, ui.DestEditor, ui.PathSeparatorEditor, ui.PathKeyEditor, ui.NameSeparatorEditor, ui.NameKeyEditor} {
+	if ui.ParseRadio.Update(gtx) {
+		dirty = true
+	}
+	if ui.ProfileRadio.Update(gtx) {
+		ui.switchProfile()
+		dirty = true
+	}
+	if ui.OfflineCheck.Update(gtx) {
+		dirty = true
+	}
+	if ui.WaitCheck.Update(gtx) {
+		if ui.WaitCheck.Value {
+			ui.startWaiting()
+		} else {
+			ui.stopWaiting()
+		}
+	}
+	for _, ed := range []*widget.Editor{ui.InputEditor, ui.DestEditor, ui.PathSeparatorEditor, ui.PathKeyEditor, ui.NameSeparatorEditor, ui.NameKeyEditor, ui.PathRegexEditor, ui.NameRegexEditor, ui.SandboxEditor, ui.RemapEditor, ui.ExcludeEditor, ui.IncludeEditor, ui.MaxDepthEditor, ui.RenumberEditor, ui.FramePadEditor} {
 		for {
 			event, ok := ed.Update(gtx)
 			if !ok {
@@ -110,30 +396,192 @@ func (ui *UI) HandleEvent(gtx C) {
 			}
 		}
 	}
-	ui.Program.PathSeps = strings.Fields(ui.PathSeparatorEditor.Text())
-	ui.Program.PathKeys = strings.Fields(ui.PathKeyEditor.Text())
-	ui.Program.NameSeps = strings.Fields(ui.NameSeparatorEditor.Text())
-	ui.Program.NameKeys = strings.Fields(ui.NameKeyEditor.Text())
-	ui.Program.DestPattern = ui.DestEditor.Text()
+	if ui.takeDrops() {
+		dirty = true
+	}
+	if ui.BrowseButton.Clicked(gtx) {
+		ui.browse(false)
+	}
+	if ui.BrowseDirsButton.Clicked(gtx) {
+		ui.browse(true)
+	}
+	if ui.BrowseDestButton.Clicked(gtx) && !ui.Locked() {
+		ui.browseDest()
+	}
+	if ui.takeChosen() {
+		dirty = true
+	}
+	for {
+		event, ok := ui.OverrideEditor.Update(gtx)
+		if !ok {
+			break
+		}
+		if _, ok := event.(widget.SubmitEvent); ok {
+			ui.applyOverride()
+		}
+	}
+	if ui.ApplyDestButton.Clicked(gtx) {
+		ui.applyOverride()
+	}
+	if ui.CloseDestButton.Clicked(gtx) {
+		ui.OverrideSrc = ""
+	}
+	for {
+		event, ok := ui.FilterEditor.Update(gtx)
+		if !ok {
+			break
+		}
+		if _, ok := event.(widget.ChangeEvent); ok && ui.Program.Analyzed && !ui.Program.Done {
+			ui.View.Filter = ui.FilterEditor.Text()
+			ui.Result = analyzeInput(ui.Program, ui.View)
+		}
+	}
+	if ui.CopyButton.Clicked(gtx) && ui.Program.Analyzed {
+		ui.copyReport(gtx)
+	}
+	if ui.ExportButton.Clicked(gtx) && ui.Program.Analyzed {
+		base, err := exportReport(ui.Program.report(), time.Now())
+		if err != nil {
+			ui.Notifier.SetText(explainError(err))
+			ui.NotifyIsError = true
+		} else {
+			ui.Notifier.SetText(fmt.Sprintf(tr("report exported: %s.csv and .json"), base))
+			ui.NotifyIsError = false
+		}
+	}
+	if ui.GroupRadio.Update(gtx) && ui.Program.Analyzed && !ui.Program.Done {
+		ui.View.Group = ui.GroupRadio.Value
+		ui.Result = analyzeInput(ui.Program, ui.View)
+	}
+	if ui.PreviewButton.Clicked(gtx) {
+		ui.ShowPreview = !ui.ShowPreview
+		dirty = true
+	}
 	if dirty {
 		ui.Validate()
+		ui.UpdateLineChecks()
+		ui.UpdateSandbox()
+		if ui.ShowPreview {
+			ui.UpdatePreview()
+		}
 	}
 	if ui.AnalyzeButton.Clicked(gtx) {
-		text := ui.InputEditor.Text()
-		ui.Program.InputText = text
-		err := ui.Program.AnalyzeInput(text)
+		// 분석 시점의 설정을 고정한다.
+		// 분석 이후 에디터를 수정하더라도 진행중인 작업에는 영향을 주지 않는다.
+		err := ui.applySettings(ui.Program)
+		// 분석 이후의 줄 검사는 경로의 존재 여부를 새로 확인한다.
+		// 분석 전에 시작한 줄 검사가 확인한 것도 버린다.
+		ui.existCache = nil
+		if ui.lineChecks != nil {
+			ui.lineChecks = nil
+			ui.UpdateLineChecks()
+		}
+		// 저장소 이전이 반영되도록 분석할 때마다 쇼 레지스트리를 새로 읽는다.
+		var roots map[string]string
+		if err == nil {
+			roots, err = loadShowRegistry(ui.Config.ShowRegistry)
+		}
+		if err == nil {
+			ui.ShowRoots = roots
+			ui.Program.ShowRoots = roots
+			ui.Program.PrevDestPattern = ui.Config.lastDest(ui.Config.Profile)
+			text := ui.InputEditor.Text()
+			ui.Program.InputText = text
+			err = ui.Program.AnalyzeInput(text)
+		}
 		if err != nil {
-			ui.Notifier.SetText(err.Error())
+			ui.Notifier.SetText(explainError(err))
 			ui.NotifyIsError = true
 		} else {
 			ui.Program.Analyzed = true
-			analyzed := analyzeInput(ui.Program)
+			// 다시 복사할지는 분석 결과를 보고 매번 새로 정한다.
+			ui.ReingestCheck.Value = false
+			ui.OverrideSrc = ""
+			ui.View.Collapsed = nil
+			// 분석할 때마다 파일이 바뀌었을 수 있으므로 썸네일을 새로 만든다.
+			ui.thumbs = make(map[string]*thumbnail)
+			ui.startCounting()
+			ui.startWaiting()
+			analyzed := analyzeInput(ui.Program, ui.View)
 			ui.Result = analyzed
-			ui.Notifier.SetText("path analyzed")
+			ui.Notifier.SetText(tr("path analyzed"))
 			ui.NotifyIsError = false
+			if ui.Program.DestPatternChanged() {
+				ui.Notifier.SetText(tr("path analyzed, but destination pattern changed since last run"))
+				ui.NotifyIsError = true
+			}
+			if len(ui.Program.NoSpace) != 0 {
+				ui.Notifier.SetText(tr("path analyzed, but not enough space at the destination"))
+				ui.NotifyIsError = true
+			}
+			if len(ui.Program.ManifestProblems) != 0 {
+				ui.Notifier.SetText(tr("path analyzed, but some files don't match the manifest"))
+				ui.NotifyIsError = true
+			}
+			if len(ui.Program.OverQuota) != 0 {
+				ui.Notifier.SetText(tr("path analyzed, but the batch would exceed a quota"))
+				ui.NotifyIsError = true
+			}
+			if len(ui.Program.NotWritable) != 0 {
+				ui.Notifier.SetText(tr("path analyzed, but some destinations are not writable"))
+				ui.NotifyIsError = true
+			}
+			if len(ui.Program.Reingested) != 0 {
+				ui.Notifier.SetText(tr("path analyzed, but some sources were already ingested"))
+				ui.NotifyIsError = true
+			}
+			if len(ui.Program.Duplicates) != 0 {
+				ui.Notifier.SetText(tr("path analyzed, but some files were already ingested elsewhere"))
+				ui.NotifyIsError = true
+			}
+			if len(ui.Program.CrossDevice) != 0 {
+				ui.Notifier.SetText(tr("path analyzed, but some destinations can't be linked across filesystems"))
+				ui.NotifyIsError = true
+			}
+			if len(ui.Program.CaseCollisions) != 0 {
+				ui.Notifier.SetText(tr("path analyzed, but some destinations differ only in case"))
+				ui.NotifyIsError = true
+			}
+			if len(ui.Program.Recursions) != 0 {
+				ui.Notifier.SetText(tr("path analyzed, but can't run: ") + ui.Program.Recursions[0])
+				ui.NotifyIsError = true
+			}
+		}
+	}
+	if ui.SuggestButton.Clicked(gtx) {
+		ui.Suggest()
+	}
+	if ui.ToolsButton.Clicked(gtx) {
+		ui.ShowTools = !ui.ShowTools
+	}
+	if ui.LanguageButton.Clicked(gtx) {
+		code, _ := nextLanguage()
+		setLanguage(code)
+		ui.Config.Language = code
+		// 보여주고 있는 결과도 바꾼 언어로 다시 만든다.
+		switch {
+		case ui.copying():
+		case ui.Program.Done:
+			ui.Result = analyzeCopy(ui.Program)
+		case ui.Program.Analyzed:
+			ui.Result = analyzeInput(ui.Program, ui.View)
+		default:
+			dirty = true
 		}
 	}
+	if ui.CompareButton.Clicked(gtx) {
+		ui.Compare()
+	}
+	// 추정한 설정은 바로 검사해 보여주지만, 추정하지 못한 이유를 검사 결과로 덮어쓰지 않는다.
+	if ui.LearnButton.Clicked(gtx) && ui.Learn() {
+		ui.Validate()
+	}
+	ui.updateCounts()
+	ui.updateWaiting()
+	ui.updateLineChecks(gtx)
 	if ui.OKButton.Clicked(gtx) {
+		ui.stopCounting()
+		ui.stopWaiting()
 		// make it ready to get a new input
 		ui.Program.Analyzed = false
 		ui.Program.Done = false
@@ -142,369 +590,1775 @@ func (ui *UI) HandleEvent(gtx C) {
 		ui.InputEditor = input
 	}
 	if ui.CancelButton.Clicked(gtx) {
+		ui.stopCounting()
+		ui.stopWaiting()
 		// let user modify input
 		ui.Program.Analyzed = false
 		ui.Program.Done = false
-		ui.Notifier.SetText("please modify your paths and analyze again")
+		ui.Notifier.SetText(tr("please modify your paths and analyze again"))
 		ui.NotifyIsError = false
 	}
-	if ui.RunButton.Clicked(gtx) {
-		err := ui.Program.Copy()
+	if ui.SavePlanButton.Clicked(gtx) {
+		file, err := savePlan(ui.Program.Plan())
 		if err != nil {
-			ui.Notifier.SetText(err.Error())
+			ui.Notifier.SetText(explainError(err))
 			ui.NotifyIsError = true
 		} else {
-			ui.Result = analyzeCopy(ui.Program)
-			ui.Notifier.SetText("done")
+			ui.Notifier.SetText(tr("plan saved: ") + file)
 			ui.NotifyIsError = false
-			ui.Program.Done = true
-			// save the lastest setting
-			cfg := &Config{
-				PathSepBy: ui.PathSeparatorEditor.Text(),
-				PathKeys:  ui.PathKeyEditor.Text(),
-				NameSepBy: ui.NameSeparatorEditor.Text(),
-				NameKeys:  ui.NameKeyEditor.Text(),
-				Dest:      ui.DestEditor.Text(),
-			}
-			os.MkdirAll(filepath.Dir(ui.ConfigFile), 0755)
-			f, err := os.Create(ui.ConfigFile)
-			if err != nil {
-				ui.Notifier.SetText(err.Error())
-				ui.NotifyIsError = true
-			}
-			err = toml.NewEncoder(f).Encode(cfg)
-			if err != nil {
-				ui.Notifier.SetText(err.Error())
-				ui.NotifyIsError = true
-			}
-			f.Close()
 		}
 	}
+	if ui.TestButton.Clicked(gtx) {
+		n := ui.Config.TestFiles
+		if n <= 0 {
+			n = defaultTestFiles
+		}
+		ui.run(n)
+	}
+	if ui.RunButton.Clicked(gtx) {
+		ui.requestRun()
+	}
+	if ui.RetryButton.Clicked(gtx) {
+		ui.retry()
+	}
+	if ui.ConfirmRunButton.Clicked(gtx) {
+		ui.ConfirmText = ""
+		ui.run(0)
+	}
+	if ui.ConfirmBackButton.Clicked(gtx) {
+		ui.ConfirmText = ""
+	}
+	ui.takeThumbs()
+	ui.openDests(gtx)
+	ui.dragDests(gtx)
+	ui.handleResult(gtx, &ui.ResultState)
+	for _, st := range ui.ResultLines {
+		ui.handleResult(gtx, st)
+	}
+	locked := ui.Locked()
+	for _, ed := range ui.settingEditors() {
+		ed.ReadOnly = locked
+	}
+	ui.BorderColor = colors.Border
+	ui.DestColor = colors.Text
+	ui.DestHintColor = colors.Hint
+	if locked {
+		ui.DestColor = colors.Disabled
+		ui.DestHintColor = color.NRGBA{}
+	}
+}
+
+// handleResult는 분석 화면에서 누른 경로나 표시에 맞게 동작한다.
+func (ui *UI) handleResult(gtx C, st *richtext.InteractiveText) {
 	for {
-		span, event, ok := ui.ResultState.Update(gtx)
+		span, event, ok := st.Update(gtx)
 		if !ok {
 			break
 		}
+		if key, ok := span.Get("collapse").(string); ok {
+			if event.Type == richtext.Click && !ui.Program.Done {
+				ui.View.toggle(key)
+				ui.Result = analyzeInput(ui.Program, ui.View)
+			}
+			continue
+		}
+		if src, ok := span.Get("override").(string); ok {
+			if event.Type == richtext.Click && !ui.Program.Done {
+				ui.editDest(src)
+			}
+			continue
+		}
+		if src, ok := span.Get("skip").(string); ok {
+			if event.Type == richtext.Click && !ui.Program.Done {
+				ui.Program.toggleSkip(src)
+				ui.Result = analyzeInput(ui.Program, ui.View)
+			}
+			continue
+		}
 		path, _ := span.Content()
+		// 시퀀스처럼 보여주는 내용과 실제 경로가 다른 경우
+		if p, ok := span.Get("path").(string); ok {
+			path = p
+		}
 		switch event.Type {
 		case richtext.Click:
-			openCmd := map[string]string{
-				"darwin": "open",
-				"linux":  "xdg-open",
-			}[runtime.GOOS]
-			if openCmd == "" {
-				return
-			}
-			cmd := exec.Command(openCmd, path)
-			err := cmd.Start()
+			err := ui.openResultPath(path)
 			if err != nil {
-				ui.Notifier.SetText(err.Error())
+				ui.Notifier.SetText(explainError(err))
 				ui.NotifyIsError = false
 			}
 		}
 	}
-	ui.DestEditor.ReadOnly = false
-	ui.BorderColor = color.NRGBA{R: 128, G: 128, B: 128, A: 255}
-	ui.DestColor = color.NRGBA{A: 255}
-	ui.DestHintColor = color.NRGBA{R: 128, G: 128, B: 128, A: 255}
-	if ui.Program.Analyzed || ui.Program.Done {
-		ui.DestEditor.ReadOnly = true
-		ui.DestColor = color.NRGBA{R: 160, G: 160, B: 160, A: 255}
-		ui.DestHintColor = color.NRGBA{}
-	}
 }
 
-func (ui *UI) Validate() {
-	dest := strings.TrimSpace(ui.DestEditor.Text())
-	if dest == "" {
-		ui.Notifier.SetText("please set destination")
-		ui.NotifyIsError = true
-		return
+// defaultTestFiles는 시험 실행에서 대상 경로마다 복사하는 기본 파일 수이다.
+const defaultTestFiles = 3
+
+// run은 분석한 소스들을 복사하고 그 결과를 보여준다.
+// sample이 0보다 크면 대상 경로마다 처음 sample개의 파일만 복사하는 시험 실행으로,
+// 분석 결과를 그대로 두어 확인한 뒤에 나머지를 복사할 수 있게 한다.
+func (ui *UI) run(sample int) {
+	ui.Program.Reingest = ui.ReingestCheck.Value
+	ui.Program.Sample = sample
+	ui.Program.ContinueOnError = ui.ContinueCheck.Value
+	ui.startCopy(0, ui.Program.Copy)
+}
+
+// retry는 마지막 복사 작업에서 실패한 파일들만 다시 복사하고 그 결과를 보여준다.
+func (ui *UI) retry() {
+	ui.Program.ContinueOnError = ui.ContinueCheck.Value
+	ui.startCopy(len(ui.Program.Copied), ui.Program.retryFailed)
+}
+
+// finishRun은 복사 작업의 결과를 보여준다. ui.Program.Copied에서 from 이후가 이번에 복사한 파일들이다.
+func (ui *UI) finishRun(from int, err error) {
+	sample := ui.Program.Sample
+	// 일부만 복사되고 실패했더라도 복사된 파일들은 기록한다.
+	if len(ui.Program.Copied) > from {
+		herr := ui.recordHistory(ui.Program.Copied[from:])
+		if herr != nil && err == nil {
+			err = fmt.Errorf(tr("record history: %v"), herr)
+		}
 	}
-	dest = os.ExpandEnv(dest)
-	if !strings.HasPrefix(dest, "/") {
-		ui.Notifier.SetText("destination path cannot be relative")
+	if err != nil {
+		if len(ui.Program.Outcomes) != 0 {
+			// 어떤 파일들이 복사되고 어떤 파일이 실패했는지 보여준다.
+			ui.Result = analyzeCopy(ui.Program)
+		}
+		ui.Notifier.SetText(explainError(err))
 		ui.NotifyIsError = true
 		return
 	}
-	if strings.TrimSpace(ui.InputEditor.Text()) == "" {
-		ui.Notifier.SetText("paste filepaths to take in.")
+	ui.Result = analyzeCopy(ui.Program)
+	if sample > 0 {
+		ui.Notifier.SetText(tr("test run done, check the copied files and run to copy the rest"))
 		ui.NotifyIsError = false
 		return
 	}
-	sampleSrc := ""
-	lines := strings.Split(ui.InputEditor.Text(), "\n")
-	for _, l := range lines {
-		l = strings.TrimPrefix(l, "file://")
-		if strings.HasPrefix(l, "/") {
-			sampleSrc = l
-			break
-		}
+	ui.Notifier.SetText(tr("done"))
+	ui.NotifyIsError = false
+	ui.Program.Done = true
+	// save the lastest setting
+	err = ui.saveConfig()
+	if err != nil {
+		ui.Notifier.SetText(explainError(err))
+		ui.NotifyIsError = true
 	}
-	if sampleSrc == "" {
-		ui.Notifier.SetText("filepath not found")
-		ui.NotifyIsError = false
-		return
+}
+
+// recordHistory는 복사한 파일들 files를 작업 기록에 남긴다.
+func (ui *UI) recordHistory(files []IngestFile) error {
+	p := ui.Program
+	now := time.Now()
+	host, _ := os.Hostname()
+	rec := &IngestRecord{
+		RunID:       newRunID(now),
+		Time:        now,
+		User:        currentUser(),
+		Host:        host,
+		Method:      p.Method,
+		DestPattern: p.DestPattern,
+		Files:       files,
 	}
-	env, err := ui.Program.ParseEnvsFromSrc(sampleSrc)
+	hist, err := historyFile()
 	if err != nil {
-		ui.Notifier.SetText("dest sample: " + err.Error())
-		return
+		return err
 	}
-	env["DATE"] = time.Now().Format("060102")
-	sampleDest, err := destDirectory(sampleSrc, ui.DestEditor.Text(), env)
-	if err != nil {
-		ui.Notifier.SetText("dest sample: " + err.Error())
-		return
+	return appendHistory(hist, rec)
+}
+
+// parseMaxDepth는 디렉토리 소스를 방문할 최대 깊이를 적은 텍스트를 분석한다. 빈 텍스트는 0(제한 없음)이다.
+func parseMaxDepth(text string) (int, error) {
+	d := strings.TrimSpace(text)
+	if d == "" {
+		return 0, nil
 	}
-	ui.Notifier.SetText("dest sample: " + sampleDest)
+	n, err := strconv.Atoi(d)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("max depth should be a positive number: %s", d)
+	}
+	return n, nil
 }
 
-// Layout은 현재 UI 상태에 따라 레이아웃을 설정한다.
-func (ui *UI) Layout(gtx C) D {
-	return layout.UniformInset(unit.Dp(4)).Layout(gtx, func(gtx C) D {
-		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
-			layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
-			layout.Rigid(func(gtx C) D {
-				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
-					layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, "separate path to ").Layout(gtx) }),
-					layout.Flexed(1, func(gtx C) D {
-						return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: unit.Dp(1)}.Layout(gtx, func(gtx C) D {
-							return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
-								med := material.Editor(ui.Theme, ui.PathKeyEditor, "path environ filter")
-								med.Color = ui.DestColor
-								med.HintColor = ui.DestHintColor
-								return med.Layout(gtx)
-							})
-						})
-					}),
-					layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, " with ").Layout(gtx) }),
-					layout.Rigid(func(gtx C) D {
-						return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: unit.Dp(1)}.Layout(gtx, func(gtx C) D {
-							return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
-								gtx.Constraints.Max.X = 150
-								med := material.Editor(ui.Theme, ui.PathSeparatorEditor, "separators (/ \\)")
-								med.Color = ui.DestColor
-								med.HintColor = ui.DestHintColor
-								return med.Layout(gtx)
-							})
-						})
-					}),
-				)
-			}),
-			layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
-			layout.Rigid(func(gtx C) D {
-				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
-					layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, "separate name to ").Layout(gtx) }),
-					layout.Flexed(1, func(gtx C) D {
-						return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: unit.Dp(1)}.Layout(gtx, func(gtx C) D {
-							return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
-								med := material.Editor(ui.Theme, ui.NameKeyEditor, "name environ filter")
-								med.Color = ui.DestColor
-								med.HintColor = ui.DestHintColor
-								return med.Layout(gtx)
-							})
-						})
-					}),
-					layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, " with ").Layout(gtx) }),
-					layout.Rigid(func(gtx C) D {
-						return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: unit.Dp(1)}.Layout(gtx, func(gtx C) D {
-							return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
-								gtx.Constraints.Max.X = 150
-								med := material.Editor(ui.Theme, ui.NameSeparatorEditor, "separators (_ .)")
-								med.Color = ui.DestColor
-								med.HintColor = ui.DestHintColor
-								return med.Layout(gtx)
-							})
-						})
-					}),
-				)
-			}),
-			layout.Rigid(layout.Spacer{Height: unit.Dp(10)}.Layout),
-			layout.Flexed(1, func(gtx C) D {
-				return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: unit.Dp(1)}.Layout(gtx, func(gtx C) D {
-					return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
-						if !ui.Program.Analyzed {
-							return material.Editor(ui.Theme, ui.InputEditor, "paths to copy").Layout(gtx)
-						} else {
-							return material.List(ui.Theme, ui.List).Layout(gtx, 1, func(gtx C, i int) D {
-								return richtext.Text(&ui.ResultState, ui.Theme.Shaper, ui.Result...).Layout(gtx)
-							})
-						}
-					})
-				})
-			}),
-			layout.Rigid(layout.Spacer{Height: unit.Dp(2)}.Layout),
-			layout.Rigid(func(gtx C) D {
-				return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: unit.Dp(1)}.Layout(gtx, func(gtx C) D {
-					return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
-						med := material.Editor(ui.Theme, ui.DestEditor, "destination folder")
-						med.Color = ui.DestColor
-						med.HintColor = ui.DestHintColor
-						return med.Layout(gtx)
-					})
-				})
-			}),
-			layout.Rigid(layout.Spacer{Height: unit.Dp(2)}.Layout),
-			layout.Rigid(func(gtx C) D {
-				childs := []layout.FlexChild{
-					layout.Rigid(material.RadioButton(ui.Theme, ui.MethodRadio, "link", "Link").Layout),
-					layout.Rigid(material.RadioButton(ui.Theme, ui.MethodRadio, "copy", "Copy").Layout),
-				}
-				childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(20)}.Layout))
-				childs = append(childs, layout.Flexed(1, layout.Spacer{}.Layout))
-				if ui.Program.Done {
-					childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.OKButton, "OK").Layout))
-				} else if ui.Program.Analyzed {
-					childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.CancelButton, "Cancel").Layout))
-					childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout))
-					childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.RunButton, "Run").Layout))
-				} else {
-					childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.AnalyzeButton, "Analyze").Layout))
-				}
-				return layout.Flex{}.Layout(gtx,
-					childs...,
-				)
-			}),
-			layout.Rigid(layout.Spacer{Height: unit.Dp(10)}.Layout),
-			layout.Rigid(func(gtx C) D {
-				return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: unit.Dp(1)}.Layout(gtx, func(gtx C) D {
-					med := material.Editor(ui.Theme, ui.Notifier, "")
-					if ui.NotifyIsError {
-						med.Color = color.NRGBA{R: 192, G: 32, B: 32, A: 255}
-					}
-					return layout.UniformInset(unit.Dp(2)).Layout(gtx,
-						med.Layout,
-					)
-				})
-			}),
-		)
-	})
+// parseFramePad는 시퀀스 프레임 번호를 채울 자릿수를 적은 텍스트를 분석한다. 빈 텍스트는 0(원래 자릿수)이다.
+func parseFramePad(text string) (int, error) {
+	d := strings.TrimSpace(text)
+	if d == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(d)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("frame padding should be a positive number: %s", d)
+	}
+	return n, nil
 }
 
-// Program은 받아들인 경로를 다양한 각도에서 분석한 정보이다.
-type Program struct {
-	InputText       string
-	PathSeps        []string
-	PathKeys        []string
-	NameSeps        []string
-	NameKeys        []string
-	DestPattern     string
-	Method          string
-	Analyzed        bool
-	Done            bool
-	NotExists       []string
-	Invalids        []string
-	Srcs            []string
-	SrcIsDir        map[string]bool
-	SrcDirFileCount map[string]int
-	DestDir         map[string]string
-	DestDirSrcs     map[string][]string
-	DestDirExists   map[string]bool
-	Today           string
+// saveConfig는 작업에 사용한 설정을 설정 파일에 저장한다.
+// 에디터로 수정할 수 없는 설정은 설정 파일에서 읽어들인 값을 그대로 유지한다.
+func (ui *UI) saveConfig() error {
+	cfg := ui.Config
+	cfg.setProfile(cfg.Profile, ui.editorProfile())
+	cfg.setLastDest(cfg.Profile, ui.Program.DestPattern)
+	cfg.Symlinks = ui.SymlinkRadio.Value
+	cfg.ReadOnly = ui.ReadOnlyCheck.Value
+	cfg.SkipHidden = ui.HiddenCheck.Value
+	cfg.Flatten = ui.FlattenCheck.Value
+	cfg.Offline = ui.OfflineCheck.Value
+	cfg.WaitMissing = ui.WaitCheck.Value
+	cfg.ContinueOnError = ui.ContinueCheck.Value
+	cfg.Remap = ui.RemapEditor.Text()
+	cfg.Excludes = ui.ExcludeEditor.Text()
+	cfg.Includes = ui.IncludeEditor.Text()
+	// 숫자 에디터의 값이 잘못되었다면 이전에 저장한 값을 그대로 둔다.
+	if n, err := parseMaxDepth(ui.MaxDepthEditor.Text()); err == nil {
+		cfg.MaxDepth = n
+	}
+	cfg.Renumber = ui.RenumberEditor.Text()
+	cfg.Card = ui.CardRadio.Value
+	cfg.Probe = ui.ProbeCheck.Value
+	cfg.ExrHeaders = ui.ExrCheck.Value
+	cfg.ExifDate = ui.ExifCheck.Value
+	cfg.Thumbnails = ui.ThumbCheck.Value
+	return writeConfig(ui.ConfigFile, cfg)
 }
 
-func (p *Program) ParseEnvsFromSrc(src string) (map[string]string, error) {
-	env := make(map[string]string)
-	pathEnv, err := parseEnvs(src, p.PathSeps, p.PathKeys)
+// writeConfig는 cfg를 설정 파일 path에 쓴다.
+func writeConfig(path string, cfg *Config) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	for k, v := range pathEnv {
-		env[k] = v
+	f, err := os.Create(path)
+	if err != nil {
+		return err
 	}
-	nameEnv, err := parseEnvs(filepath.Base(src), p.NameSeps, p.NameKeys)
+	defer f.Close()
+	return toml.NewEncoder(f).Encode(cfg)
+}
+
+// scratchProgram은 진행중인 작업의 설정은 건드리지 않고, 현재 에디터의 설정으로 미리보기용 프로그램을 만든다.
+func (ui *UI) scratchProgram() (*Program, error) {
+	p := new(Program)
+	err := ui.applySettings(p)
 	if err != nil {
 		return nil, err
 	}
-	for k, v := range nameEnv {
-		env[k] = v
-	}
-	return env, nil
+	p.ShowRoots = ui.ShowRoots
+	p.stamp()
+	return p, nil
 }
 
-// Analyze는 사용자가 입력한 텍스트를 받아들이고 그 안에서 경로를 찾아
+// startCounting은 분석한 디렉토리 소스들의 파일 수와 크기를 세고, 파일 소스들의 해시를 비교하는 것을 다른 고루틴에서 한다.
+// 하나를 끝낼 때마다 그 결과가 분석 결과에 반영된다.
+func (ui *UI) startCounting() {
+	ui.stopCounting()
+	p := ui.Program
+	srcs := make([]string, 0, len(p.SrcCounting)+len(p.SrcChecking))
+	for _, src := range p.Srcs {
+		if p.SrcCounting[src] || p.SrcChecking[src] {
+			srcs = append(srcs, src)
+		}
+	}
+	if len(srcs) == 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan dirCount, len(srcs))
+	ui.counts = ch
+	ui.stopCount = cancel
+	// 세는 동안 다시 분석하더라도 영향을 받지 않도록 지금의 설정을 복사해 쓴다.
+	// 대상 디렉토리와 링크 여부는 세는 동안 바뀔 수 있는 맵을 함께 쓰지 않도록 소스마다 미리 꺼내둔다.
+	snap := *p
+	destDirs := make([]string, len(srcs))
+	destExists := make([]bool, len(srcs))
+	linked := make([]bool, len(srcs))
+	for i, src := range srcs {
+		destDirs[i] = p.DestDir[src]
+		destExists[i] = p.DestDirExists[destDirs[i]]
+		linked[i] = p.SrcLinked[src]
+	}
+	go func() {
+		// 같은 저장소를 동시에 여러번 훑지 않도록 하나씩 센다.
+		for i, src := range srcs {
+			var c dirCount
+			if snap.SrcIsDir[src] {
+				c = snap.countDir(ctx, src, destDirs[i], destExists[i])
+			} else {
+				// 이미 링크된 파일은 작업 기록과 비교할 필요가 없다.
+				c = snap.checkFile(ctx, src, !linked[i])
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			ch <- c
+			ui.Window.Invalidate()
+		}
+	}()
+}
+
+// stopCounting은 진행중인 디렉토리 세기를 멈추고 아직 전달되지 않은 결과를 버린다.
+func (ui *UI) stopCounting() {
+	if ui.stopCount != nil {
+		ui.stopCount()
+	}
+	ui.stopCount = nil
+	ui.counts = nil
+}
+
+// updateCounts는 그동안 끝난 디렉토리 세기의 결과를 분석 결과에 반영하고 분석 화면을 새로 만든다.
+func (ui *UI) updateCounts() {
+	if ui.counts == nil || ui.copying() {
+		// 복사하는 동안에는 분석 결과를 바꾸지 않고 끝날 때까지 기다린다.
+		return
+	}
+	p := ui.Program
+	updated := false
+	for done := false; !done; {
+		select {
+		case c := <-ui.counts:
+			p.applyCount(c)
+			updated = true
+		default:
+			done = true
+		}
+	}
+	if !updated {
+		return
+	}
+	if len(p.SrcCounting) == 0 && len(p.SrcChecking) == 0 {
+		ui.stopCounting()
+		err := p.checkDiskSpace()
+		if err == nil {
+			err = p.checkQuota()
+		}
+		if err != nil {
+			ui.Notifier.SetText(explainError(err))
+			ui.NotifyIsError = true
+		} else if len(p.NoSpace) != 0 {
+			ui.Notifier.SetText(tr("files counted, but not enough space at the destination"))
+			ui.NotifyIsError = true
+		} else if len(p.PathProblems) != 0 {
+			ui.Notifier.SetText(tr("files counted, but some destination paths can't be created"))
+			ui.NotifyIsError = true
+		} else if len(p.OverQuota) != 0 {
+			ui.Notifier.SetText(tr("files counted, but the batch would exceed a quota"))
+			ui.NotifyIsError = true
+		} else if len(p.ManifestProblems) != 0 {
+			ui.Notifier.SetText(tr("files counted, but some files don't match the manifest"))
+			ui.NotifyIsError = true
+		} else if len(p.Duplicates) != 0 {
+			ui.Notifier.SetText(tr("files counted, but some files were already ingested elsewhere"))
+			ui.NotifyIsError = true
+		}
+	}
+	if p.Analyzed && !p.Done {
+		ui.Result = analyzeInput(p, ui.View)
+	}
+}
+
+// startWaiting은 WaitCheck가 켜져 있으면 분석한 결과의 존재하지 않는 소스들이 생기는지 다른 고루틴에서 기다린다.
+func (ui *UI) startWaiting() {
+	ui.stopWaiting()
+	p := ui.Program
+	if !ui.WaitCheck.Value || !p.Analyzed || p.Done || p.Offline || len(p.NotExists) == 0 {
+		return
+	}
+	interval := defaultWaitInterval
+	if ui.Config.WaitInterval > 0 {
+		interval = time.Duration(ui.Config.WaitInterval) * time.Second
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan []string, 1)
+	ui.appeared = ch
+	ui.stopWait = cancel
+	paths := append([]string(nil), p.NotExists...)
+	go watchMissing(ctx, paths, interval, ch, ui.Window.Invalidate)
+}
+
+// stopWaiting은 존재하지 않는 소스들을 기다리는 것을 멈춘다.
+func (ui *UI) stopWaiting() {
+	if ui.stopWait != nil {
+		ui.stopWait()
+	}
+	ui.stopWait = nil
+	ui.appeared = nil
+}
+
+// updateWaiting은 기다리던 소스가 생겼다면 분석할 때의 입력과 설정으로 다시 분석해 복사할 목록에 넣는다.
+// 아직 존재하지 않는 소스가 남아있다면 계속 기다린다.
+func (ui *UI) updateWaiting() {
+	if ui.appeared == nil {
+		return
+	}
+	var appeared []string
+	select {
+	case appeared = <-ui.appeared:
+	default:
+		return
+	}
+	ui.stopWaiting()
+	p := ui.Program
+	if !p.Analyzed || p.Done {
+		return
+	}
+	if err := p.reanalyze(); err != nil {
+		ui.Notifier.SetText(explainError(err))
+		ui.NotifyIsError = true
+		return
+	}
+	ui.startCounting()
+	ui.Result = analyzeInput(p, ui.View)
+	msg := fmt.Sprintf(tr("%s appeared, analyzed again"), appeared[0])
+	if len(appeared) > 1 {
+		msg = fmt.Sprintf(tr("%s and %d more appeared, analyzed again"), appeared[0], len(appeared)-1)
+	}
+	ui.Notifier.SetText(msg)
+	ui.NotifyIsError = false
+	ui.startWaiting()
+}
+
+// reanalyze는 같은 입력을 다시 분석한다.
+// 분석한 뒤에 작업에서 뺀 소스들과 직접 바꾼 대상 디렉토리는 소스 경로를 기준으로 다시 적용한다.
+func (p *Program) reanalyze() error {
+	skipped := p.SrcSkipped
+	overrides := make(map[string]string, len(p.DestOverridden))
+	for src := range p.DestOverridden {
+		overrides[src] = p.DestDir[src]
+	}
+	if err := p.AnalyzeInput(p.InputText); err != nil {
+		return err
+	}
+	for src := range skipped {
+		if _, ok := p.DestDir[src]; ok {
+			p.SrcSkipped[src] = true
+		}
+	}
+	srcs := make([]string, 0, len(overrides))
+	for src := range overrides {
+		srcs = append(srcs, src)
+	}
+	sortNatural(srcs)
+	for _, src := range srcs {
+		if old, ok := p.DestDir[src]; !ok || old == overrides[src] {
+			continue
+		}
+		if err := p.overrideDest(src, overrides[src]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateLineChecks는 입력 에디터의 각 경로 줄을 다시 검사하도록 예약한다.
+// 글롭을 펼치고 경로를 확인하는 데 시간이 걸리므로 입력이 lineCheckDelay 동안 멈춘 뒤에 다른 고루틴에서 검사한다.
+func (ui *UI) UpdateLineChecks() {
+	ui.lineCheckAt = time.Now().Add(lineCheckDelay)
+}
+
+// UpdateSandbox는 현재 에디터 설정으로 시험 경로를 분석한 결과를 새로 만든다.
+func (ui *UI) UpdateSandbox() {
+	if strings.TrimSpace(ui.SandboxEditor.Text()) == "" {
+		ui.SandboxResult = ""
+		return
+	}
+	p, err := ui.scratchProgram()
+	if err != nil {
+		ui.SandboxResult = tr("error: ") + err.Error()
+		return
+	}
+	ui.SandboxResult = sandboxResult(p, ui.SandboxEditor.Text())
+}
+
+// UpdatePreview는 현재 에디터 설정으로 입력 경로들의 토큰 미리보기를 새로 만든다.
+func (ui *UI) UpdatePreview() {
+	p, err := ui.scratchProgram()
+	if err != nil {
+		ui.Preview = []richtext.SpanStyle{richChanged(err.Error())}
+		return
+	}
+	paths, _, err := expandInputPaths(p.sourcePaths(inputPaths(ui.InputEditor.Text())))
+	if err != nil {
+		ui.Preview = []richtext.SpanStyle{richChanged(err.Error())}
+		return
+	}
+	ui.Preview = previewSpans(p, paths)
+}
+
+// Suggest는 입력된 경로들을 살펴 구분자와 키 설정을 제안하고 에디터에 채운다.
+func (ui *UI) Suggest() {
+	paths, _, err := expandInputPaths(inputPaths(ui.InputEditor.Text()))
+	if err != nil {
+		ui.Notifier.SetText(explainError(err))
+		ui.NotifyIsError = true
+		return
+	}
+	if len(paths) == 0 {
+		ui.Notifier.SetText(tr("paste sample paths to suggest separators and keys"))
+		ui.NotifyIsError = false
+		return
+	}
+	sug, err := suggestSplit(paths)
+	if err != nil {
+		ui.Notifier.SetText(explainError(err))
+		ui.NotifyIsError = true
+		return
+	}
+	ui.ParseRadio.Value = "split"
+	ui.PathSeparatorEditor.SetText(sug.PathSeps)
+	ui.PathKeyEditor.SetText(sug.PathKeys)
+	ui.NameSeparatorEditor.SetText(sug.NameSeps)
+	ui.NameKeyEditor.SetText(sug.NameKeys)
+	ui.Notifier.SetText(fmt.Sprintf(tr("suggested from %d paths, rename DIR/KEY placeholders to your keys"), len(paths)))
+	ui.NotifyIsError = false
+}
+
+// Learn은 예제 소스 경로와 원하는 대상 경로로부터 키 설정과 대상 경로 패턴을 추정해 에디터에 채운다.
+// 추정하지 못했다면 그 이유를 알리고 거짓을 반환한다.
+func (ui *UI) Learn() bool {
+	src := strings.TrimSpace(ui.LearnSrcEditor.Text())
+	if src == "" {
+		if paths := inputPaths(ui.InputEditor.Text()); len(paths) != 0 {
+			src = strings.TrimSpace(paths[0])
+		}
+	}
+	dest := strings.TrimSpace(ui.LearnDestEditor.Text())
+	if src == "" || dest == "" {
+		ui.Notifier.SetText(tr("learn needs a sample path and its desired destination"))
+		ui.NotifyIsError = true
+		return false
+	}
+	l, err := learnKeys(src, dest,
+		strings.Fields(ui.PathSeparatorEditor.Text()),
+		strings.Fields(ui.NameSeparatorEditor.Text()),
+		strings.Fields(ui.PathKeyEditor.Text()),
+		strings.Fields(ui.NameKeyEditor.Text()),
+	)
+	if err != nil {
+		ui.Notifier.SetText(explainError(err))
+		ui.NotifyIsError = true
+		return false
+	}
+	ui.ParseRadio.Value = "split"
+	ui.PathKeyEditor.SetText(strings.Join(l.PathKeys, " "))
+	ui.NameKeyEditor.SetText(strings.Join(l.NameKeys, " "))
+	ui.DestEditor.SetText(l.Dest)
+	return true
+}
+
+// maxCompareLines는 도구 화면에 보여줄 비교 결과의 최대 줄 수이다.
+const maxCompareLines = 20
+
+// Compare는 도구 화면에 입력된 소스 디렉토리와 대상 디렉토리를 비교해 그 결과를 보여준다.
+func (ui *UI) Compare() {
+	src := strings.TrimSpace(ui.CompareSrcEditor.Text())
+	dest := strings.TrimSpace(ui.CompareDestEditor.Text())
+	if src == "" || dest == "" {
+		ui.Notifier.SetText(tr("compare needs a source directory and its destination"))
+		ui.NotifyIsError = true
+		return
+	}
+	d, err := diffTrees(src, dest)
+	if err != nil {
+		ui.CompareResult = ""
+		ui.Notifier.SetText(explainError(err))
+		ui.NotifyIsError = true
+		return
+	}
+	if d.count() == 0 {
+		ui.CompareResult = tr("no differences")
+		return
+	}
+	lines := d.lines()
+	if len(lines) > maxCompareLines {
+		more := len(lines) - maxCompareLines
+		lines = append(lines[:maxCompareLines], fmt.Sprintf(tr("... and %d more, run takein diff for the full list"), more))
+	}
+	summary := fmt.Sprintf(tr("%d missing, %d extra, %d size differs"), len(d.Missing), len(d.Extra), len(d.SizeDiffers))
+	ui.CompareResult = summary + "\n" + strings.Join(lines, "\n")
+}
+
+// Locked는 분석되었거나 복사가 끝난 작업이 있어 설정을 수정할 수 없는 상태인지 확인한다.
+// 설정을 다시 수정하려면 Cancel 또는 OK를 눌러 다음 작업으로 넘어가야 한다.
+func (ui *UI) Locked() bool {
+	return ui.Program.Analyzed || ui.Program.Done
+}
+
+// settingEditors는 작업 설정에 쓰이는 에디터들이다.
+func (ui *UI) settingEditors() []*widget.Editor {
+	return []*widget.Editor{ui.PathSeparatorEditor, ui.PathKeyEditor, ui.NameSeparatorEditor, ui.NameKeyEditor, ui.PathRegexEditor, ui.NameRegexEditor, ui.DestEditor, ui.RemapEditor, ui.ExcludeEditor, ui.IncludeEditor, ui.MaxDepthEditor, ui.RenumberEditor, ui.FramePadEditor}
+}
+
+// applySettings는 현재 에디터에 입력된 설정을 p에 복사한다.
+// 정규식 설정에 문제가 있다면 에러를 반환한다.
+func (ui *UI) applySettings(p *Program) error {
+	p.PathSeps = strings.Fields(ui.PathSeparatorEditor.Text())
+	p.PathKeys = strings.Fields(ui.PathKeyEditor.Text())
+	p.NameSeps = strings.Fields(ui.NameSeparatorEditor.Text())
+	p.NameKeys = strings.Fields(ui.NameKeyEditor.Text())
+	p.DestPattern = ui.DestEditor.Text()
+	p.Remap = stringMapper(strings.TrimSpace(ui.RemapEditor.Text()))
+	p.Vars = ui.Config.Vars
+	p.Roots = ui.Config.Roots
+	p.Translations = ui.Config.Translate
+	p.Rewrites = ui.Config.Rewrites
+	p.Manifest = strings.TrimSpace(ui.ManifestEditor.Text())
+	p.Excludes = strings.Fields(ui.ExcludeEditor.Text())
+	if err := checkGlobs(p.Excludes); err != nil {
+		return fmt.Errorf("exclude: %w", err)
+	}
+	p.Includes = strings.Fields(ui.IncludeEditor.Text())
+	if err := checkGlobs(p.Includes); err != nil {
+		return fmt.Errorf("include: %w", err)
+	}
+	depth, err := parseMaxDepth(ui.MaxDepthEditor.Text())
+	if err != nil {
+		return err
+	}
+	p.MaxDepth = depth
+	renum, err := parseRenumber(ui.RenumberEditor.Text())
+	if err != nil {
+		return err
+	}
+	renum.Pad, err = parseFramePad(ui.FramePadEditor.Text())
+	if err != nil {
+		return err
+	}
+	p.Renumber = renum
+	p.OSEnv = make(map[string]string)
+	for _, k := range ui.Config.OSEnv {
+		if v, ok := os.LookupEnv(k); ok {
+			p.OSEnv[k] = v
+		}
+	}
+	routes, err := compileRoutes(ui.Config.Routes)
+	if err != nil {
+		return err
+	}
+	p.Routes = routes
+	p.Vocabulary, err = compileVocabulary(ui.Config.Vocabulary)
+	if err != nil {
+		return err
+	}
+	p.Method = ui.MethodRadio.Value
+	p.Symlinks = ui.SymlinkRadio.Value
+	p.ReadOnly = ui.ReadOnlyCheck.Value
+	p.SkipHidden = ui.HiddenCheck.Value
+	p.Flatten = ui.FlattenCheck.Value
+	p.Offline = ui.OfflineCheck.Value
+	p.Card = ui.CardRadio.Value
+	p.Probe = ui.ProbeCheck.Value
+	p.FFprobe = ui.Config.FFprobe
+	p.ExifDate = ui.ExifCheck.Value
+	p.Normalize, err = parseNormForm(ui.Config.Normalize)
+	if err != nil {
+		return err
+	}
+	p.Sanitize = ui.Config.Sanitize
+	p.MaxPathLen = ui.Config.MaxPathLength
+	p.StableWait = time.Duration(ui.Config.StableWait) * time.Second
+	p.HashHistory = ui.Config.HashHistory
+	p.ExrAttrs = nil
+	if ui.ExrCheck.Value {
+		p.ExrAttrs = ui.Config.ExrAttrs
+		if len(p.ExrAttrs) == 0 {
+			p.ExrAttrs = defaultExrAttrs
+		}
+	}
+	p.ParseMode = ui.ParseRadio.Value
+	p.DateLayout = dateLayout(ui.Config.DateFormat)
+	p.Location = nil
+	if tz := strings.TrimSpace(ui.Config.TimeZone); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return fmt.Errorf("time zone: %w", err)
+		}
+		p.Location = loc
+	}
+	p.PathRegexp = nil
+	p.NameRegexp = nil
+	if p.ParseMode != "regex" {
+		return nil
+	}
+	p.PathRegexp, err = compileKeyRegex(ui.PathRegexEditor.Text())
+	if err != nil {
+		return fmt.Errorf("path regex: %w", err)
+	}
+	p.NameRegexp, err = compileKeyRegex(ui.NameRegexEditor.Text())
+	if err != nil {
+		return fmt.Errorf("name regex: %w", err)
+	}
+	return nil
+}
+
+func (ui *UI) Validate() {
+	dest := strings.TrimSpace(ui.DestEditor.Text())
+	if dest == "" {
+		ui.Notifier.SetText(tr("please set destination"))
+		ui.NotifyIsError = true
+		return
+	}
+	dest, err := expandRoot(dest, ui.Config.Roots)
+	if err != nil {
+		ui.Notifier.SetText(explainError(err))
+		ui.NotifyIsError = true
+		return
+	}
+	dest = os.ExpandEnv(dest)
+	if !isAbsPath(dest) {
+		ui.Notifier.SetText(tr("destination path cannot be relative"))
+		ui.NotifyIsError = true
+		return
+	}
+	if strings.TrimSpace(ui.InputEditor.Text()) == "" {
+		ui.Notifier.SetText(tr("paste filepaths to take in."))
+		ui.NotifyIsError = false
+		return
+	}
+	p, err := ui.scratchProgram()
+	if err != nil {
+		ui.Notifier.SetText(explainError(err))
+		ui.NotifyIsError = true
+		return
+	}
+	sampleSrc := ""
+	if paths := p.sourcePaths(inputPaths(ui.InputEditor.Text())); len(paths) != 0 {
+		sampleSrc = paths[0]
+		// 글롭 패턴이라면 그 패턴에 맞는 첫 경로를 예로 든다.
+		if expanded, _, err := expandInputPaths(paths[:1]); err == nil && len(expanded) != 0 {
+			sampleSrc = expanded[0]
+		}
+	}
+	if sampleSrc == "" {
+		ui.Notifier.SetText(tr("filepath not found"))
+		ui.NotifyIsError = false
+		return
+	}
+	env, err := p.Env(sampleSrc)
+	if err != nil {
+		ui.Notifier.SetText(tr("dest sample: ") + err.Error())
+		return
+	}
+	if err := p.checkVocabulary(env); err != nil {
+		ui.Notifier.SetText(tr("dest sample: ") + err.Error())
+		return
+	}
+	sampleDest, err := p.destDirectoryFor(sampleSrc, env)
+	if err != nil {
+		ui.Notifier.SetText(tr("dest sample: ") + err.Error())
+		return
+	}
+	ui.Notifier.SetText(tr("dest sample: ") + sampleDest)
+}
+
+// Layout은 현재 UI 상태에 따라 레이아웃을 설정한다.
+func (ui *UI) Layout(gtx C) D {
+	if ui.copying() {
+		return ui.layoutProgress(gtx, ui.layoutMain)
+	}
+	if ui.ConfirmText != "" {
+		return ui.layoutConfirm(gtx, ui.layoutMain)
+	}
+	return ui.layoutMain(gtx)
+}
+
+// layoutMain은 입력과 분석 결과, 작업 설정을 그린다.
+func (ui *UI) layoutMain(gtx C) D {
+	return layout.UniformInset(unit.Dp(4)).Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx C) D {
+				children := ui.profileChildren()
+				children = append(children,
+					layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr("find keys by ")).Layout(gtx) }),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutSetting(gtx, material.RadioButton(ui.Theme, ui.ParseRadio, "split", tr("Separators")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutSetting(gtx, material.RadioButton(ui.Theme, ui.ParseRadio, "regex", tr("Regex")).Layout)
+					}),
+					layout.Flexed(1, layout.Spacer{}.Layout),
+					layout.Rigid(func(gtx C) D {
+						_, name := nextLanguage()
+						btn := material.Button(ui.Theme, ui.LanguageButton, name)
+						btn.Inset = layout.UniformInset(unit.Dp(6))
+						return btn.Layout(gtx)
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout),
+					layout.Rigid(func(gtx C) D {
+						label := tr("Tools")
+						if ui.ShowTools {
+							label = tr("Hide tools")
+						}
+						btn := material.Button(ui.Theme, ui.ToolsButton, label)
+						btn.Inset = layout.UniformInset(unit.Dp(6))
+						return btn.Layout(gtx)
+					}),
+				)
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx, children...)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
+			layout.Rigid(func(gtx C) D {
+				if ui.ParseRadio.Value == "regex" {
+					return ui.layoutRegexRow(gtx, tr("path regex "), ui.PathRegexEditor, "(?P<SHOW>[^/]+)/shot/(?P<SHOT>[^/]+)")
+				}
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutHelp(gtx, &ui.help.pathKeys, pathKeysHelp, false, material.Body1(ui.Theme, tr("separate path to ")).Layout)
+					}),
+					layout.Flexed(1, func(gtx C) D {
+						return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+							return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
+								med := material.Editor(ui.Theme, ui.PathKeyEditor, tr("path environ filter"))
+								med.Color = ui.DestColor
+								med.HintColor = ui.DestHintColor
+								return med.Layout(gtx)
+							})
+						})
+					}),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutHelp(gtx, &ui.help.pathSeps, separatorsHelp, false, material.Body1(ui.Theme, tr(" with ")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+							return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
+								gtx.Constraints.Max.X = gtx.Dp(150)
+								med := material.Editor(ui.Theme, ui.PathSeparatorEditor, tr("separators (/ \\)"))
+								med.Color = ui.DestColor
+								med.HintColor = ui.DestHintColor
+								return med.Layout(gtx)
+							})
+						})
+					}),
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
+			layout.Rigid(func(gtx C) D {
+				if ui.ParseRadio.Value == "regex" {
+					return ui.layoutRegexRow(gtx, tr("name regex "), ui.NameRegexEditor, "^(?P<SHOT>SH\\d+)_(?P<VER>v\\d+)_")
+				}
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutHelp(gtx, &ui.help.nameKeys, nameKeysHelp, false, material.Body1(ui.Theme, tr("separate name to ")).Layout)
+					}),
+					layout.Flexed(1, func(gtx C) D {
+						return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+							return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
+								med := material.Editor(ui.Theme, ui.NameKeyEditor, tr("name environ filter"))
+								med.Color = ui.DestColor
+								med.HintColor = ui.DestHintColor
+								return med.Layout(gtx)
+							})
+						})
+					}),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutHelp(gtx, &ui.help.nameSeps, separatorsHelp, false, material.Body1(ui.Theme, tr(" with ")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+							return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
+								gtx.Constraints.Max.X = gtx.Dp(150)
+								med := material.Editor(ui.Theme, ui.NameSeparatorEditor, tr("separators (_ .)"))
+								med.Color = ui.DestColor
+								med.HintColor = ui.DestHintColor
+								return med.Layout(gtx)
+							})
+						})
+					}),
+				)
+			}),
+			layout.Rigid(func(gtx C) D {
+				if !ui.ShowTools {
+					return D{}
+				}
+				return ui.layoutTools(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(10)}.Layout),
+			layout.Rigid(func(gtx C) D {
+				if !ui.Program.Analyzed || ui.Program.Done {
+					return D{}
+				}
+				return layout.Inset{Bottom: unit.Dp(2)}.Layout(gtx, func(gtx C) D {
+					return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+						layout.Flexed(1, func(gtx C) D {
+							return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+								return layout.UniformInset(unit.Dp(2)).Layout(gtx, material.Editor(ui.Theme, ui.FilterEditor, tr("filter sources (text or KEY=value)")).Layout)
+							})
+						}),
+						layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr("  group by ")).Layout(gtx) }),
+						layout.Rigid(material.RadioButton(ui.Theme, ui.GroupRadio, groupByDest, tr("Destination")).Layout),
+						layout.Rigid(material.RadioButton(ui.Theme, ui.GroupRadio, groupByShow, tr("Show")).Layout),
+						layout.Rigid(material.RadioButton(ui.Theme, ui.GroupRadio, groupBySource, tr("Source")).Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(10)}.Layout),
+						layout.Rigid(material.CheckBox(ui.Theme, ui.ThumbCheck, tr("Thumbnails")).Layout),
+					)
+				})
+			}),
+			layout.Flexed(1, func(gtx C) D {
+				return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+					return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
+						if !ui.Program.Analyzed {
+							if ui.ShowPreview {
+								return material.List(ui.Theme, ui.List).Layout(gtx, 1, func(gtx C, i int) D {
+									return richtext.Text(&ui.PreviewState, ui.Theme.Shaper, ui.Preview...).Layout(gtx)
+								})
+							}
+							if len(ui.LineChecks) == 0 {
+								return material.Editor(ui.Theme, ui.InputEditor, tr(inputHint)).Layout(gtx)
+							}
+							return ui.InputSplit.Layout(gtx,
+								material.Editor(ui.Theme, ui.InputEditor, tr(inputHint)).Layout,
+								func(gtx C) D {
+									return material.List(ui.Theme, ui.LineCheckList).Layout(gtx, 1, func(gtx C, i int) D {
+										return richtext.Text(&ui.LineCheckState, ui.Theme.Shaper, ui.LineChecks...).Layout(gtx)
+									})
+								},
+							)
+						} else {
+							if (ui.ThumbCheck.Value && !ui.Program.Done) || ui.hasDestLines() {
+								return ui.layoutResultLines(gtx)
+							}
+							return material.List(ui.Theme, ui.List).Layout(gtx, 1, func(gtx C, i int) D {
+								return richtext.Text(&ui.ResultState, ui.Theme.Shaper, ui.Result...).Layout(gtx)
+							})
+						}
+					})
+				})
+			}),
+			layout.Rigid(func(gtx C) D {
+				if ui.OverrideSrc == "" || !ui.Program.Analyzed || ui.Program.Done {
+					return D{}
+				}
+				return layout.Inset{Top: unit.Dp(2)}.Layout(gtx, ui.layoutOverride)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(2)}.Layout),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Flexed(1, func(gtx C) D {
+						return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+							return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
+								med := material.Editor(ui.Theme, ui.DestEditor, tr("destination folder"))
+								med.Color = ui.DestColor
+								med.HintColor = ui.DestHintColor
+								// 창의 아래쪽에 있으므로 도움말은 위에 보여준다.
+								return ui.layoutHelp(gtx, &ui.help.dest, destHelp, true, med.Layout)
+							})
+						})
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutSetting(gtx, ui.layoutBrowseButton(ui.BrowseDestButton, tr("Choose…")))
+					}),
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(2)}.Layout),
+			layout.Rigid(func(gtx C) D {
+				childs := []layout.FlexChild{
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutSetting(gtx, material.RadioButton(ui.Theme, ui.MethodRadio, "link", tr("Link")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutSetting(gtx, material.RadioButton(ui.Theme, ui.MethodRadio, "copy", tr("Copy")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutSetting(gtx, material.RadioButton(ui.Theme, ui.MethodRadio, "catalog", tr("Catalog")).Layout)
+					}),
+				}
+				childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(20)}.Layout))
+				childs = append(childs,
+					layout.Rigid(func(gtx C) D {
+						return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, material.Body1(ui.Theme, tr("symlinks:")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutSetting(gtx, material.RadioButton(ui.Theme, ui.SymlinkRadio, "follow", tr("Follow")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutSetting(gtx, material.RadioButton(ui.Theme, ui.SymlinkRadio, "keep", tr("Keep")).Layout)
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(20)}.Layout),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutSetting(gtx, material.CheckBox(ui.Theme, ui.ReadOnlyCheck, tr("Read-only")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutSetting(gtx, material.CheckBox(ui.Theme, ui.HiddenCheck, tr("Skip hidden")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutSetting(gtx, material.CheckBox(ui.Theme, ui.FlattenCheck, tr("Flatten")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return ui.layoutSetting(gtx, material.CheckBox(ui.Theme, ui.OfflineCheck, tr("Offline plan")).Layout)
+					}),
+					layout.Rigid(func(gtx C) D {
+						// 분석한 뒤에도 켜고 끌 수 있다.
+						return material.CheckBox(ui.Theme, ui.WaitCheck, tr("Wait for missing")).Layout(gtx)
+					}),
+					layout.Rigid(func(gtx C) D {
+						return material.CheckBox(ui.Theme, ui.ContinueCheck, tr("Keep going on errors")).Layout(gtx)
+					}),
+				)
+				childs = append(childs, layout.Flexed(1, layout.Spacer{}.Layout))
+				if ui.Program.Analyzed {
+					childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.CopyButton, tr("Copy report")).Layout))
+					childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout))
+					childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.ExportButton, tr("Export")).Layout))
+					childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(10)}.Layout))
+				}
+				if ui.Program.Done {
+					childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.OKButton, tr("OK")).Layout))
+				} else if ui.Program.Analyzed {
+					childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.CancelButton, tr("Cancel")).Layout))
+					childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout))
+					childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.SavePlanButton, tr("Save plan")).Layout))
+					if !ui.Program.Offline {
+						if len(ui.Program.Reingested) != 0 {
+							childs = append(childs, layout.Rigid(material.CheckBox(ui.Theme, ui.ReingestCheck, tr("Ingest again")).Layout))
+						}
+						childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout))
+						if !ui.copying() && len(ui.Program.failedOutcomes()) != 0 {
+							childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.RetryButton, tr("Retry failed")).Layout))
+							childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout))
+						}
+						if ui.Program.Method != "catalog" {
+							childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.TestButton, tr("Test run")).Layout))
+							childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout))
+						}
+						childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.RunButton, tr("Run")).Layout))
+					}
+				} else {
+					browse := ui.layoutBrowseButton(ui.BrowseButton, tr("Add files…"))
+					childs = append(childs, layout.Rigid(browse))
+					childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout))
+					browseDirs := ui.layoutBrowseButton(ui.BrowseDirsButton, tr("Add folders…"))
+					childs = append(childs, layout.Rigid(browseDirs))
+					childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(10)}.Layout))
+					previewLabel := tr("Preview")
+					if ui.ShowPreview {
+						previewLabel = tr("Edit input")
+					}
+					childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.PreviewButton, previewLabel).Layout))
+					childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout))
+					childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.SuggestButton, tr("Suggest")).Layout))
+					childs = append(childs, layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout))
+					childs = append(childs, layout.Rigid(material.Button(ui.Theme, ui.AnalyzeButton, tr("Analyze")).Layout))
+				}
+				return layout.Flex{}.Layout(gtx,
+					childs...,
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(10)}.Layout),
+			layout.Rigid(func(gtx C) D {
+				return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+					med := material.Editor(ui.Theme, ui.Notifier, "")
+					if ui.NotifyIsError {
+						med.Color = colors.Error
+					}
+					return layout.UniformInset(unit.Dp(2)).Layout(gtx,
+						med.Layout,
+					)
+				})
+			}),
+		)
+	})
+}
+
+// layoutTools는 설정을 돕는 도구들을 그린다.
+func (ui *UI) layoutTools(gtx C) D {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr("learn from ")).Layout(gtx) }),
+				layout.Flexed(1, func(gtx C) D {
+					return ui.layoutToolEditor(gtx, ui.LearnSrcEditor, "sample path (first input path if empty)")
+				}),
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr(" to ")).Layout(gtx) }),
+				layout.Flexed(1, func(gtx C) D {
+					return ui.layoutToolEditor(gtx, ui.LearnDestEditor, "desired destination for the sample")
+				}),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout),
+				layout.Rigid(func(gtx C) D {
+					return ui.layoutSetting(gtx, material.Button(ui.Theme, ui.LearnButton, tr("Learn")).Layout)
+				}),
+			)
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr("remap values ")).Layout(gtx) }),
+				layout.Flexed(1, func(gtx C) D {
+					return ui.layoutToolEditor(gtx, ui.RemapEditor, "from,to pairs separated by commas, e.g. DEL,delivery,PRV,preview")
+				}),
+			)
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr("exclude in directories ")).Layout(gtx) }),
+				layout.Flexed(1, func(gtx C) D {
+					return ui.layoutToolEditor(gtx, ui.ExcludeEditor, "name globs, e.g. .DS_Store Thumbs.db *.tmp ._*")
+				}),
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr(" include only ")).Layout(gtx) }),
+				layout.Flexed(1, func(gtx C) D {
+					return ui.layoutToolEditor(gtx, ui.IncludeEditor, "file name globs, e.g. *.exr *.mov (all if empty)")
+				}),
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr(" max depth ")).Layout(gtx) }),
+				layout.Rigid(func(gtx C) D {
+					gtx.Constraints.Min.X = gtx.Dp(60)
+					gtx.Constraints.Max.X = gtx.Dp(60)
+					return ui.layoutToolEditor(gtx, ui.MaxDepthEditor, "any")
+				}),
+			)
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr("renumber frames ")).Layout(gtx) }),
+				layout.Flexed(1, func(gtx C) D {
+					return ui.layoutToolEditor(gtx, ui.RenumberEditor, "first frame like 1001, or offset like +100 or -10 (keep if empty)")
+				}),
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr(" padding ")).Layout(gtx) }),
+				layout.Rigid(func(gtx C) D {
+					gtx.Constraints.Min.X = gtx.Dp(60)
+					gtx.Constraints.Max.X = gtx.Dp(60)
+					return ui.layoutToolEditor(gtx, ui.FramePadEditor, "keep")
+				}),
+			)
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
+		layout.Rigid(func(gtx C) D {
+			children := []layout.FlexChild{
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr("camera card ")).Layout(gtx) }),
+				layout.Rigid(func(gtx C) D {
+					return ui.layoutSetting(gtx, material.RadioButton(ui.Theme, ui.CardRadio, "", tr("None")).Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					return ui.layoutSetting(gtx, material.RadioButton(ui.Theme, ui.CardRadio, "auto", tr("Auto")).Layout)
+				}),
+			}
+			for _, preset := range cardPresets {
+				children = append(children, layout.Rigid(func(gtx C) D {
+					return ui.layoutSetting(gtx, material.RadioButton(ui.Theme, ui.CardRadio, preset.Name, preset.Label).Layout)
+				}))
+			}
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx, children...)
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr("media metadata ")).Layout(gtx) }),
+				layout.Rigid(func(gtx C) D {
+					return ui.layoutSetting(gtx, material.CheckBox(ui.Theme, ui.ProbeCheck, tr("Probe with ffprobe (RES, FPS, CODEC)")).Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					return ui.layoutSetting(gtx, material.CheckBox(ui.Theme, ui.ExrCheck, tr("Read EXR headers (EXR_OWNER, ...)")).Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					return ui.layoutSetting(gtx, material.CheckBox(ui.Theme, ui.ExifCheck, tr("Read EXIF dates (SHOT_DATE)")).Layout)
+				}),
+			)
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr("vendor manifest ")).Layout(gtx) }),
+				layout.Flexed(1, func(gtx C) D {
+					return ui.layoutSetting(gtx, func(gtx C) D {
+						return ui.layoutToolEditor(gtx, ui.ManifestEditor, "MHL or md5sum list to verify the sources against (none if empty)")
+					})
+				}),
+			)
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr("compare ")).Layout(gtx) }),
+				layout.Flexed(1, func(gtx C) D {
+					return ui.layoutToolEditor(gtx, ui.CompareSrcEditor, "source directory")
+				}),
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr(" with ")).Layout(gtx) }),
+				layout.Flexed(1, func(gtx C) D {
+					return ui.layoutToolEditor(gtx, ui.CompareDestEditor, "where it was ingested")
+				}),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout),
+				layout.Rigid(material.Button(ui.Theme, ui.CompareButton, tr("Compare")).Layout),
+			)
+		}),
+		layout.Rigid(func(gtx C) D {
+			if ui.CompareResult == "" {
+				return D{}
+			}
+			return material.Body2(ui.Theme, ui.CompareResult).Layout(gtx)
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(5)}.Layout),
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr("test path ")).Layout(gtx) }),
+				layout.Flexed(1, func(gtx C) D {
+					return ui.layoutToolEditor(gtx, ui.SandboxEditor, "any path to try the current keys and destination on")
+				}),
+			)
+		}),
+		layout.Rigid(func(gtx C) D {
+			if ui.SandboxResult == "" {
+				return D{}
+			}
+			return material.Body2(ui.Theme, ui.SandboxResult).Layout(gtx)
+		}),
+	)
+}
+
+// layoutToolEditor는 도구에 쓰이는 테두리 있는 에디터를 그린다.
+func (ui *UI) layoutToolEditor(gtx C, ed *widget.Editor, hint string) D {
+	return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+		return layout.UniformInset(unit.Dp(2)).Layout(gtx, material.Editor(ui.Theme, ed, tr(hint)).Layout)
+	})
+}
+
+// layoutRegexRow는 레이블과 정규식 에디터로 이루어진 한 줄을 그린다.
+func (ui *UI) layoutRegexRow(gtx C, label string, ed *widget.Editor, hint string) D {
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+		layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, label).Layout(gtx) }),
+		layout.Flexed(1, func(gtx C) D {
+			return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+				return layout.UniformInset(unit.Dp(2)).Layout(gtx, func(gtx C) D {
+					med := material.Editor(ui.Theme, ed, hint)
+					med.Color = ui.DestColor
+					med.HintColor = ui.DestHintColor
+					return med.Layout(gtx)
+				})
+			})
+		}),
+	)
+}
+
+// layoutSetting은 작업 설정 위젯을 그린다.
+// 설정이 잠긴 동안에는 위젯을 비활성화해 진행중인 작업의 설정을 바꿀 수 없게 한다.
+func (ui *UI) layoutSetting(gtx C, w layout.Widget) D {
+	if ui.Locked() {
+		gtx = gtx.Disabled()
+	}
+	return w(gtx)
+}
+
+// Program은 받아들인 경로를 다양한 각도에서 분석한 정보이다.
+type Program struct {
+	InputText    string
+	PathSeps     []string
+	PathKeys     []string
+	NameSeps     []string
+	NameKeys     []string
+	DestPattern  string
+	Routes       []Route
+	Vocabulary   map[string]KeyRule
+	Remap        map[string]string
+	Vars         map[string]string
+	OSEnv        map[string]string
+	Roots        map[string]string
+	Translations []PathTranslation
+	Rewrites     []Rewrite
+	Excludes     []string
+	Includes     []string
+	MaxDepth     int
+	SkipHidden   bool
+	Flatten      bool
+	Offline      bool
+	Renumber     renumber
+	Card         string
+	Probe        bool
+	FFprobe      string
+	ExrAttrs     []string
+	ExifDate     bool
+	Normalize    string
+	Sanitize     bool
+	MaxPathLen   int
+	StableWait   time.Duration
+	HashHistory  bool
+	// Ingested는 분석할 때 읽은 작업 기록의 파일 해시들이다.
+	Ingested        contentIndex
+	PrevDestPattern string
+	Method          string
+	Symlinks        string
+	ReadOnly        bool
+	ParseMode       string
+	PathRegexp      *regexp.Regexp
+	NameRegexp      *regexp.Regexp
+	Analyzed        bool
+	Done            bool
+	NotExists       []string
+	Invalids        []string
+	Ignored         []ignoredFile
+	// ignoredSeen은 같은 파일을 같은 이유로 두번 기록하지 않기 위해 Ignored의 항목들을 기억한다.
+	ignoredSeen     map[string]bool
+	Merged          []string
+	Rewritten       []string
+	Cards           []string
+	Gaps            []string
+	Srcs            []string
+	SrcIsDir        map[string]bool
+	SrcDirFileCount map[string]int
+	SrcCounting     map[string]bool
+	// SrcChecking은 다른 고루틴에서 해시를 비교하고 있는 파일 소스들이다.
+	SrcChecking map[string]bool
+	// SrcCountErr는 디렉토리 소스를 세지 못했거나 파일 소스의 해시를 비교하지 못한 에러이다.
+	SrcCountErr map[string]string
+	SrcExcluded map[string]int
+	SrcSeq      map[string]*sequence
+	SrcDirSeqs  map[string][]*sequence
+	SrcCardEnv  map[string]map[string]string
+	SrcMeta     map[string]map[string]string
+	// probed는 분석할 때 ffprobe로 미리 분석해둔 미디어 파일 소스들의 결과이다.
+	probed    map[string]probeResult
+	SrcBytes  map[string]int64
+	SrcLinked map[string]bool
+	SrcStats  map[string]srcStats
+	// SrcDestExists는 대상 경로에 이미 다른 파일이 있어 건너뛸 파일 소스들이다.
+	SrcDestExists map[string]bool
+	// SrcDestExisting은 디렉토리 소스 안의 파일들 중 대상 경로에 이미 있는 파일의 수이다.
+	SrcDestExisting map[string]int
+	// SrcSkipped는 분석한 뒤 작업에서 빼기로 한 소스들이다.
+	SrcSkipped     map[string]bool
+	NoSpace        []string
+	NotWritable    []string
+	Recursions     []string
+	CaseCollisions []string
+	CrossDevice    []string
+	OverQuota      []string
+	Reingested     []string
+	// Reingest가 참이면 이전에 복사한 소스가 있더라도 다시 복사한다.
+	Reingest bool
+	// Sample이 0보다 크면 대상 경로마다 처음 Sample개의 파일만 복사한다.
+	Sample     int
+	Duplicates []string
+	// Manifest는 소스들을 확인할 해시 목록 파일의 경로로, 비어있으면 확인하지 않는다.
+	Manifest string
+	// manifest는 분석할 때 읽은 Manifest의 내용이다.
+	manifest         *manifest
+	ManifestProblems []string
+	// PathProblems는 디렉토리 소스 안의 파일들 중 대상 경로를 만들 수 없는 것들로, 디렉토리를 셀 때 찾는다.
+	PathProblems  []string
+	DestDir       map[string]string
+	DestDirSrcs   map[string][]string
+	DestDirExists map[string]bool
+	// DestOverridden은 분석한 뒤 대상 디렉토리를 직접 바꾼 소스들이다.
+	DestOverridden map[string]bool
+	// Now, User, Host는 작업을 분석한 시각과 사용자, 컴퓨터로, 대상 경로 패턴에서 쓸 수 있다.
+	Now  time.Time
+	User string
+	Host string
+	// DateLayout과 Location은 ${DATE}와 ${TIME}의 형식과 시간대이다.
+	DateLayout string
+	Location   *time.Location
+	ShowRoots  map[string]string
+	// Copied는 마지막 복사 작업에서 실제로 복사된 파일들이다.
+	Copied []IngestFile
+	// Linked는 마지막 복사 작업에서 이미 소스와 같은 파일이라 건너뛴 대상 경로들이다.
+	Linked []string
+	// Outcomes는 마지막 복사 작업에서 각 파일을 처리한 결과이다.
+	Outcomes []fileOutcome
+	// ContinueOnError가 참이면 파일 하나를 복사하지 못해도 멈추지 않고 나머지 파일들을 복사한다.
+	ContinueOnError bool
+	// RunTime은 마지막 복사 작업에 걸린 시간으로, 실패한 파일을 다시 복사하는데 걸린 시간도 더한다.
+	RunTime time.Duration
+	// progress는 복사 작업의 진행 상황을 기록할 곳으로, nil이면 기록하지 않는다.
+	progress *copyProgress
+}
+
+func (p *Program) ParseEnvsFromSrc(src string) (map[string]string, error) {
+	env := make(map[string]string)
+	var pathEnv, nameEnv map[string]string
+	var err error
+	if p.ParseMode == "regex" {
+		pathEnv, err = parseEnvsRegex(src, p.PathRegexp)
+	} else {
+		pathEnv, err = parseEnvs(src, p.PathSeps, p.PathKeys)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range pathEnv {
+		env[k] = v
+	}
+	if p.ParseMode == "regex" {
+		nameEnv, err = parseEnvsRegex(baseName(src), p.NameRegexp)
+	} else {
+		nameEnv, err = parseEnvs(baseName(src), p.NameSeps, p.NameKeys)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range nameEnv {
+		env[k] = v
+	}
+	return env, nil
+}
+
+// DestPatternChanged는 대상 경로 패턴이 마지막 작업 이후 바뀌었는지 확인한다.
+// 공용 워크스테이션에서 다른 사람이 모르게 패턴을 바꾸는 경우를 알리기 위함이다.
+func (p *Program) DestPatternChanged() bool {
+	prev := strings.TrimSpace(p.PrevDestPattern)
+	return prev != "" && prev != strings.TrimSpace(p.DestPattern)
+}
+
+// stamp는 분석 시각과 사용자, 컴퓨터 정보를 기록한다.
+func (p *Program) stamp() {
+	p.Now = time.Now()
+	if p.Location != nil {
+		p.Now = p.Now.In(p.Location)
+	}
+	p.User = currentUser()
+	// 윈도우즈의 사용자 이름은 DOMAIN\user 형식이라 경로에 그대로 쓸 수 없다.
+	if i := strings.LastIndex(p.User, `\`); i >= 0 {
+		p.User = p.User[i+1:]
+	}
+	p.Host, _ = os.Hostname()
+}
+
+// dateLayout은 ${DATE}와 ${SHOT_DATE}의 형식이다.
+func (p *Program) dateLayout() string {
+	if p.DateLayout == "" {
+		return "060102"
+	}
+	return p.DateLayout
+}
+
+// Env는 소스 경로에 대한 대상 경로 패턴의 환경 변수들을 만든다.
+// 경로에서 분석한 값들에 날짜나 쇼 레지스트리처럼 프로그램이 제공하는 값들을 더한다.
+//
+// DATE 외의 기본 변수들과 미디어 메타데이터, 설정의 고정된 값들, 허용된 프로세스 환경 변수들은 경로에서 같은 이름의 키를 분석했다면 그 값을 덮어쓰지 않는다.
+func (p *Program) Env(src string) (map[string]string, error) {
+	var env map[string]string
+	if cenv, ok := p.SrcCardEnv[src]; ok {
+		// 카메라 카드의 클립은 경로를 분석하지 않고 클립 이름에서 얻은 값들을 쓴다.
+		env = make(map[string]string, len(cenv))
+		for k, v := range cenv {
+			env[k] = v
+		}
+	} else {
+		var err error
+		env, err = p.ParseEnvsFromSrc(src)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for k, v := range env {
+		if to, ok := p.Remap[v]; ok {
+			env[k] = to
+		}
+	}
+	env["DATE"] = p.Now.Format(p.dateLayout())
+	name := baseName(src)
+	ext := ""
+	if i := strings.LastIndex(name, "."); i > 0 {
+		ext = name[i+1:]
+	}
+	parent := baseName(strings.TrimSuffix(strings.TrimRight(src, `/\`), name))
+	builtins := map[string]string{
+		"TIME":     p.Now.Format("150405"),
+		"USER":     p.User,
+		"HOSTNAME": p.Host,
+		"EXT":      ext,
+		"BASENAME": name,
+		"PARENT":   parent,
+	}
+	for k, v := range builtins {
+		if _, ok := env[k]; !ok {
+			env[k] = v
+		}
+	}
+	for _, vars := range []map[string]string{p.SrcMeta[src], p.Vars, p.OSEnv} {
+		for k, v := range vars {
+			if _, ok := env[k]; !ok {
+				env[k] = v
+			}
+		}
+	}
+	if root, ok := p.ShowRoots[env["SHOW"]]; ok {
+		env["SHOWROOT"] = root
+	}
+	return env, nil
+}
+
+// Analyze는 사용자가 입력한 텍스트를 받아들이고 그 안에서 경로를 찾아
 // 그 상태 및 대상 경로 정보 분석한다.
 func (p *Program) AnalyzeInput(text string) error {
 	// 이전 데이터 삭제
 	p.NotExists = make([]string, 0)
 	p.Invalids = make([]string, 0)
+	p.Ignored = make([]ignoredFile, 0)
+	p.ignoredSeen = make(map[string]bool)
+	p.Merged = make([]string, 0)
+	p.Rewritten = make([]string, 0)
+	p.Cards = make([]string, 0)
+	p.Gaps = make([]string, 0)
 	p.Srcs = make([]string, 0)
 	p.SrcIsDir = make(map[string]bool)
 	p.SrcDirFileCount = make(map[string]int)
+	p.SrcCounting = make(map[string]bool)
+	p.SrcChecking = make(map[string]bool)
+	p.SrcCountErr = make(map[string]string)
+	p.SrcExcluded = make(map[string]int)
+	p.SrcSeq = make(map[string]*sequence)
+	p.SrcDirSeqs = make(map[string][]*sequence)
+	p.SrcCardEnv = make(map[string]map[string]string)
+	p.SrcMeta = make(map[string]map[string]string)
+	p.SrcBytes = make(map[string]int64)
+	p.SrcLinked = make(map[string]bool)
+	p.SrcStats = make(map[string]srcStats)
+	p.SrcDestExists = make(map[string]bool)
+	p.SrcDestExisting = make(map[string]int)
+	p.SrcSkipped = make(map[string]bool)
+	p.Outcomes = make([]fileOutcome, 0)
+	p.NoSpace = make([]string, 0)
+	p.NotWritable = make([]string, 0)
+	p.Recursions = make([]string, 0)
+	p.CaseCollisions = make([]string, 0)
+	p.CrossDevice = make([]string, 0)
+	p.OverQuota = make([]string, 0)
+	p.Reingested = make([]string, 0)
+	p.Reingest = false
+	p.Duplicates = make([]string, 0)
+	p.Ingested = nil
+	p.ManifestProblems = make([]string, 0)
+	p.PathProblems = make([]string, 0)
+	p.manifest = nil
+	if p.Manifest != "" && !p.Offline {
+		m, err := loadManifest(p.Manifest)
+		if err != nil {
+			return err
+		}
+		p.manifest = m
+	}
 	p.DestDir = make(map[string]string)
 	p.DestDirSrcs = make(map[string][]string)
 	p.DestDirExists = make(map[string]bool)
-	p.Today = time.Now().Format("060102")
+	p.DestOverridden = make(map[string]bool)
+	forgetHungMounts()
+	p.stamp()
+	// 이전에 복사한 소스인지 확인하기 위해 작업 기록을 읽는다.
+	var history []*IngestRecord
+	if !p.Offline {
+		hist, err := historyFile()
+		if err != nil {
+			return err
+		}
+		history, err = readHistory(hist)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, hist)
+		}
+		if p.HashHistory {
+			p.Ingested = newContentIndex(history)
+		}
+	}
 	// 문자열에서 경로 추출
-	text = strings.Replace(text, "\r\n", "\n", -1)
-	lines := strings.Split(text, "\n")
-	paths := make([]string, 0)
-	for _, l := range lines {
-		l = strings.TrimPrefix(l, "file://")
-		if strings.HasPrefix(l, "/") {
-			// 할일: 윈도우즈 경로형식 처리
-			paths = append(paths, l)
+	paths := inputPaths(text)
+	// 다른 운영체제에서 복사해 온 경로나 예전 마운트 위치의 경로를 지금 이 컴퓨터의 경로로 바꾼다.
+	for i, path := range paths {
+		path = strings.TrimSpace(path)
+		src := p.sourcePath(path)
+		if src != path {
+			p.Rewritten = append(p.Rewritten, path+" → "+src)
+		}
+		paths[i] = src
+	}
+	// 글롭 패턴을 실제 경로들로 바꾼다.
+	// 맞는 경로가 없는 패턴은 존재하지 않는 경로로 취급한다.
+	if !p.Offline {
+		expanded, unmatched, err := expandInputPaths(paths)
+		if err != nil {
+			return err
+		}
+		paths = expanded
+		p.NotExists = append(p.NotExists, unmatched...)
+	}
+	// 같은 경로가 여러번 입력되었다면 하나만 남긴다.
+	uniq := make([]string, 0, len(paths))
+	seen := make(map[string]bool)
+	for _, src := range paths {
+		src = filepath.Clean(strings.TrimSpace(src))
+		// 다른 정규화 형식으로 입력된 경로는 실제 이름으로 바꾼다.
+		if !p.Offline {
+			src = existingForm(src, statExists)
 		}
+		if seen[src] {
+			p.merge(src, "duplicate")
+			continue
+		}
+		seen[src] = true
+		uniq = append(uniq, src)
 	}
+	paths = uniq
 	// 경로 분석
 	//
 	// 존재하는 파일과 존재하지 않는 파일 분리
 	for _, src := range paths {
 		src = strings.TrimSpace(src)
-		fi, err := os.Stat(src)
+		if p.Offline {
+			// 존재 여부와 종류를 알 수 없으므로 모두 파일 소스로 본다.
+			p.Srcs = append(p.Srcs, src)
+			p.SrcIsDir[src] = false
+			continue
+		}
+		fi, err := safeStat(src)
 		if err != nil {
+			var stale *staleMountError
+			if errors.As(err, &stale) {
+				// 응답하지 않는 마운트의 소스는 분석할 수 없다.
+				p.Invalids = append(p.Invalids, src+" ("+err.Error()+")")
+				continue
+			}
 			if !errors.Is(err, os.ErrNotExist) {
-				return fmt.Errorf("%v: %s", err, src)
+				return fmt.Errorf("%w: %s", err, src)
 			}
 			p.NotExists = append(p.NotExists, src)
 			continue
 		}
+		if kind := specialFileKind(fi.Mode()); kind != "" {
+			p.ignore(src, kind)
+			continue
+		}
+		if fi.IsDir() && p.Card != "" {
+			// 카메라 카드의 루트라면 카드 대신 그 안의 클립들을 소스로 쓴다.
+			clips, preset, err := p.cardClips(src)
+			if err != nil {
+				return fmt.Errorf("%w: %s", err, src)
+			}
+			if len(clips) != 0 {
+				p.Cards = append(p.Cards, src+" ("+preset.Label+", "+strconv.Itoa(len(clips))+" clips)")
+				for _, clip := range clips {
+					if _, ok := p.SrcIsDir[clip.Path]; ok {
+						p.merge(clip.Path, "duplicate")
+						continue
+					}
+					cfi, err := safeStat(clip.Path)
+					if err != nil {
+						return fmt.Errorf("%w: %s", err, clip.Path)
+					}
+					p.Srcs = append(p.Srcs, clip.Path)
+					p.SrcIsDir[clip.Path] = cfi.IsDir()
+					if !cfi.IsDir() {
+						p.SrcBytes[clip.Path] = cfi.Size()
+						p.SrcStats[clip.Path] = fileStats(clip.Path, cfi)
+					}
+					p.SrcCardEnv[clip.Path] = clip.Env
+				}
+				continue
+			}
+		}
 		p.Srcs = append(p.Srcs, src)
 		p.SrcIsDir[src] = fi.IsDir()
+		if !fi.IsDir() {
+			p.SrcBytes[src] = fi.Size()
+			p.SrcStats[src] = fileStats(src, fi)
+		}
+	}
+	sortNatural(p.Srcs)
+	// 입력된 디렉토리 안의 경로는 그 디렉토리를 복사할 때 함께 복사되므로 제외한다.
+	// 그렇지 않으면 같은 파일이 두번 처리된다.
+	srcs := make([]string, 0, len(p.Srcs))
+	for _, src := range p.Srcs {
+		if parent := p.listedParent(src); parent != "" {
+			p.merge(src, "inside "+parent)
+			delete(p.SrcIsDir, src)
+			continue
+		}
+		srcs = append(srcs, src)
+	}
+	p.Srcs = srcs
+	// 프레임 번호만 다른 파일 소스들을 시퀀스로 묶는다.
+	files := make([]string, 0, len(p.Srcs))
+	for _, src := range p.Srcs {
+		if !p.SrcIsDir[src] {
+			files = append(files, src)
+		}
+	}
+	seqs, _ := detectSequences(files)
+	for _, seq := range seqs {
+		for _, path := range seq.Paths {
+			p.SrcSeq[path] = seq
+		}
+	}
+	// 미디어 파일들은 대상 경로를 찾기 전에 함께 ffprobe로 분석해둔다.
+	p.probed = nil
+	if p.Probe && !p.Offline {
+		media := make([]string, 0)
+		for _, src := range files {
+			if isProbeMedia(src) {
+				media = append(media, src)
+			}
+		}
+		p.probed = probeAll(p.FFprobe, media)
 	}
-	sort.Strings(p.Srcs)
 	// 소스 경로에 대한 대상 경로를 찾고, 찾지 못하거나 문제가 있으면 유효하지 않은 것으로 간주
+	// 시퀀스도 프레임마다 분석하며, 한 프레임이라도 유효하지 않은 시퀀스는 통째로 유효하지 않은 것으로 간주한다.
+	dests := make(map[string]string)
+	invalidSeq := make(map[*sequence]bool)
 	for _, src := range p.Srcs {
-		env, err := p.ParseEnvsFromSrc(src)
+		seq := p.SrcSeq[src]
+		if seq != nil && invalidSeq[seq] {
+			continue
+		}
+		destDir, reason, err := p.sourceDest(src)
 		if err != nil {
 			return err
 		}
-		env["DATE"] = p.Today
-		destDir, err := destDirectory(src, p.DestPattern, env)
-		if err != nil {
-			p.Invalids = append(p.Invalids, src+" ("+err.Error()+")")
+		if reason == "" {
+			dests[src] = destDir
+			continue
+		}
+		name := src
+		if seq != nil {
+			name = seq.String()
+			invalidSeq[seq] = true
+		}
+		p.Invalids = append(p.Invalids, name+" ("+reason+")")
+	}
+	// 대상 경로가 다른 프레임들이 섞인 시퀀스는 대상 경로별로 나눈다.
+	for _, seq := range seqs {
+		for _, path := range seq.Paths {
+			delete(p.SrcSeq, path)
+		}
+		if invalidSeq[seq] {
+			for _, path := range seq.Paths {
+				delete(dests, path)
+			}
+			continue
+		}
+		parts, _ := seq.split(func(path string) string { return dests[path] })
+		for _, part := range parts {
+			for _, path := range part.Paths {
+				p.SrcSeq[path] = part
+			}
+			p.checkGaps(part)
+		}
+	}
+	for _, src := range p.Srcs {
+		destDir, ok := dests[src]
+		if !ok {
 			continue
 		}
+		seq := p.SrcSeq[src]
 		p.DestDir[src] = destDir
-		// 소스 경로가 디렉토리이면 그 안의 파일 갯수 분석
+		// 대상 경로에 이미 같은 파일(하드 링크)이 있다면 다시 링크하거나 복사할 필요가 없다.
+		if !p.Offline && !p.SrcIsDir[src] && (seq == nil || !p.Renumber.active()) {
+			p.SrcLinked[src] = isSameFile(src, filepath.Join(destDir, filepath.Base(src)))
+		}
+		// 같은 내용이 이미 다른 곳으로 복사되었는지 작업 기록의 해시와 비교한다.
+		// 해시를 구하는 데 시간이 걸리므로 디렉토리를 셀 때처럼 분석이 끝난 뒤 따로 비교한다.
+		if p.Ingested != nil && !p.SrcIsDir[src] && !p.SrcLinked[src] {
+			p.SrcChecking[src] = true
+		}
+		// 업체의 해시 목록과도 분석이 끝난 뒤 따로 비교한다.
+		if p.manifest != nil && !p.SrcIsDir[src] {
+			p.SrcChecking[src] = true
+		}
+		// 소스 경로가 디렉토리이면 그 안의 파일 갯수는 분석이 끝난 뒤 따로 센다.
 		if p.SrcIsDir[src] {
-			srcd := os.DirFS(src)
-			err := fs.WalkDir(srcd, ".", func(path string, d fs.DirEntry, err error) error {
-				if err != nil {
-					return err
-				}
-				if d.IsDir() {
-					return nil
-				}
-				p.SrcDirFileCount[src] += 1
-				// 1000개 이상의 파일이 있다면 더이상 세지 않는다.
-				// 복사 단계에서는 모든 파일이 복사될 것이다.
-				if p.SrcDirFileCount[src] > 1000 {
-					return fs.SkipDir
-				}
-				return nil
-			})
-			if err != nil {
-				return err
-			}
+			p.SrcCounting[src] = true
 		}
 		// 대상 경로가 복사될 디렉토리가 이미 존재하는지 검사
-		if _, checked := p.DestDirExists[destDir]; !checked {
-			_, err := os.Stat(destDir)
+		if _, checked := p.DestDirExists[destDir]; !checked && !p.Offline {
+			_, err := safeStat(destDir)
 			if err != nil {
 				if !errors.Is(err, os.ErrNotExist) {
-					return fmt.Errorf("%v: %s", err, src)
+					return fmt.Errorf("%w: %s", err, src)
 				}
 				p.DestDirExists[destDir] = false
 			} else {
@@ -518,24 +2372,164 @@ func (p *Program) AnalyzeInput(text string) error {
 		destDirSrcs = append(destDirSrcs, src)
 		p.DestDirSrcs[destDir] = destDirSrcs
 	}
-	return nil
+	// 이전에 복사한 소스인지 검사
+	if !p.Offline {
+		p.checkReingest(history)
+	}
+	return p.checkDestinations()
+}
+
+// sourceDest는 소스 경로의 메타데이터를 읽고 키를 분석해 대상 디렉토리를 찾는다.
+// 소스가 유효하지 않다면 그 이유를 reason으로 반환하고, 분석을 계속할 수 없는 에러는 err로 반환한다.
+func (p *Program) sourceDest(src string) (destDir, reason string, err error) {
+	file := !p.Offline && !p.SrcIsDir[src]
+	if r, ok := p.probed[src]; ok {
+		if r.Err != nil {
+			return "", r.Err.Error(), nil
+		}
+		p.SrcMeta[src] = r.Meta
+	}
+	if len(p.ExrAttrs) != 0 && file && isExr(src) {
+		meta, err := readExrHeader(src, p.ExrAttrs)
+		if err != nil {
+			return "", err.Error(), nil
+		}
+		p.SrcMeta[src] = meta
+	}
+	if p.ExifDate && file && isExifPhoto(src) {
+		// EXIF가 없는 사진은 ${SHOT_DATE}를 쓰는 패턴에서만 문제가 된다.
+		shot, err := readExifDate(src, p.Location)
+		if err != nil && !errors.Is(err, errNoExif) {
+			return "", err.Error(), nil
+		}
+		if err == nil {
+			if p.SrcMeta[src] == nil {
+				p.SrcMeta[src] = make(map[string]string)
+			}
+			p.SrcMeta[src]["SHOT_DATE"] = shot.Format(p.dateLayout())
+		}
+	}
+	env, err := p.Env(src)
+	if err != nil {
+		return "", "", err
+	}
+	if err := p.checkVocabulary(env); err != nil {
+		return "", err.Error(), nil
+	}
+	destDir, err = p.destDirectoryFor(src, env)
+	if err != nil {
+		return "", err.Error(), nil
+	}
+	return destDir, "", nil
+}
+
+// checkDestinations는 대상 경로들에 대한 검사를 한다.
+// 분석할 때와 소스의 대상 경로를 직접 바꿨을 때 부른다.
+func (p *Program) checkDestinations() error {
+	// 대소문자만 다른 대상 경로 검사
+	p.checkCaseCollisions(!p.Offline)
+	if p.Offline {
+		return nil
+	}
+	// 대상 파일이 이미 있는지 검사
+	if err := p.checkDestFiles(); err != nil {
+		return err
+	}
+	// 디렉토리 소스와 대상 경로가 서로를 포함하는지 검사
+	p.checkRecursion()
+	// 대상 경로에 쓸 수 있는지 검사
+	if err := p.checkWritable(); err != nil {
+		return err
+	}
+	// 링크할 소스와 대상 경로가 같은 파일시스템에 있는지 검사
+	if err := p.checkCrossDevice(); err != nil {
+		return err
+	}
+	// 대상 파일시스템에 복사할 공간과 쿼터가 충분한지 검사
+	// 디렉토리 소스들을 다 센 뒤에 다시 검사한다.
+	if err := p.checkDiskSpace(); err != nil {
+		return err
+	}
+	return p.checkQuota()
+}
+
+// applyCount는 디렉토리 소스를 센 결과나 파일 소스의 해시를 비교한 결과를 분석 결과에 반영한다.
+func (p *Program) applyCount(c dirCount) {
+	if c.File {
+		p.applyFileCheck(c)
+		return
+	}
+	if !p.SrcCounting[c.Src] {
+		return
+	}
+	delete(p.SrcCounting, c.Src)
+	if c.Err != nil {
+		p.SrcCountErr[c.Src] = c.Err.Error()
+		return
+	}
+	p.SrcDirFileCount[c.Src] = c.Files
+	p.SrcBytes[c.Src] = c.Bytes
+	p.SrcStats[c.Src] = c.Stats
+	p.SrcExcluded[c.Src] = c.Excluded
+	// 세는 동안 대상 디렉토리를 바꿨다면 이전 대상 디렉토리에서 센 수는 쓰지 않는다.
+	if p.DestDir[c.Src] == c.DestDir {
+		p.SrcDestExisting[c.Src] = c.Existing
+	}
+	p.SrcDirSeqs[c.Src] = c.Seqs
+	for _, seq := range c.Seqs {
+		p.checkGaps(seq)
+	}
+	if p.DestDir[c.Src] == c.DestDir && len(c.PathProblems) != 0 {
+		p.PathProblems = append(p.PathProblems, c.PathProblems...)
+	}
+	for _, ig := range c.Ignored {
+		p.ignore(ig.Path, ig.Reason)
+	}
+	if len(c.Dups) != 0 {
+		p.Duplicates = append(p.Duplicates, c.Dups...)
+		sortNatural(p.Duplicates)
+	}
+	if len(c.ManifestProblems) != 0 {
+		p.ManifestProblems = append(p.ManifestProblems, c.ManifestProblems...)
+		sortNatural(p.ManifestProblems)
+	}
+}
+
+// applyFileCheck는 파일 소스의 해시를 비교한 결과를 분석 결과에 반영한다.
+func (p *Program) applyFileCheck(c dirCount) {
+	if !p.SrcChecking[c.Src] {
+		return
+	}
+	delete(p.SrcChecking, c.Src)
+	if c.Err != nil {
+		p.SrcCountErr[c.Src] = c.Err.Error()
+		return
+	}
+	if len(c.Dups) != 0 {
+		p.Duplicates = append(p.Duplicates, c.Dups...)
+		sortNatural(p.Duplicates)
+	}
+	if len(c.ManifestProblems) != 0 {
+		p.ManifestProblems = append(p.ManifestProblems, c.ManifestProblems...)
+		sortNatural(p.ManifestProblems)
+	}
 }
 
 func richTitle(text string) richtext.SpanStyle {
 	return richtext.SpanStyle{
 		Content: text,
-		Color:   color.NRGBA{A: 255},
+		Color:   colors.Text,
 		Size:    unit.Sp(20),
-		Font:    gofont.Collection()[0].Font,
+		Font:    textFont,
 	}
 }
 
 func richTitlePath(text string) richtext.SpanStyle {
 	return richtext.SpanStyle{
 		Content:     text,
-		Color:       color.NRGBA{A: 255},
+		Color:       colors.Text,
 		Size:        unit.Sp(20),
-		Font:        gofont.Collection()[0].Font,
+		Font:        textFont,
 		Interactive: true,
 	}
 }
@@ -543,177 +2537,807 @@ func richTitlePath(text string) richtext.SpanStyle {
 func richPath(text string) richtext.SpanStyle {
 	return richtext.SpanStyle{
 		Content:     text,
-		Color:       color.NRGBA{A: 255, B: 170},
+		Color:       colors.Path,
 		Size:        unit.Sp(15),
-		Font:        gofont.Collection()[0].Font,
+		Font:        textFont,
 		Interactive: true,
 	}
 }
 
-func richText(text string) richtext.SpanStyle {
-	return richtext.SpanStyle{
-		Content: text,
-		Color:   color.NRGBA{A: 255},
-		Size:    unit.Sp(15),
-		Font:    gofont.Collection()[0].Font,
+// richCheck는 소스를 작업에 넣을지 보여주는 체크박스이다. 누르면 소스를 작업에서 빼거나 다시 넣는다.
+func richCheck(src string, checked bool) richtext.SpanStyle {
+	box := markChecked
+	if !checked {
+		box = markUnchecked
+	}
+	s := richText(box)
+	s.Interactive = true
+	s.Set("skip", src)
+	return s
+}
+
+// richOverride는 소스의 대상 디렉토리를 고치는 링크이다.
+func richOverride(src string) richtext.SpanStyle {
+	s := richPath(markOverride)
+	s.Size = unit.Sp(12)
+	s.Set("override", src)
+	return s
+}
+
+// richSeq는 시퀀스를 보여준다. 누르면 시퀀스가 있는 디렉토리를 연다.
+func richSeq(seq *sequence) richtext.SpanStyle {
+	s := richPath(filepath.Join(seq.Dir, seq.Name()))
+	s.Set("path", seq.Dir)
+	return s
+}
+
+func richChanged(text string) richtext.SpanStyle {
+	return richtext.SpanStyle{
+		Content: text,
+		Color:   colors.Error,
+		Size:    unit.Sp(15),
+		Font:    textFont,
+	}
+}
+
+func richText(text string) richtext.SpanStyle {
+	return richtext.SpanStyle{
+		Content: text,
+		Color:   colors.Text,
+		Size:    unit.Sp(15),
+		Font:    textFont,
+	}
+}
+
+// 인풋을 분석한 프로그램 정보를 바탕으로 사용자에게 알려줄 정보를 생성한다.
+func analyzeInput(p *Program, v *reportView) []richtext.SpanStyle {
+	res := make([]richtext.SpanStyle, 0)
+	if len(p.Recursions) != 0 {
+		res = append(res, richTitle(tr("Recursive Copy")))
+		res = append(res, richText("\n"))
+		for _, l := range p.Recursions {
+			res = append(res, richChanged(l))
+			res = append(res, richText("\n"))
+		}
+		res = append(res, richText(tr("can't run until the destination pattern or the sources change\n\n")))
+	}
+	if p.Offline {
+		res = append(res, richTitle(tr("Offline Plan")))
+		res = append(res, richText("\n"))
+		res = append(res, richText(tr("files and destinations are not checked, and the plan can't be run\n\n")))
+	}
+	if len(p.PathProblems) != 0 {
+		res = append(res, richTitle(tr("Destination Path Problems")))
+		res = append(res, richText("\n"))
+		for _, l := range p.PathProblems {
+			res = append(res, richChanged(l))
+			res = append(res, richText("\n"))
+		}
+		res = append(res, richText(tr("these files inside directory sources will fail to copy\n\n")))
+	}
+	if len(p.ManifestProblems) != 0 {
+		res = append(res, richTitle(tr("Manifest Mismatches")))
+		res = append(res, richText("\n"))
+		for _, l := range p.ManifestProblems {
+			res = append(res, richChanged(l))
+			res = append(res, richText("\n"))
+		}
+		res = append(res, richText(tr("checked against ")+p.Manifest+"\n\n"))
+	}
+	if len(p.NoSpace) != 0 {
+		res = append(res, richTitle(tr("Not Enough Space")))
+		res = append(res, richText("\n"))
+		for _, l := range p.NoSpace {
+			res = append(res, richChanged(l))
+			res = append(res, richText("\n"))
+		}
+		res = append(res, richText("\n"))
+	}
+	if len(p.OverQuota) != 0 {
+		res = append(res, richTitle(tr("Over Quota")))
+		res = append(res, richText("\n"))
+		for _, l := range p.OverQuota {
+			res = append(res, richChanged(l))
+			res = append(res, richText("\n"))
+		}
+		res = append(res, richText("\n"))
+	}
+	if len(p.NotWritable) != 0 {
+		res = append(res, richTitle(tr("Not Writable")))
+		res = append(res, richText("\n"))
+		for _, l := range p.NotWritable {
+			res = append(res, richChanged(l))
+			res = append(res, richText("\n"))
+		}
+		res = append(res, richText("\n"))
+	}
+	if len(p.Reingested) != 0 {
+		res = append(res, richTitle(tr("Already Ingested")))
+		res = append(res, richText("\n"))
+		for _, l := range p.Reingested {
+			res = append(res, richChanged(l))
+			res = append(res, richText("\n"))
+		}
+		res = append(res, richText(tr("check \"Ingest again\" to copy them anyway\n\n")))
+	}
+	if len(p.Duplicates) != 0 {
+		res = append(res, richTitle(tr("Already Ingested Content")))
+		res = append(res, richText("\n"))
+		for _, l := range p.Duplicates {
+			res = append(res, richChanged(l))
+			res = append(res, richText("\n"))
+		}
+		res = append(res, richText("\n"))
+	}
+	if len(p.CrossDevice) != 0 {
+		res = append(res, richTitle(tr("Can't Link Across Filesystems")))
+		res = append(res, richText("\n"))
+		for _, l := range p.CrossDevice {
+			res = append(res, richChanged(l))
+			res = append(res, richText("\n"))
+		}
+		res = append(res, richText(tr("hardlinks will fail here, use the copy method instead\n\n")))
+	}
+	if p.DestPatternChanged() {
+		res = append(res, richTitle(tr("Pattern Changed Since Last Run")))
+		res = append(res, richText("\n"))
+		res = append(res, richPatternDiff(p.PrevDestPattern, p.DestPattern)...)
+		res = append(res, richText("\n"))
+	}
+	if len(p.NotExists) != 0 {
+		res = append(res, richToggle("notexists", v.Collapsed["notexists"]))
+		res = append(res, richTitle(tr("Not Exists")))
+		if v.Collapsed["notexists"] {
+			res = append(res, richTitle(" ("+strconv.Itoa(len(p.NotExists))+")"))
+		}
+		res = append(res, richText("\n"))
+		if !v.Collapsed["notexists"] {
+			for _, path := range sortedNatural(p.NotExists) {
+				if !v.matchesText(path) {
+					continue
+				}
+				res = append(res, richPath(path))
+				res = append(res, richText("\n"))
+			}
+		}
+		res = append(res, richText("\n"))
+	}
+	if len(p.Invalids) != 0 {
+		res = append(res, richToggle("invalids", v.Collapsed["invalids"]))
+		res = append(res, richTitle(tr("Invalids")))
+		if v.Collapsed["invalids"] {
+			res = append(res, richTitle(" ("+strconv.Itoa(len(p.Invalids))+")"))
+		}
+		res = append(res, richText("\n"))
+		if !v.Collapsed["invalids"] {
+			for _, path := range sortedNatural(p.Invalids) {
+				if !v.matchesText(path) {
+					continue
+				}
+				res = append(res, richPath(path))
+				res = append(res, richText("\n"))
+			}
+		}
+		res = append(res, richText("\n"))
+	}
+	if len(p.Gaps) != 0 {
+		res = append(res, richTitle(tr("Frame Gaps")))
+		res = append(res, richText("\n"))
+		for _, gap := range sortedNatural(p.Gaps) {
+			res = append(res, richChanged(gap))
+			res = append(res, richText("\n"))
+		}
+		res = append(res, richText("\n"))
+	}
+	if len(p.CaseCollisions) != 0 {
+		res = append(res, richTitle(tr("Case Collisions")))
+		res = append(res, richText("\n"))
+		for _, l := range p.CaseCollisions {
+			res = append(res, richChanged(l))
+			res = append(res, richText("\n"))
+		}
+		res = append(res, richText(tr("these merge on case-insensitive filesystems such as macOS and SMB shares\n\n")))
+	}
+	res = append(res, richList("Rewritten", p.Rewritten)...)
+	res = append(res, richList("Camera Cards", p.Cards)...)
+	res = append(res, richList("Merged", p.Merged)...)
+	res = append(res, richIgnored(p.Ignored)...)
+	// 매번 같은 순서로 보여주어 분석 결과를 비교할 수 있게 한다.
+	destDirs := make([]string, 0, len(p.DestDirSrcs))
+	for dd := range p.DestDirSrcs {
+		destDirs = append(destDirs, dd)
+	}
+	sortNatural(destDirs)
+	if v.filtering() {
+		srcs := p.plannedSources()
+		shown := len(v.filterSources(p, srcs))
+		res = append(res, richText(fmt.Sprintf(tr("showing %d of %d sources matching %q\n\n"), shown, len(srcs), strings.TrimSpace(v.Filter))))
+	}
+	switch v.Group {
+	case groupByShow:
+		return append(res, richShowGroups(p, v)...)
+	case groupBySource:
+		return append(res, richSources(p, v.filterSources(p, p.plannedSources()), true)...)
+	}
+	for _, dd := range destDirs {
+		srcs := v.filterSources(p, sortedNatural(p.DestDirSrcs[dd]))
+		if len(srcs) == 0 {
+			continue
+		}
+		collapsed := v.Collapsed["dest:"+dd]
+		res = append(res, richToggle("dest:"+dd, collapsed))
+		res = append(res, richTitle(destTitle))
+		res = append(res, richTitlePath(dd))
+		exist := p.DestDirExists[dd]
+		if p.Offline {
+			res = append(res, richTitle(tr(" (not checked)")))
+		} else if !exist {
+			res = append(res, richTitle(tr(" (to be created)")))
+		}
+		res = append(res, richText("\n"))
+		if !p.Offline {
+			res = append(res, richText(p.destSummary(dd)+"\n"))
+		}
+		if collapsed {
+			res = append(res, richText("\n"))
+			continue
+		}
+		res = append(res, richSources(p, srcs, false)...)
+		res = append(res, richText("\n"))
+	}
+	return res
+}
+
+// richSources는 소스들을 한 줄씩 보여준다. 시퀀스는 처음 나온 프레임의 자리에 한번만 보여준다.
+// withDest가 참이면 각 소스가 복사될 대상 디렉토리도 보여준다.
+func richSources(p *Program, srcs []string, withDest bool) []richtext.SpanStyle {
+	res := make([]richtext.SpanStyle, 0)
+	shown := make(map[*sequence]bool)
+	for _, src := range srcs {
+		if seq := p.SrcSeq[src]; seq != nil {
+			if shown[seq] {
+				continue
+			}
+			shown[seq] = true
+			res = append(res, richCheck(src, !p.SrcSkipped[src]))
+			res = append(res, richSeq(seq))
+			line := fmt.Sprintf(tr(" (sequence %s"), seq.Range()+p.Renumber.describe(seq))
+			if meta := describeMeta(p.SrcMeta[seq.Paths[0]]); meta != "" {
+				line += ", " + meta
+			}
+			linked, exists := 0, 0
+			for _, path := range seq.Paths {
+				if p.SrcLinked[path] {
+					linked++
+				}
+				if p.SrcDestExists[path] {
+					exists++
+				}
+			}
+			if linked == len(seq.Paths) {
+				line += ", " + tr("already linked")
+			} else if linked != 0 {
+				line += ", " + fmt.Sprintf(tr("%d frames already linked"), linked)
+			}
+			if exists == len(seq.Paths) {
+				line += ", " + tr("already exists, will be skipped")
+			} else if exists != 0 {
+				line += ", " + fmt.Sprintf(tr("%d frames already exist, will be skipped"), exists)
+			}
+			if p.DestOverridden[src] {
+				line += ", " + tr("destination set by hand")
+			}
+			res = append(res, richText(line+")"))
+			res = append(res, richOverride(src))
+			res = append(res, richText("\n"))
+			if withDest {
+				res = append(res, richDestLine(p, src)...)
+			}
+			continue
+		}
+		line := ""
+		// dest := p.DestDir[src]
+		srcName := filepath.Base(src)
+		destName := srcName // TODO: 이름 변환 지원
+		res = append(res, richCheck(src, !p.SrcSkipped[src]))
+		res = append(res, richPath(src))
+		comment := ""
+		if p.SrcIsDir[src] {
+			count := p.SrcDirFileCount[src]
+			containing := "directory, containing %d file, %s"
+			if count > 1 {
+				containing = "directory, containing %d files, %s"
+			}
+			switch {
+			case p.SrcCounting[src]:
+				comment += tr("directory, counting files...")
+			case p.SrcCountErr[src] != "":
+				comment += fmt.Sprintf(tr("directory, couldn't count files: %s"), p.SrcCountErr[src])
+			default:
+				comment += fmt.Sprintf(tr(containing), count, formatBytes(p.SrcBytes[src]))
+			}
+			if p.Flatten {
+				comment += ", " + tr("flattened")
+			}
+			if n := p.SrcExcluded[src]; n > 0 {
+				comment += ", " + fmt.Sprintf(tr("%d excluded"), n)
+			}
+			if n := p.SrcDestExisting[src]; n > 0 {
+				comment += ", " + fmt.Sprintf(tr("%d already at the destination, will be skipped"), n)
+			}
+		}
+		if meta := describeMeta(p.SrcMeta[src]); meta != "" {
+			comment += meta
+		}
+		if !p.SrcIsDir[src] {
+			switch {
+			case p.SrcChecking[src]:
+				if comment != "" {
+					comment += ", "
+				}
+				comment += tr("checking hashes...")
+			case p.SrcCountErr[src] != "":
+				if comment != "" {
+					comment += ", "
+				}
+				comment += tr("couldn't check hashes: ") + p.SrcCountErr[src]
+			}
+		}
+		if p.SrcLinked[src] {
+			if comment != "" {
+				comment += ", "
+			}
+			comment += tr("already linked")
+		}
+		if p.SrcDestExists[src] {
+			if comment != "" {
+				comment += ", "
+			}
+			comment += tr("already exists, will be skipped")
+		}
+		if p.DestOverridden[src] {
+			if comment != "" {
+				comment += ", "
+			}
+			comment += tr("destination set by hand")
+		}
+		if srcName != destName {
+			if comment != "" {
+				comment += " "
+			}
+			comment += destName
+		}
+		if comment != "" {
+			line += " (" + comment + ")"
+		}
+		res = append(res, richText(line))
+		res = append(res, richOverride(src))
+		res = append(res, richText("\n"))
+		if withDest {
+			res = append(res, richDestLine(p, src)...)
+		}
+		for _, seq := range p.SrcDirSeqs[src] {
+			res = append(res, richText("    "))
+			res = append(res, richSeq(seq))
+			res = append(res, richText(fmt.Sprintf(tr(" (sequence %s"), seq.Range()+p.Renumber.describe(seq))+")\n"))
+		}
+	}
+	return res
+}
+
+// richDestLine은 소스가 복사될 대상 디렉토리를 보여주는 줄이다.
+func richDestLine(p *Program, src string) []richtext.SpanStyle {
+	dd := p.DestDir[src]
+	res := []richtext.SpanStyle{richText("    " + tr("to ")), richPath(dd)}
+	if p.Offline {
+		res = append(res, richText(tr(" (not checked)")))
+	} else if !p.DestDirExists[dd] {
+		res = append(res, richText(tr(" (to be created)")))
 	}
+	return append(res, richText("\n"))
 }
 
-// 인풋을 분석한 프로그램 정보를 바탕으로 사용자에게 알려줄 정보를 생성한다.
-func analyzeInput(p *Program) []richtext.SpanStyle {
+func analyzeCopy(p *Program) []richtext.SpanStyle {
 	res := make([]richtext.SpanStyle, 0)
-	if len(p.NotExists) != 0 {
-		res = append(res, richTitle("Not Exists"))
-		res = append(res, richText("\n"))
-		for _, path := range p.NotExists {
-			res = append(res, richPath(path))
-			res = append(res, richText("\n"))
+	if p.Method == "catalog" {
+		var bytes int64
+		for _, f := range p.Copied {
+			bytes += f.Size
 		}
-		res = append(res, richText("\n"))
+		res = append(res, richTitle(tr("Catalog completed")))
+		res = append(res, richText("\n\n"))
+		res = append(res, richText(fmt.Sprintf(tr("%d files, %s hashed and recorded in the history, nothing was copied\n"), len(p.Copied), formatBytes(bytes))))
+		return res
 	}
-	if len(p.Invalids) != 0 {
-		res = append(res, richTitle("Invalids"))
+	if p.Sample > 0 {
+		res = append(res, richTitle(tr("Test run completed")))
+		res = append(res, richText("\n\n"))
+		res = append(res, richRunStats(p.runStats())...)
+		res = append(res, richOutcomes(p)...)
+		res = append(res, richText(tr("check the layout and permissions of these files, then run to copy the rest\n")))
+		return res
+	}
+	if len(p.failedOutcomes()) != 0 {
+		res = append(res, richTitle(tr("Copy finished with errors")))
+	} else {
+		res = append(res, richTitle(tr("Copy completed")))
+	}
+	res = append(res, richText("\n\n"))
+	res = append(res, richRunStats(p.runStats())...)
+	res = append(res, richOutcomes(p)...)
+	excluded := 0
+	for _, n := range p.SrcExcluded {
+		excluded += n
+	}
+	if excluded != 0 {
 		res = append(res, richText("\n"))
-		for _, path := range p.Invalids {
-			res = append(res, richPath(path))
-			res = append(res, richText("\n"))
-		}
+		res = append(res, richText(fmt.Sprintf(tr("%d files or directories excluded by filters\n"), excluded)))
+	}
+	if len(p.Ignored) != 0 {
 		res = append(res, richText("\n"))
+		res = append(res, richIgnored(p.Ignored)...)
 	}
-	destDirs := make([]string, 0, len(p.DestDirSrcs))
-	for dd := range p.DestDirSrcs {
-		destDirs = append(destDirs, dd)
+	return res
+}
+
+// richPatternDiff는 이전 패턴과 현재 패턴을 위아래로 놓고 달라진 부분을 강조한다.
+func richPatternDiff(prevPattern, curPattern string) []richtext.SpanStyle {
+	// 한글이 잘리지 않도록 룬 단위로 비교한다.
+	prev := []rune(strings.TrimSpace(prevPattern))
+	cur := []rune(strings.TrimSpace(curPattern))
+	// 앞뒤로 같은 부분을 제외한 가운데가 달라진 부분이다.
+	pre := 0
+	for pre < len(prev) && pre < len(cur) && prev[pre] == cur[pre] {
+		pre++
 	}
-	for _, dd := range destDirs {
-		res = append(res, richTitle("To: "))
-		res = append(res, richTitlePath(dd))
-		exist := p.DestDirExists[dd]
-		if !exist {
-			res = append(res, richTitle(" "+"(to be created)"))
-		}
-		res = append(res, richText("\n"))
-		srcs := p.DestDirSrcs[dd]
-		for _, src := range srcs {
-			line := ""
-			// dest := p.DestDir[src]
-			srcName := filepath.Base(src)
-			destName := srcName // TODO: 이름 변환 지원
-			res = append(res, richPath(src))
-			comment := ""
-			if p.SrcIsDir[src] {
-				count := p.SrcDirFileCount[src]
-				counts := strconv.Itoa(count)
-				if count > 1000 {
-					// 1000개 이상의 파일이 있어 더이상 세지 않았다.
-					// 복사 단계에서는 모든 파일이 복사될 것이다.
-					counts = "1000+"
-				}
-				plural := ""
-				if count > 1 {
-					plural = "s"
-				}
-				comment += "directory, containing " + counts + " file" + plural
-			}
-			if srcName != destName {
-				if comment != "" {
-					comment += " "
-				}
-				comment += destName
-			}
-			if comment != "" {
-				line += " (" + comment + ")"
-			}
-			res = append(res, richText(line))
-			res = append(res, richText("\n"))
-		}
+	suf := 0
+	for suf < len(prev)-pre && suf < len(cur)-pre && prev[len(prev)-1-suf] == cur[len(cur)-1-suf] {
+		suf++
+	}
+	res := make([]richtext.SpanStyle, 0)
+	for _, l := range []struct {
+		label   string
+		pattern []rune
+	}{
+		{tr("was: "), prev},
+		{tr("now: "), cur},
+	} {
+		res = append(res, richText(l.label))
+		res = append(res, richText(string(l.pattern[:pre])))
+		res = append(res, richChanged(string(l.pattern[pre:len(l.pattern)-suf])))
+		res = append(res, richText(string(l.pattern[len(l.pattern)-suf:])))
 		res = append(res, richText("\n"))
 	}
 	return res
 }
 
-func analyzeCopy(p *Program) []richtext.SpanStyle {
+// richList는 제목과 경로 목록으로 이루어진 섹션을 표시한다.
+// 목록이 비어있다면 아무것도 표시하지 않는다.
+func richList(title string, paths []string) []richtext.SpanStyle {
 	res := make([]richtext.SpanStyle, 0)
-	res = append(res, richTitle("Copy completed"))
-	res = append(res, richText("\n\n"))
-	for destDir, srcs := range p.DestDirSrcs {
-		res = append(res, richTitle("Copied: "))
-		res = append(res, richTitlePath(destDir))
+	if len(paths) == 0 {
+		return res
+	}
+	res = append(res, richTitle(tr(title)))
+	res = append(res, richText("\n"))
+	for _, path := range sortedNatural(paths) {
+		res = append(res, richPath(path))
 		res = append(res, richText("\n"))
-		for _, src := range srcs {
-			res = append(res, richPath(destDir+filepath.Base(src)))
-			res = append(res, richText("\n"))
-		}
 	}
+	res = append(res, richText("\n"))
+	return res
+}
+
+// richIgnored는 건너뛴 파일들을 보여준다. 누를 수 있는 경로와 그 이유를 따로 보여준다.
+func richIgnored(files []ignoredFile) []richtext.SpanStyle {
+	res := make([]richtext.SpanStyle, 0)
+	if len(files) == 0 {
+		return res
+	}
+	sorted := make([]ignoredFile, len(files))
+	copy(sorted, files)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return naturalLess(sorted[i].Path, sorted[j].Path)
+	})
+	res = append(res, richTitle(tr("Ignored")))
+	res = append(res, richText("\n"))
+	for _, f := range sorted {
+		res = append(res, richPath(f.Path))
+		res = append(res, richText(" ("+f.Reason+")\n"))
+	}
+	res = append(res, richText("\n"))
 	return res
 }
 
 // Copy는 프로그램 설정에 따라 분석한 소스 파일을 대상 경로로 복사한다.
 func (p *Program) Copy() error {
 	if !p.Analyzed {
-		return fmt.Errorf("paths not analyzed yet")
+		return errors.New(tr("paths not analyzed yet"))
 	}
-	copyFunc := os.Link
-	if p.Method == "copy" {
-		copyFunc = copyFile
+	if p.Offline {
+		return errors.New(tr("offline plan can't be run, analyze again without offline mode"))
+	}
+	if len(p.Recursions) != 0 && p.Method != "catalog" {
+		return fmt.Errorf(tr("can't run: %s"), p.Recursions[0])
+	}
+	if p.Method == "catalog" {
+		return p.catalog()
+	}
+	if len(p.Reingested) != 0 && !p.Reingest {
+		return fmt.Errorf(tr("already ingested: %s, check \"Ingest again\" to run anyway"), p.Reingested[0])
+	}
+	if p.StableWait > 0 {
+		changed, err := p.unstableFiles(p.StableWait)
+		if err != nil {
+			return err
+		}
+		if len(changed) != 0 {
+			if len(changed) > 1 {
+				return fmt.Errorf(tr("%s and %d more files are still being written, try again later"), changed[0], len(changed)-1)
+			}
+			return fmt.Errorf(tr("%s is still being written, try again later"), changed[0])
+		}
+	}
+	p.Copied = make([]IngestFile, 0)
+	p.Linked = make([]string, 0)
+	p.Outcomes = make([]fileOutcome, 0)
+	start := time.Now()
+	defer func() {
+		p.RunTime = time.Since(start)
+	}()
+	// 작업 기록과 결과가 매번 같은 순서가 되도록 대상 경로 순서로 복사한다.
+	destDirs := make([]string, 0, len(p.DestDirSrcs))
+	for dd := range p.DestDirSrcs {
+		destDirs = append(destDirs, dd)
 	}
-	for destDir, srcs := range p.DestDirSrcs {
+	sortNatural(destDirs)
+	// 진행 상황을 보여줄 수 있도록 복사할 파일들을 먼저 모두 모은다.
+	type destFiles struct {
+		destDir string
+		files   []srcFile
+		dests   []string
+		sizes   []int64
+	}
+	jobs := make([]destFiles, 0, len(destDirs))
+	totalFiles := 0
+	var totalBytes int64
+	for _, destDir := range destDirs {
+		srcs := p.DestDirSrcs[destDir]
 		// 소스에서 그 안의 모든 파일 경로를 분석한다.
 		// 혹시 복사 방법이 링크일 때 디렉토리 소스를 바로 링크하지 않고
 		// 그 안의 개별 파일들을 링크하는 방식을 사용하면
 		// 복사된 경로에서 실수로 파일을 지우는 것을 방지할수 있기 때문이다.
 		// 개별 파일을 링크한다면 그 안의 내용물을 지워도
 		// 소스 파일 정보가 삭제되지 않는다.
-		subPath := make(map[string]string)
+		files := make([]srcFile, 0)
 		for _, src := range srcs {
-			if p.SrcIsDir[src] {
-				srcDir := filepath.Dir(src)
-				filepath.WalkDir(src, func(s string, d fs.DirEntry, err error) error {
-					if err != nil {
-						return err
-					}
-					if d.IsDir() {
-						return nil
-					}
-					sub := s[len(srcDir):]
-					subPath[s] = sub
-					return nil
-				})
-			} else {
-				subPath[src] = filepath.Base(src)
+			if p.SrcSkipped[src] {
+				continue
+			}
+			err := p.walkSource(src, func(f srcFile) error {
+				files = append(files, f)
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("%w: %s", err, src)
+			}
+		}
+		dests, err := p.destPaths(destDir, files)
+		if err != nil {
+			return err
+		}
+		n := len(files)
+		if p.Sample > 0 && n > p.Sample {
+			// 시험 실행에서는 처음 몇개만 복사하는 것으로 어림한다.
+			n = p.Sample
+		}
+		sizes := make([]int64, len(files))
+		for i, f := range files {
+			sizes[i] = fileSize(f)
+			if i < n {
+				totalBytes += sizes[i]
 			}
 		}
+		totalFiles += n
+		jobs = append(jobs, destFiles{destDir: destDir, files: files, dests: dests, sizes: sizes})
+	}
+	p.progress.setTotal(totalFiles, totalBytes)
+	for _, job := range jobs {
 		// 링크 또는 복사 수행
-		for s, sub := range subPath {
-			d := filepath.Join(destDir, sub)
-			dDir := filepath.Dir(d)
-			_, err := os.Stat(dDir)
-			if err != nil {
-				if !errors.Is(err, os.ErrNotExist) {
-					return fmt.Errorf("%v: %s", err, dDir)
-				}
-				err := os.MkdirAll(dDir, 0755)
-				if err != nil {
-					return fmt.Errorf("make dirs: %v: %s", err, dDir)
-				}
+		copied := 0
+		for i, f := range job.files {
+			if p.Sample > 0 && copied >= p.Sample {
+				break
 			}
-			_, err = os.Lstat(d)
-			if err == nil {
-				// 파일이 이미 존재한다.
-				// 할일: 사용자가 원하면 덮어쓰기 기능을 제공해야 할까?
-				continue
-			} else if !errors.Is(err, os.ErrNotExist) {
-				return fmt.Errorf("%v: %s", err, s)
+			p.progress.startFile(f.Path, job.sizes[i])
+			outcome := p.ingestFile(f, job.dests[i], job.destDir)
+			p.progress.finishFile()
+			p.Outcomes = append(p.Outcomes, outcome)
+			switch outcome.Status {
+			case outcomeFailed:
+				if !p.ContinueOnError {
+					return outcome.err
+				}
+			case outcomeCopied, outcomeLinked, outcomeSymlinked:
+				copied++
 			}
-			err = copyFunc(s, d)
-			if err != nil {
-				return fmt.Errorf("%s file: %v", p.Method, err)
+		}
+	}
+	return p.failedError()
+}
+
+// ingestFile은 소스 파일 f를 대상 경로 d로 복사하거나 링크하고 그 결과를 반환한다.
+// 복사한 파일은 p.Copied에, 이미 링크되어 있던 파일은 p.Linked에 기록한다.
+func (p *Program) ingestFile(f srcFile, d, destDir string) fileOutcome {
+	outcome := fileOutcome{Src: f.Path, Dest: d, DestDir: destDir, file: f}
+	fail := func(err error) fileOutcome {
+		outcome.Status = outcomeFailed
+		outcome.Err = err.Error()
+		outcome.err = err
+		return outcome
+	}
+	copyFunc := os.Link
+	if p.Method == "copy" {
+		copyFunc = func(src, dest string) error {
+			return copyFile(src, dest, p.progress.addBytes)
+		}
+	}
+	dDir := filepath.Dir(d)
+	_, err := os.Stat(dDir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return fail(fmt.Errorf("%w: %s", err, dDir))
+		}
+		err := os.MkdirAll(dDir, 0755)
+		if err != nil {
+			return fail(fmt.Errorf(tr("make dirs: %w: %s"), err, dDir))
+		}
+	}
+	dfi, err := os.Lstat(d)
+	if err == nil {
+		// 파일이 이미 존재한다.
+		// 할일: 사용자가 원하면 덮어쓰기 기능을 제공해야 할까?
+		outcome.Status = outcomeExists
+		if sfi, err := os.Stat(f.Real); err == nil && os.SameFile(sfi, dfi) {
+			p.Linked = append(p.Linked, d)
+			outcome.Status = outcomeAlreadyLinked
+		}
+		return outcome
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fail(fmt.Errorf("%w: %s", err, f.Path))
+	}
+	if f.Link != "" {
+		// 심볼릭 링크는 복사 방법과 관계없이 같은 링크로 재현한다.
+		err = os.Symlink(f.Link, d)
+		if err != nil {
+			return fail(fmt.Errorf(tr("symlink file: %w"), err))
+		}
+		p.Copied = append(p.Copied, IngestFile{Src: f.Path, Dest: d})
+		outcome.Status = outcomeSymlinked
+		return outcome
+	}
+	err = copyFunc(f.Real, d)
+	if err != nil {
+		if p.Method == "copy" {
+			return fail(fmt.Errorf(tr("copy file: %w"), err))
+		}
+		return fail(fmt.Errorf(tr("link file: %w"), err))
+	}
+	rec := IngestFile{Src: f.Path, Dest: d}
+	if p.HashHistory {
+		// 다음 분석에서 같은 내용을 찾을 수 있도록 해시를 남긴다.
+		// 해시를 구하지 못해도 복사는 된 것이므로 기록에서 해시만 빠진다.
+		if fi, err := os.Stat(f.Real); err == nil {
+			if hash, err := hashFile(f.Real); err == nil {
+				rec.Size, rec.Hash = fi.Size(), hash
 			}
 		}
 	}
-	return nil
+	// 하드 링크는 소스와 같은 파일이므로 바꾸면 소스까지 읽기 전용이 된다. 복사한 파일만 바꾼다.
+	if p.ReadOnly && p.Method == "copy" {
+		err = os.Chmod(d, 0444)
+		if err != nil {
+			return fail(fmt.Errorf(tr("make read-only: %w"), err))
+		}
+	}
+	p.Copied = append(p.Copied, rec)
+	outcome.Status = outcomeLinked
+	if p.Method == "copy" {
+		outcome.Status = outcomeCopied
+		outcome.Bytes = fileSize(f)
+	}
+	return outcome
 }
 
-func parseEnvs(src string, seps []string, keys []string) (map[string]string, error) {
+// specialFileKind는 소켓, FIFO, 장치 파일처럼 복사할 수 없는 파일이면 그 종류를 반환한다.
+// 일반 파일이나 디렉토리, 심볼릭 링크라면 빈 문자열을 반환한다.
+func specialFileKind(mode fs.FileMode) string {
+	switch {
+	case mode&fs.ModeSocket != 0:
+		return "socket"
+	case mode&fs.ModeNamedPipe != 0:
+		return "named pipe"
+	case mode&fs.ModeCharDevice != 0:
+		return "character device"
+	case mode&fs.ModeDevice != 0:
+		return "device"
+	case mode&fs.ModeIrregular != 0:
+		return "irregular file"
+	}
+	return ""
+}
+
+// checkGaps는 시퀀스에 빠진 프레임이 있다면 기록한다.
+// 렌더가 덜 끝난 시퀀스를 받아들이기 전에 알아채기 위함이다.
+func (p *Program) checkGaps(seq *sequence) {
+	if missing := seq.Missing(); missing != "" {
+		p.Gaps = append(p.Gaps, seq.String()+" missing "+missing)
+	}
+}
+
+// toggleSkip은 소스 src를 작업에서 빼거나 다시 넣는다.
+// 시퀀스에 속한 소스라면 시퀀스 전체를 함께 빼거나 넣는다.
+func (p *Program) toggleSkip(src string) {
+	skip := !p.SrcSkipped[src]
+	paths := []string{src}
+	if seq := p.SrcSeq[src]; seq != nil {
+		paths = seq.Paths
+	}
+	for _, path := range paths {
+		if skip {
+			p.SrcSkipped[path] = true
+		} else {
+			delete(p.SrcSkipped, path)
+		}
+	}
+}
+
+// merge는 다른 입력 경로에 합쳐져 따로 처리하지 않는 입력 경로를 그 이유와 함께 기록한다.
+func (p *Program) merge(path, reason string) {
+	p.Merged = append(p.Merged, path+" ("+reason+")")
+}
+
+// listedParent는 src를 포함하는 디렉토리가 소스로 입력되었다면 그 디렉토리를 반환한다.
+func (p *Program) listedParent(src string) string {
+	for dir := filepath.Dir(src); ; dir = filepath.Dir(dir) {
+		if p.SrcIsDir[dir] {
+			return dir
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return ""
+		}
+	}
+}
+
+// ignoredFile은 복사하지 않고 건너뛴 파일과 그 이유이다.
+type ignoredFile struct {
+	Path   string
+	Reason string
+}
+
+// ignore는 복사하지 않고 건너뛸 파일을 그 이유와 함께 기록한다.
+func (p *Program) ignore(path, reason string) {
+	if p.ignoredSeen == nil {
+		p.ignoredSeen = make(map[string]bool)
+	}
+	key := path + "\x00" + reason
+	if p.ignoredSeen[key] {
+		return
+	}
+	p.ignoredSeen[key] = true
+	p.Ignored = append(p.Ignored, ignoredFile{Path: path, Reason: reason})
+}
+
+// splitTokens는 src를 구분자들로 나눈 토큰들을 반환한다.
+func splitTokens(src string, seps []string) []string {
+	// 경로 구분자는 슬래시와 백슬래시를 구분하지 않는다.
+	// 같은 설정으로 유닉스 경로와 윈도우즈 경로를 모두 분석할 수 있다.
+	for _, sep := range seps {
+		if sep == "/" || sep == "\\" {
+			seps = append(seps[:len(seps):len(seps)], "/", "\\")
+			break
+		}
+	}
 	vals := make([]string, 0)
 	remain := src
 	for len(remain) > 0 {
@@ -734,6 +3358,11 @@ func parseEnvs(src string, seps []string, keys []string) (map[string]string, err
 		vals = append(vals, remain[:idx])
 		remain = remain[idx+len(cutter):]
 	}
+	return vals
+}
+
+func parseEnvs(src string, seps []string, keys []string) (map[string]string, error) {
+	vals := splitTokens(src, seps)
 	idx := -1
 	for i, key := range keys {
 		if key == "..." {
@@ -780,23 +3409,145 @@ func parseEnvs(src string, seps []string, keys []string) (map[string]string, err
 	return envs, nil
 }
 
+// compileKeyRegex는 키를 찾기 위한 정규식을 컴파일한다.
+// 정규식이 비어있으면 nil을 반환한다.
+func compileKeyRegex(expr string) (*regexp.Regexp, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	named := false
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			named = true
+			break
+		}
+	}
+	if !named {
+		return nil, fmt.Errorf("no named capture group (?P<KEY>...) in %s", expr)
+	}
+	return re, nil
+}
+
+// parseEnvsRegex는 이름 있는 캡쳐 그룹을 가진 정규식으로 src에서 키 값들을 찾는다.
+// 그룹 이름이 키가 되며, 이름이 _ 인 그룹은 무시한다.
+// re가 nil이면 아무 키도 찾지 않는다.
+func parseEnvsRegex(src string, re *regexp.Regexp) (map[string]string, error) {
+	envs := make(map[string]string)
+	if re == nil {
+		return envs, nil
+	}
+	m := re.FindStringSubmatch(src)
+	if m == nil {
+		return nil, fmt.Errorf("regex not matched: %s", src)
+	}
+	for i, name := range re.SubexpNames() {
+		if name == "" || name == "_" {
+			continue
+		}
+		envs[name] = m[i]
+	}
+	return envs, nil
+}
+
+// inputPaths는 사용자가 입력한 텍스트에서 경로로 보이는 줄들을 찾는다.
+// 파일 관리자에서 복사한 것을 붙여넣을 수 있도록, 따옴표로 감싼 경로는 따옴표를 벗기고
+// 노틸러스가 앞에 붙이는 x-special/nautilus-clipboard나 copy 같은 줄은 경로가 아니므로 건너뛴다.
+func inputPaths(text string) []string {
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	lines := strings.Split(text, "\n")
+	paths := make([]string, 0)
+	for _, l := range lines {
+		l = unquotePath(l)
+		if strings.HasPrefix(l, "file://") {
+			path, ok := fileURIPath(strings.TrimSpace(l))
+			if !ok {
+				continue
+			}
+			l = path
+		}
+		if isAbsPath(l) {
+			paths = append(paths, l)
+		}
+	}
+	return paths
+}
+
+// unquotePath는 탐색기의 "경로로 복사"나 터미널로 끌어온 경로처럼 따옴표로 감싼 줄에서 따옴표를 벗긴다.
+func unquotePath(l string) string {
+	t := strings.TrimSpace(l)
+	if len(t) >= 2 && (t[0] == '"' || t[0] == '\'') && t[len(t)-1] == t[0] {
+		return t[1 : len(t)-1]
+	}
+	return l
+}
+
+// fileURIPath는 파인더나 노틸러스에서 끌어온 file:// URI를 파일 경로로 바꾼다.
+// 퍼센트 인코딩된 공백이나 유니코드 문자를 풀고, file://host/path 형식의 호스트는 버린다.
+func fileURIPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	path := u.Path
+	if u.Path == "" {
+		// file:/path 처럼 슬래시가 부족한 형식
+		path = u.Opaque
+	}
+	// file:///C:/dir 형식의 윈도우즈 경로
+	if len(path) >= 4 && path[0] == '/' && path[2] == ':' && path[3] == '/' {
+		path = path[1:]
+	}
+	return path, path != ""
+}
+
+// isAbsPath는 path가 절대 경로인지 확인한다.
+// 프로그램이 실행중인 운영체제와 관계없이 유닉스 경로(/mnt/..),
+// 윈도우즈 드라이브 경로(C:\..) 그리고 UNC 경로(\\server\share\..)를 모두 절대 경로로 본다.
+func isAbsPath(path string) bool {
+	if strings.HasPrefix(path, "/") || strings.HasPrefix(path, `\\`) {
+		return true
+	}
+	if len(path) >= 3 && path[1] == ':' && (path[2] == '\\' || path[2] == '/') {
+		c := path[0]
+		return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+	}
+	return false
+}
+
+// baseName은 경로의 마지막 요소를 반환한다.
+// filepath.Base와 달리 운영체제와 관계없이 슬래시와 백슬래시를 모두 구분자로 본다.
+func baseName(path string) string {
+	path = strings.TrimRight(path, `/\`)
+	if i := strings.LastIndexAny(path, `/\`); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
 // destDirectory는 destPattern을 이용해 소스 경로를 복사할 폴더 경로를 반환한다.
 func destDirectory(src, destPattern string, env map[string]string) (string, error) {
-	if !filepath.IsAbs(src) {
+	if !isAbsPath(src) {
 		return "", fmt.Errorf("not an absolute path: %s", src)
 	}
 	destPattern = strings.TrimSpace(destPattern)
-	unknown := ""
+	var expandErr error
 	destDir := os.Expand(destPattern, func(k string) string {
-		v, ok := env[k]
-		if !ok {
-			unknown = "$" + k
+		v, err := expandVar(k, env)
+		if err != nil {
+			if expandErr == nil {
+				expandErr = err
+			}
 			return ""
 		}
 		return v
 	})
-	if unknown != "" {
-		return "", fmt.Errorf("unknown environ variable in dest: %s", unknown)
+	if expandErr != nil {
+		return "", expandErr
 	}
 	return destDir, nil
 }
@@ -817,8 +3568,18 @@ func stringMapper(mapstr string) map[string]string {
 	return mapper
 }
 
+// configDir는 takein의 설정과 작업 기록이 저장되는 디렉토리를 반환한다.
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "takein"), nil
+}
+
 // copyFile은 파일을 복사하고 복사중 에러가 났다면 그 내용을 반환한다.
-func copyFile(src, dest string) error {
+// progress가 nil이 아니라면 복사하는 동안 복사한 바이트 수를 나누어 알린다.
+func copyFile(src, dest string, progress func(int64)) error {
 	s, err := os.Open(src)
 	if err != nil {
 		return err
@@ -829,15 +3590,47 @@ func copyFile(src, dest string) error {
 		return err
 	}
 	defer d.Close()
-	_, err = d.ReadFrom(s)
+	for {
+		// 조각으로 나누어도 LimitedReader는 ReadFrom의 빠른 복사 경로를 그대로 쓴다.
+		n, err := d.ReadFrom(io.LimitReader(s, copyChunk))
+		if progress != nil && n > 0 {
+			progress(n)
+		}
+		if err != nil {
+			return err
+		}
+		if n < copyChunk {
+			return nil
+		}
+	}
+}
+
+// copyChunk는 복사하는 동안 진행 상황을 알리는 단위이다.
+const copyChunk = 16 << 20
+
+// fileSize는 소스 파일 f의 크기이다. 심볼릭 링크이거나 크기를 알 수 없다면 0이다.
+func fileSize(f srcFile) int64 {
+	if f.Link != "" {
+		return 0
+	}
+	fi, err := os.Stat(f.Real)
 	if err != nil {
-		return err
+		return 0
 	}
-	return nil
+	return fi.Size()
 }
 
 func main() {
-	cfgDir, err := os.UserConfigDir()
+	// 하위 명령이 주어지면 창을 띄우지 않고 명령만 실행한다.
+	// macOS가 앱을 실행할 때 붙이는 -psn_ 같은 플래그는 하위 명령이 아니다.
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		err := runCommand(os.Args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	cfgDir, err := configDir()
 	if err != nil {
 		log.Fatalf("couldn't find home dir")
 	}
@@ -847,8 +3640,14 @@ func main() {
 		NameSepBy: ". _",
 		NameKeys:  "SEQ SCENE SHOT PART VER ...",
 		Dest:      "/mnt/storm/show/${SHOW}/shot/${SEQ}/${SCENE}_${SHOT}/out/",
+		Symlinks:  "follow",
+		ParseMode: "split",
+		Normalize: "NFC",
+		// 윈도우즈의 MAX_PATH
+		MaxPathLength: 260,
+		StatTimeout:   10,
 	}
-	cfgFile := filepath.Join(cfgDir, "takein", "config.toml")
+	cfgFile := filepath.Join(cfgDir, "config.toml")
 	_, err = os.Stat(cfgFile)
 	if err != nil {
 		if !os.IsNotExist(err) {
@@ -860,58 +3659,216 @@ func main() {
 			log.Fatal(err)
 		}
 	}
+	statTimeout = time.Duration(cfg.StatTimeout) * time.Second
+	// 분석 전에도 대상 경로 미리보기에 ${SHOWROOT}를 쓸 수 있도록 미리 읽어둔다.
+	showRoots, err := loadShowRegistry(cfg.ShowRegistry)
+	if err != nil {
+		log.Print(err)
+	}
 	w := new(app.Window)
 	w.Option(app.Title("Takein"))
+	w.Option(windowOptions(cfg)...)
 	prog := &Program{
 		Analyzed: false,
 	}
 	th := material.NewTheme()
-	th.Shaper = text.NewShaper(text.WithCollection(gofont.Collection()))
+	fonts, errs := loadFonts(cfg.Fonts)
+	for _, err := range errs {
+		log.Print(err)
+	}
+	th.Shaper = text.NewShaper(text.WithCollection(fonts))
+	th.Face = textFont.Typeface
+	setLanguage(cfg.Language)
+	if cfg.HighContrast {
+		applyPalette(th, highContrastPalette)
+	}
+	// 키 분석 방법과 대상 경로 패턴은 지금 프로필의 것을 쓴다.
+	profile := cfg.profile(cfg.Profile)
 	pathSepEd := new(widget.Editor)
 	pathSepEd.SingleLine = true
-	pathSepEd.SetText(cfg.PathSepBy)
+	pathSepEd.SetText(profile.PathSepBy)
 	pathKeyEd := new(widget.Editor)
-	pathKeyEd.SetText(cfg.PathKeys)
+	pathKeyEd.SetText(profile.PathKeys)
 	pathKeyEd.SingleLine = true
 	nameSepEd := new(widget.Editor)
-	nameSepEd.SetText(cfg.NameSepBy)
+	nameSepEd.SetText(profile.NameSepBy)
 	nameSepEd.SingleLine = true
 	nameKeyEd := new(widget.Editor)
-	nameKeyEd.SetText(cfg.NameKeys)
+	nameKeyEd.SetText(profile.NameKeys)
 	nameKeyEd.SingleLine = true
+	pathRegexEd := new(widget.Editor)
+	pathRegexEd.SetText(profile.PathRegex)
+	pathRegexEd.SingleLine = true
+	nameRegexEd := new(widget.Editor)
+	nameRegexEd.SetText(profile.NameRegex)
+	nameRegexEd.SingleLine = true
+	remapEd := new(widget.Editor)
+	remapEd.SetText(cfg.Remap)
+	remapEd.SingleLine = true
+	excludeEd := new(widget.Editor)
+	excludeEd.SetText(cfg.Excludes)
+	excludeEd.SingleLine = true
+	includeEd := new(widget.Editor)
+	includeEd.SetText(cfg.Includes)
+	includeEd.SingleLine = true
+	maxDepthEd := new(widget.Editor)
+	if cfg.MaxDepth > 0 {
+		maxDepthEd.SetText(strconv.Itoa(cfg.MaxDepth))
+	}
+	maxDepthEd.SingleLine = true
+	renumberEd := new(widget.Editor)
+	renumberEd.SetText(cfg.Renumber)
+	renumberEd.SingleLine = true
+	framePadEd := new(widget.Editor)
+	if profile.FramePad > 0 {
+		framePadEd.SetText(strconv.Itoa(profile.FramePad))
+	}
+	framePadEd.SingleLine = true
+	parseRad := new(widget.Enum)
+	parseRad.Value = profile.ParseMode
+	profileRad := new(widget.Enum)
+	profileRad.Value = cfg.Profile
 	input := new(widget.Editor)
 	// display only shows the result.
 	// by separating it, we can keep history of the editor clean.
 	dest := new(widget.Editor)
 	dest.SingleLine = true
-	dest.SetText(cfg.Dest)
+	dest.SetText(profile.Dest)
 	analyzeBtn := new(widget.Clickable)
+	suggestBtn := new(widget.Clickable)
+	previewBtn := new(widget.Clickable)
+	toolsBtn := new(widget.Clickable)
+	learnBtn := new(widget.Clickable)
+	learnSrcEd := new(widget.Editor)
+	learnSrcEd.SingleLine = true
+	learnDestEd := new(widget.Editor)
+	learnDestEd.SingleLine = true
+	compareSrcEd := new(widget.Editor)
+	compareSrcEd.SingleLine = true
+	compareDestEd := new(widget.Editor)
+	compareDestEd.SingleLine = true
+	manifestEd := new(widget.Editor)
+	manifestEd.SingleLine = true
+	sandboxEd := new(widget.Editor)
+	sandboxEd.SingleLine = true
+	overrideEd := new(widget.Editor)
+	overrideEd.SingleLine = true
+	overrideEd.Submit = true
+	filterEd := new(widget.Editor)
+	filterEd.SingleLine = true
+	groupRad := new(widget.Enum)
+	groupRad.Value = groupByDest
 	cancelBtn := new(widget.Clickable)
+	savePlanBtn := new(widget.Clickable)
 	runBtn := new(widget.Clickable)
 	okBtn := new(widget.Clickable)
 	methodRad := new(widget.Enum)
 	methodRad.Value = "link"
+	symlinkRad := new(widget.Enum)
+	symlinkRad.Value = cfg.Symlinks
+	readOnlyChk := new(widget.Bool)
+	readOnlyChk.Value = cfg.ReadOnly
+	skipHiddenChk := new(widget.Bool)
+	skipHiddenChk.Value = cfg.SkipHidden
+	flattenChk := new(widget.Bool)
+	flattenChk.Value = cfg.Flatten
+	offlineChk := new(widget.Bool)
+	offlineChk.Value = cfg.Offline
+	waitChk := new(widget.Bool)
+	waitChk.Value = cfg.WaitMissing
+	continueChk := new(widget.Bool)
+	continueChk.Value = cfg.ContinueOnError
+	cardRad := new(widget.Enum)
+	cardRad.Value = cfg.Card
+	probeChk := new(widget.Bool)
+	probeChk.Value = cfg.Probe
+	thumbChk := new(widget.Bool)
+	thumbChk.Value = cfg.Thumbnails
+	exrChk := new(widget.Bool)
+	exrChk.Value = cfg.ExrHeaders
+	exifChk := new(widget.Bool)
+	exifChk.Value = cfg.ExifDate
 	notifier := new(widget.Editor)
 	notifier.SingleLine = true
 	notifier.ReadOnly = true
 	ui := &UI{
 		Program:             prog,
 		Window:              w,
+		drops:               make(chan []string, 8),
+		chosen:              make(chan chooseResult, 1),
+		BrowseButton:        new(widget.Clickable),
+		BrowseDirsButton:    new(widget.Clickable),
+		BrowseDestButton:    new(widget.Clickable),
+		OverrideEditor:      overrideEd,
+		ApplyDestButton:     new(widget.Clickable),
+		CloseDestButton:     new(widget.Clickable),
+		ConfirmRunButton:    new(widget.Clickable),
+		ConfirmBackButton:   new(widget.Clickable),
+		View:                new(reportView),
+		FilterEditor:        filterEd,
+		GroupRadio:          groupRad,
+		CopyButton:          new(widget.Clickable),
+		ExportButton:        new(widget.Clickable),
+		ThumbCheck:          thumbChk,
+		RetryButton:         new(widget.Clickable),
+		LanguageButton:      new(widget.Clickable),
+		InputSplit:          &split{Ratio: cfg.InputSplit, Default: unit.Dp(240)},
+		thumbs:              make(map[string]*thumbnail),
+		thumbDone:           make(chan *thumbnail, 16),
+		thumbSlots:          make(chan struct{}, 2),
+		Config:              cfg,
 		Theme:               th,
 		ConfigFile:          cfgFile,
 		PathSeparatorEditor: pathSepEd,
 		PathKeyEditor:       pathKeyEd,
 		NameSeparatorEditor: nameSepEd,
 		NameKeyEditor:       nameKeyEd,
+		PathRegexEditor:     pathRegexEd,
+		NameRegexEditor:     nameRegexEd,
+		RemapEditor:         remapEd,
+		ExcludeEditor:       excludeEd,
+		IncludeEditor:       includeEd,
+		MaxDepthEditor:      maxDepthEd,
+		RenumberEditor:      renumberEd,
+		FramePadEditor:      framePadEd,
 		InputEditor:         input,
 		DestEditor:          dest,
 		List:                &widget.List{List: layout.List{Axis: layout.Vertical}},
+		LineCheckList:       &widget.List{List: layout.List{Axis: layout.Vertical}},
 		AnalyzeButton:       analyzeBtn,
+		SuggestButton:       suggestBtn,
+		PreviewButton:       previewBtn,
+		ToolsButton:         toolsBtn,
+		LearnButton:         learnBtn,
+		LearnSrcEditor:      learnSrcEd,
+		LearnDestEditor:     learnDestEd,
+		CompareSrcEditor:    compareSrcEd,
+		CompareDestEditor:   compareDestEd,
+		CompareButton:       new(widget.Clickable),
+		ManifestEditor:      manifestEd,
+		SandboxEditor:       sandboxEd,
 		CancelButton:        cancelBtn,
+		SavePlanButton:      savePlanBtn,
 		RunButton:           runBtn,
+		TestButton:          new(widget.Clickable),
 		OKButton:            okBtn,
 		MethodRadio:         methodRad,
+		SymlinkRadio:        symlinkRad,
+		ParseRadio:          parseRad,
+		ProfileRadio:        profileRad,
+		ReadOnlyCheck:       readOnlyChk,
+		HiddenCheck:         skipHiddenChk,
+		FlattenCheck:        flattenChk,
+		OfflineCheck:        offlineChk,
+		WaitCheck:           waitChk,
+		ReingestCheck:       new(widget.Bool),
+		ContinueCheck:       continueChk,
+		CardRadio:           cardRad,
+		ProbeCheck:          probeChk,
+		ExrCheck:            exrChk,
+		ExifCheck:           exifChk,
 		Notifier:            notifier,
+		ShowRoots:           showRoots,
 	}
 	go func() {
 		err := ui.Loop()
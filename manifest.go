@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// manifestSum은 납품 목록에 적힌 파일 하나의 해시이다.
+type manifestSum struct {
+	Algo string
+	Hex  string
+}
+
+// manifest는 업체가 보낸 MHL 파일이나 md5sum 형식의 해시 목록이다.
+// 목록의 경로들은 목록 파일이 있는 디렉토리 Dir을 기준으로 한 상대 경로이다.
+type manifest struct {
+	File string
+	Dir  string
+	Sums map[string]manifestSum
+}
+
+// mhlHash는 ASC MHL 파일의 hash 요소이다. 버전 1은 file, 버전 2는 path 요소에 경로를 적는다.
+type mhlHash struct {
+	File   string `xml:"file"`
+	Path   string `xml:"path"`
+	MD5    string `xml:"md5"`
+	SHA1   string `xml:"sha1"`
+	SHA256 string `xml:"sha256"`
+}
+
+type mhlList struct {
+	Hashes   []mhlHash `xml:"hash"`
+	V2Hashes []mhlHash `xml:"hashes>hash"`
+}
+
+// loadManifest는 해시 목록 파일 file을 읽는다.
+// MHL(XML)과, "해시  경로" 또는 "MD5 (경로) = 해시" 형식의 md5sum, sha1sum, sha256sum 목록을 읽을 수 있다.
+func loadManifest(file string) (*manifest, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	m := &manifest{File: filepath.Clean(file), Dir: filepath.Dir(file), Sums: make(map[string]manifestSum)}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		err = m.parseMHL(data)
+	} else {
+		err = m.parseSumList(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, file)
+	}
+	if len(m.Sums) == 0 {
+		return nil, fmt.Errorf("no md5, sha1 or sha256 hashes: %s", file)
+	}
+	return m, nil
+}
+
+func (m *manifest) parseMHL(data []byte) error {
+	var l mhlList
+	if err := xml.Unmarshal(data, &l); err != nil {
+		return err
+	}
+	for _, h := range append(l.Hashes, l.V2Hashes...) {
+		path := strings.TrimSpace(h.File)
+		if path == "" {
+			path = strings.TrimSpace(h.Path)
+		}
+		// xxHash처럼 표준 라이브러리에 없는 해시만 있는 파일은 확인하지 않는다.
+		switch {
+		case h.SHA256 != "":
+			m.add(path, "sha256", h.SHA256)
+		case h.SHA1 != "":
+			m.add(path, "sha1", h.SHA1)
+		case h.MD5 != "":
+			m.add(path, "md5", h.MD5)
+		}
+	}
+	return nil
+}
+
+func (m *manifest) parseSumList(data []byte) error {
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	n := 0
+	for sc.Scan() {
+		n++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// BSD 형식: MD5 (path) = hash
+		if algo, rest, ok := strings.Cut(line, " ("); ok && strings.Contains(rest, ") = ") {
+			i := strings.LastIndex(rest, ") = ")
+			m.add(rest[:i], strings.ToLower(strings.ReplaceAll(algo, "-", "")), rest[i+4:])
+			continue
+		}
+		sum, path, ok := strings.Cut(line, " ")
+		if !ok {
+			return fmt.Errorf("line %d: no path after the hash", n)
+		}
+		// md5sum은 바이너리 모드에서 경로 앞에 *를 붙인다.
+		path = strings.TrimPrefix(strings.TrimLeft(path, " "), "*")
+		algo := map[int]string{32: "md5", 40: "sha1", 64: "sha256"}[len(sum)]
+		if algo == "" {
+			return fmt.Errorf("line %d: unknown hash: %s", n, sum)
+		}
+		m.add(path, algo, sum)
+	}
+	return sc.Err()
+}
+
+func (m *manifest) add(path, algo, sum string) {
+	if path == "" {
+		return
+	}
+	key := filepath.ToSlash(filepath.Clean(filepath.FromSlash(path)))
+	m.Sums[key] = manifestSum{Algo: algo, Hex: strings.ToLower(strings.TrimSpace(sum))}
+}
+
+// newHash는 목록에 쓰인 해시 알고리즘의 hash.Hash를 만든다.
+func newHash(algo string) (hash.Hash, error) {
+	switch algo {
+	case "md5":
+		return md5.New(), nil
+	case "sha1":
+		return sha1.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	}
+	return nil, fmt.Errorf("unsupported hash: %s", algo)
+}
+
+// verify는 파일 path를 목록과 비교해 문제가 있다면 그것을 설명하는 문자열을 반환한다.
+// 목록 디렉토리 밖의 파일은 납품물이 아니므로 확인하지 않는다.
+// 목록 파일 자신과 ASC MHL 버전 2가 목록을 두는 ascmhl 디렉토리도 확인하지 않는다.
+func (m *manifest) verify(path string) (string, error) {
+	rel, err := filepath.Rel(m.Dir, path)
+	if err != nil || !isWithin(path, m.Dir) || filepath.Clean(path) == m.File {
+		return "", nil
+	}
+	if strings.HasPrefix(filepath.ToSlash(rel), "ascmhl/") {
+		return "", nil
+	}
+	sum, ok := m.Sums[filepath.ToSlash(rel)]
+	if !ok {
+		return path + " is not in the manifest", nil
+	}
+	h, err := newHash(sum.Algo)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != sum.Hex {
+		return fmt.Sprintf("%s doesn't match the manifest (%s %s, expected %s)", path, sum.Algo, got, sum.Hex), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import "sort"
+
+// naturalLess는 문자열 안의 숫자를 글자가 아닌 수로 비교해 a가 b보다 앞인지 확인한다.
+// shot2가 shot10보다, plate.999.exr이 plate.1000.exr보다 앞에 온다.
+// 값이 같은 숫자는 자릿수가 적은 쪽이 앞에 온다. (img.1.exr, img.01.exr)
+func naturalLess(a, b string) bool {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		ca, cb := a[i], b[j]
+		if isDigit(ca) && isDigit(cb) {
+			si, sj := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			da, db := trimZeros(a[si:i]), trimZeros(b[sj:j])
+			if len(da) != len(db) {
+				return len(da) < len(db)
+			}
+			if da != db {
+				return da < db
+			}
+			if i-si != j-sj {
+				return i-si < j-sj
+			}
+			continue
+		}
+		if ca != cb {
+			return ca < cb
+		}
+		i++
+		j++
+	}
+	return len(a)-i < len(b)-j
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// trimZeros는 숫자 앞의 0들을 없앤다. 모두 0이라면 0 하나를 남긴다.
+func trimZeros(d string) string {
+	for len(d) > 1 && d[0] == '0' {
+		d = d[1:]
+	}
+	return d
+}
+
+// sortNatural은 문자열들을 naturalLess의 순서로 정렬한다.
+func sortNatural(ss []string) {
+	sort.SliceStable(ss, func(i, j int) bool {
+		return naturalLess(ss[i], ss[j])
+	})
+}
+
+// sortedNatural은 ss를 바꾸지 않고 naturalLess의 순서로 정렬된 복사본을 반환한다.
+func sortedNatural(ss []string) []string {
+	sorted := append([]string(nil), ss...)
+	sortNatural(sorted)
+	return sorted
+}
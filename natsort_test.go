@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortNatural(t *testing.T) {
+	cases := []struct {
+		in   []string
+		want []string
+	}{
+		{
+			[]string{"shot10", "shot2", "shot1"},
+			[]string{"shot1", "shot2", "shot10"},
+		},
+		{
+			[]string{"plate.1000.exr", "plate.999.exr"},
+			[]string{"plate.999.exr", "plate.1000.exr"},
+		},
+		{
+			[]string{"img.01.exr", "img.1.exr", "img.001.exr"},
+			[]string{"img.1.exr", "img.01.exr", "img.001.exr"},
+		},
+		{
+			[]string{"b", "a10b", "a10", "a9z"},
+			[]string{"a9z", "a10", "a10b", "b"},
+		},
+		{
+			[]string{"v0", "v00", "v"},
+			[]string{"v", "v0", "v00"},
+		},
+	}
+	for _, c := range cases {
+		got := sortedNatural(c.in)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("sortedNatural(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// parseNormForm은 설정의 유니코드 정규화 형식을 확인한다.
+// "NFC"(기본값)와 "NFD"는 그 형식을, "none"은 정규화하지 않음을 뜻하는 빈 문자열을 반환한다.
+func parseNormForm(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "nfc":
+		return "nfc", nil
+	case "nfd":
+		return "nfd", nil
+	case "none":
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown unicode normalization: %s", name)
+}
+
+// normalizeName은 s를 form 형식으로 정규화한다. form이 비어있으면 그대로 반환한다.
+func normalizeName(s, form string) string {
+	switch form {
+	case "nfc":
+		return norm.NFC.String(s)
+	case "nfd":
+		return norm.NFD.String(s)
+	}
+	return s
+}
+
+// normVariants는 path를 NFC와 NFD로 정규화한 경로들 중 path와 다른 것들이다.
+// macOS에서 복사한 한글 경로는 NFD이고 리눅스 저장소의 이름은 NFC인 경우가 많아,
+// 입력된 그대로는 찾을 수 없는 경로를 다른 형식으로 찾아볼 때 쓴다.
+func normVariants(path string) []string {
+	vars := make([]string, 0, 2)
+	for _, f := range []norm.Form{norm.NFC, norm.NFD} {
+		v := f.String(path)
+		if v != path && (len(vars) == 0 || vars[0] != v) {
+			vars = append(vars, v)
+		}
+	}
+	return vars
+}
+
+// existingForm은 path가 존재하지 않는다면 다른 정규화 형식으로 존재하는 경로를 찾는다.
+// 찾지 못하면 path를 그대로 반환한다.
+func existingForm(path string, exists func(path string) (bool, error)) string {
+	if ok, err := exists(path); ok || err != nil {
+		return path
+	}
+	for _, v := range normVariants(path) {
+		if ok, _ := exists(v); ok {
+			return v
+		}
+	}
+	return path
+}
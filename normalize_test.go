@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestParseNormForm(t *testing.T) {
+	cases := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", "nfc", false},
+		{"NFC", "nfc", false},
+		{" nfd ", "nfd", false},
+		{"none", "", false},
+		{"NFKC", "", true},
+	}
+	for _, c := range cases {
+		got, err := parseNormForm(c.name)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Errorf("parseNormForm(%q) = %q, %v, want %q", c.name, got, err, c.want)
+		}
+	}
+}
+
+func TestExistingForm(t *testing.T) {
+	nfc := "/mnt/storm/촬영본"
+	nfd := norm.NFD.String(nfc)
+	if nfc == nfd {
+		t.Fatal("NFC and NFD forms are the same")
+	}
+	if got := normalizeName(nfd, "nfc"); got != nfc {
+		t.Errorf("normalizeName(nfd, nfc) = %q", got)
+	}
+	if got := normalizeName(nfd, ""); got != nfd {
+		t.Errorf("normalizeName(nfd, none) changed the name")
+	}
+	only := func(path string) func(string) (bool, error) {
+		return func(p string) (bool, error) { return p == path, nil }
+	}
+	cases := []struct {
+		path   string
+		exists string
+		want   string
+	}{
+		{nfd, nfc, nfc},
+		{nfc, nfd, nfd},
+		{nfc, nfc, nfc},
+		{nfc, "", nfc},
+		{"/mnt/ascii", "", "/mnt/ascii"},
+	}
+	for _, c := range cases {
+		if got := existingForm(c.path, only(c.exists)); got != c.want {
+			t.Errorf("existingForm(%q) = %q, want %q", c.path, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"gioui.org/x/richtext"
+)
+
+// destTitle은 분석 화면과 결과 화면에서 대상 디렉토리 묶음의 첫 줄을 시작하는 제목이다.
+const destTitle = "To: "
+
+// openPath는 운영체제의 기본 프로그램으로 path를 연다. 디렉토리라면 파일 관리자로 연다.
+func openPath(path string) error {
+	openCmd := map[string]string{
+		"darwin":  "open",
+		"linux":   "xdg-open",
+		"windows": "explorer",
+	}[runtime.GOOS]
+	if openCmd == "" {
+		return fmt.Errorf("can't open files on %s", runtime.GOOS)
+	}
+	return exec.Command(openCmd, path).Start()
+}
+
+// runOpener는 설정된 명령 opener로 path를 연다. 인자의 ${PATH}는 path로, ${DIR}는 path가 있는 디렉토리로 바뀐다.
+// 어느 인자에도 ${PATH}나 ${DIR}가 없다면 path를 마지막 인자로 붙인다.
+func runOpener(opener []string, path string) error {
+	r := strings.NewReplacer("${PATH}", path, "${DIR}", filepath.Dir(path))
+	args := make([]string, 0, len(opener)+1)
+	used := false
+	for _, arg := range opener {
+		if strings.Contains(arg, "${PATH}") || strings.Contains(arg, "${DIR}") {
+			used = true
+		}
+		args = append(args, r.Replace(arg))
+	}
+	if !used {
+		args = append(args, path)
+	}
+	err := exec.Command(args[0], args[1:]...).Start()
+	if err != nil {
+		return fmt.Errorf("opener: %w", err)
+	}
+	return nil
+}
+
+// openResultPath는 결과 화면에서 누른 경로를 Opener 설정이 있다면 그 명령으로 열고, 없다면 showPath로 보여준다.
+func (ui *UI) openResultPath(path string) error {
+	if len(ui.Config.Opener) != 0 {
+		return runOpener(ui.Config.Opener, path)
+	}
+	return showPath(path)
+}
+
+// showPath는 결과 화면에서 누른 경로를 보여준다. 파일은 파일 관리자에서 선택된 채로 보여주고,
+// 디렉토리는 파일 관리자로 연다. 아직 없는 경로는 존재하는 가장 가까운 부모 디렉토리를 연다.
+func showPath(path string) error {
+	fi, err := safeStat(path)
+	if err != nil {
+		dir, err := existingAncestor(path)
+		if err != nil {
+			return err
+		}
+		return openPath(dir)
+	}
+	if fi.IsDir() {
+		return openPath(path)
+	}
+	return revealPath(path)
+}
+
+// lineDest는 줄 line이 대상 디렉토리 묶음의 첫 줄이라면 그 대상 디렉토리를, 아니라면 빈 문자열을 반환한다.
+func lineDest(line []richtext.SpanStyle) string {
+	for i := 0; i+1 < len(line); i++ {
+		if line[i].Content == destTitle {
+			return line[i+1].Content
+		}
+	}
+	return ""
+}
+
+// canOpenDest는 대상 디렉토리 dd를 열어볼 수 있는지 확인한다. 아직 만들어지지 않았다면 열 수 없다.
+// 복사중에는 복사 작업의 고루틴이 Program을 바꾸므로 읽지 않고, 열 수 없는 것으로 본다.
+func (ui *UI) canOpenDest(dd string) bool {
+	if ui.copying() {
+		return false
+	}
+	p := ui.Program
+	return !p.Offline && (p.DestDirExists[dd] || len(p.Outcomes) != 0)
+}
+
+// hasDestLines는 분석 화면이나 결과 화면에 열어볼 수 있는 대상 디렉토리 묶음이 있는지 확인한다.
+func (ui *UI) hasDestLines() bool {
+	for i, s := range ui.Result {
+		if s.Content == destTitle && i+1 < len(ui.Result) && ui.canOpenDest(ui.Result[i+1].Content) {
+			return true
+		}
+	}
+	return false
+}
+
+// openDestButton은 대상 디렉토리 dd를 여는 버튼이다.
+func (ui *UI) openDestButton(dd string) *widget.Clickable {
+	if ui.openDestButtons == nil {
+		ui.openDestButtons = make(map[string]*widget.Clickable)
+	}
+	btn := ui.openDestButtons[dd]
+	if btn == nil {
+		btn = new(widget.Clickable)
+		ui.openDestButtons[dd] = btn
+	}
+	return btn
+}
+
+// openDests는 "Open destination" 버튼이 눌린 대상 디렉토리를 연다.
+func (ui *UI) openDests(gtx C) {
+	for dd, btn := range ui.openDestButtons {
+		if !btn.Clicked(gtx) {
+			continue
+		}
+		err := ui.openResultPath(dd)
+		if err != nil {
+			ui.Notifier.SetText(explainError(err))
+			ui.NotifyIsError = true
+		}
+	}
+}
+
+// layoutDestLine은 대상 디렉토리 묶음의 첫 줄 text 옆에 그 디렉토리를 다른 프로그램으로
+// 끌어낼 손잡이(지원하지 않는 플랫폼에서는 경로 복사 버튼)와 그 디렉토리를 여는 버튼을 그린다.
+func (ui *UI) layoutDestLine(gtx C, dd string, text layout.Widget) D {
+	children := []layout.FlexChild{
+		layout.Flexed(1, text),
+		layout.Rigid(layout.Spacer{Width: unit.Dp(4)}.Layout),
+		layout.Rigid(func(gtx C) D {
+			return ui.layoutDragHandle(gtx, dd)
+		}),
+		layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout),
+	}
+	children = append(children,
+		layout.Rigid(func(gtx C) D {
+			btn := material.Button(ui.Theme, ui.openDestButton(dd), tr("Open destination"))
+			btn.TextSize = unit.Sp(12)
+			btn.Inset = layout.UniformInset(unit.Dp(6))
+			return btn.Layout(gtx)
+		}),
+	)
+	return layout.Flex{Alignment: layout.Middle}.Layout(gtx, children...)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gioui.org/x/richtext"
+)
+
+// 복사 작업에서 파일 하나를 처리한 결과
+const (
+	outcomeCopied        = "copied"
+	outcomeLinked        = "linked"
+	outcomeSymlinked     = "symlinked"
+	outcomeExists        = "skipped, already exists"
+	outcomeAlreadyLinked = "already linked"
+	outcomeFailed        = "failed"
+)
+
+// fileOutcome은 복사 작업에서 파일 하나를 처리한 결과이다.
+type fileOutcome struct {
+	Src     string
+	Dest    string
+	DestDir string
+	Status  string
+	// Bytes는 복사해서 쓴 바이트 수로, 링크했거나 건너뛴 파일은 0이다.
+	Bytes int64
+	// Err는 실패한 이유이다.
+	Err string
+	err error
+	// file은 다시 시도할 때 쓰는 소스 파일 정보이다.
+	file srcFile
+}
+
+// failedOutcomes는 실패한 파일들의 결과이다.
+func (p *Program) failedOutcomes() []fileOutcome {
+	failed := make([]fileOutcome, 0)
+	for _, o := range p.Outcomes {
+		if o.Status == outcomeFailed {
+			failed = append(failed, o)
+		}
+	}
+	return failed
+}
+
+// retryFailed는 마지막 복사 작업에서 실패한 파일들만 다시 복사하고 그 결과를 p.Outcomes에 반영한다.
+// 다시 분석하지 않으므로 그 사이에 바뀐 소스나 대상 경로는 알아채지 못한다.
+func (p *Program) retryFailed() error {
+	if !p.Analyzed {
+		return errors.New(tr("paths not analyzed yet"))
+	}
+	sizes := make(map[string]int64)
+	var total int64
+	failed := p.failedOutcomes()
+	for _, o := range failed {
+		sizes[o.Src] = fileSize(o.file)
+		total += sizes[o.Src]
+	}
+	p.progress.setTotal(len(failed), total)
+	start := time.Now()
+	defer func() {
+		p.RunTime += time.Since(start)
+	}()
+	for i, o := range p.Outcomes {
+		if o.Status != outcomeFailed {
+			continue
+		}
+		p.progress.startFile(o.Src, sizes[o.Src])
+		p.Outcomes[i] = p.ingestFile(o.file, o.Dest, o.DestDir)
+		p.progress.finishFile()
+		if p.Outcomes[i].Status == outcomeFailed && !p.ContinueOnError {
+			return p.Outcomes[i].err
+		}
+	}
+	return p.failedError()
+}
+
+// failedError는 실패한 파일이 있다면 그 수와 첫번째 실패 이유를 알리는 에러를, 없다면 nil을 반환한다.
+func (p *Program) failedError() error {
+	failed := p.failedOutcomes()
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf(tr("%d of %d files failed, first: %s"), len(failed), len(p.Outcomes), failed[0].Err)
+}
+
+// runStats는 복사 작업 전체의 통계이다.
+type runStats struct {
+	Elapsed time.Duration
+	// Bytes는 대상 경로에 쓴 바이트 수로, 링크는 세지 않는다.
+	Bytes   int64
+	Copied  int
+	Linked  int
+	Skipped int
+	Failed  int
+}
+
+// runStats는 마지막 복사 작업의 통계를 구한다. 심볼릭 링크도 링크로, 이미 있던 파일은 건너뛴 것으로 센다.
+func (p *Program) runStats() runStats {
+	st := runStats{Elapsed: p.RunTime}
+	for _, o := range p.Outcomes {
+		st.Bytes += o.Bytes
+		switch o.Status {
+		case outcomeCopied:
+			st.Copied++
+		case outcomeLinked, outcomeSymlinked:
+			st.Linked++
+		case outcomeExists, outcomeAlreadyLinked:
+			st.Skipped++
+		case outcomeFailed:
+			st.Failed++
+		}
+	}
+	return st
+}
+
+// Rate는 평균 초당 바이트 수이다.
+func (st runStats) Rate() float64 {
+	if st.Elapsed <= 0 {
+		return 0
+	}
+	return float64(st.Bytes) / st.Elapsed.Seconds()
+}
+
+// MarshalJSON은 내보내는 보고서에서 시간을 초 단위로, 평균 속도를 함께 쓴다.
+func (st runStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ElapsedSeconds float64 `json:"elapsed_seconds"`
+		BytesWritten   int64   `json:"bytes_written"`
+		BytesPerSecond float64 `json:"bytes_per_second"`
+		Copied         int     `json:"copied"`
+		Linked         int     `json:"linked"`
+		Skipped        int     `json:"skipped"`
+		Failed         int     `json:"failed"`
+	}{st.Elapsed.Seconds(), st.Bytes, st.Rate(), st.Copied, st.Linked, st.Skipped, st.Failed})
+}
+
+// richRunStats는 복사 작업에 걸린 시간과 평균 속도, 결과별 파일 수를 보여준다.
+func richRunStats(st runStats) []richtext.SpanStyle {
+	res := make([]richtext.SpanStyle, 0)
+	line := fmt.Sprintf(tr("took %s, %s written"), st.Elapsed.Round(time.Second), formatBytes(st.Bytes))
+	if st.Bytes > 0 && st.Elapsed >= time.Second {
+		line += fmt.Sprintf(tr(" at %s/s on average"), formatBytes(int64(st.Rate())))
+	}
+	res = append(res, richText(line+"\n"))
+	res = append(res, richText(fmt.Sprintf(tr("%d copied, %d linked, %d skipped"), st.Copied, st.Linked, st.Skipped)))
+	if st.Failed != 0 {
+		res = append(res, richChanged(fmt.Sprintf(tr(", %d failed"), st.Failed)))
+	}
+	res = append(res, richText("\n\n"))
+	return res
+}
+
+// outcomeSummary는 결과별 파일 수를 "12 copied, 3 skipped, already exists" 처럼 요약한다.
+func outcomeSummary(outcomes []fileOutcome) string {
+	order := []string{outcomeCopied, outcomeLinked, outcomeSymlinked, outcomeAlreadyLinked, outcomeExists, outcomeFailed}
+	counts := make(map[string]int)
+	for _, o := range outcomes {
+		counts[o.Status]++
+	}
+	line := ""
+	for _, status := range order {
+		if counts[status] == 0 {
+			continue
+		}
+		if line != "" {
+			line += ", "
+		}
+		line += strconv.Itoa(counts[status]) + " " + status
+	}
+	if line == "" {
+		return "no files"
+	}
+	return line
+}
+
+// richOutcomes는 대상 디렉토리별로 각 파일의 결과를 보여준다. 실패한 파일은 그 이유와 함께 눈에 띄게 보여준다.
+func richOutcomes(p *Program) []richtext.SpanStyle {
+	res := make([]richtext.SpanStyle, 0)
+	byDir := make(map[string][]fileOutcome)
+	for _, o := range p.Outcomes {
+		byDir[o.DestDir] = append(byDir[o.DestDir], o)
+	}
+	destDirs := make([]string, 0, len(byDir))
+	for dd := range byDir {
+		destDirs = append(destDirs, dd)
+	}
+	sortNatural(destDirs)
+	for _, dd := range destDirs {
+		res = append(res, richTitle(destTitle))
+		res = append(res, richTitlePath(dd))
+		res = append(res, richText("\n"))
+		res = append(res, richText(outcomeSummary(byDir[dd])+"\n"))
+		for _, o := range byDir[dd] {
+			res = append(res, richPath(o.Dest))
+			if o.Status == outcomeFailed {
+				res = append(res, richChanged(" failed: "+o.Err))
+			} else {
+				res = append(res, richText(" "+o.Status))
+			}
+			res = append(res, richText("\n"))
+		}
+		res = append(res, richText("\n"))
+	}
+	return res
+}
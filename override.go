@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// overrideDest는 소스 src의 대상 디렉토리를 패턴과 관계없이 destDir로 바꾸고 대상 경로들을 다시 검사한다.
+// 납품물 중 몇개만 다른 곳에 두어야 할 때 입력을 고치고 다시 분석하지 않아도 되게 한다.
+// 시퀀스에 속한 소스라면 시퀀스 전체를 함께 옮긴다.
+func (p *Program) overrideDest(src, destDir string) error {
+	destDir = strings.TrimSpace(destDir)
+	if !isAbsPath(destDir) {
+		return fmt.Errorf("destination should be an absolute path: %s", destDir)
+	}
+	destDir = filepath.Clean(destDir)
+	if err := checkDestPath(destDir, windowsRules(destDir), 0); err != nil {
+		return fmt.Errorf("%w: %s", err, destDir)
+	}
+	if _, checked := p.DestDirExists[destDir]; !checked && !p.Offline {
+		_, err := safeStat(destDir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %s", err, destDir)
+		}
+		p.DestDirExists[destDir] = err == nil
+	}
+	paths := []string{src}
+	if seq := p.SrcSeq[src]; seq != nil {
+		paths = seq.Paths
+	}
+	for _, path := range paths {
+		old, ok := p.DestDir[path]
+		if !ok || old == destDir {
+			continue
+		}
+		srcs := make([]string, 0, len(p.DestDirSrcs[old]))
+		for _, s := range p.DestDirSrcs[old] {
+			if s != path {
+				srcs = append(srcs, s)
+			}
+		}
+		if len(srcs) == 0 {
+			delete(p.DestDirSrcs, old)
+		} else {
+			p.DestDirSrcs[old] = srcs
+		}
+		p.DestDirSrcs[destDir] = append(p.DestDirSrcs[destDir], path)
+		p.DestDir[path] = destDir
+		p.DestOverridden[path] = true
+		if !p.Offline && !p.SrcIsDir[path] {
+			p.SrcLinked[path] = isSameFile(path, filepath.Join(destDir, filepath.Base(path)))
+		}
+		// 디렉토리 안의 파일들이 새 대상 경로에 이미 있는지는 다시 세지 않으면 알 수 없다.
+		delete(p.SrcDestExisting, path)
+	}
+	return p.checkDestinations()
+}
+
+// editDest는 소스 src의 대상 디렉토리를 고치는 에디터를 보여준다.
+func (ui *UI) editDest(src string) {
+	ui.OverrideSrc = src
+	dest := ui.Program.DestDir[src]
+	ui.OverrideEditor.SetText(dest)
+	n := utf8.RuneCountInString(dest)
+	ui.OverrideEditor.SetCaret(n, n)
+}
+
+// applyOverride는 에디터에서 고친 대상 디렉토리를 소스에 적용하고 분석 화면을 새로 만든다.
+func (ui *UI) applyOverride() {
+	err := ui.Program.overrideDest(ui.OverrideSrc, ui.OverrideEditor.Text())
+	if err != nil {
+		ui.Notifier.SetText(explainError(err))
+		ui.NotifyIsError = true
+		return
+	}
+	ui.Notifier.SetText(tr("destination changed for ") + filepath.Base(ui.OverrideSrc))
+	ui.NotifyIsError = false
+	ui.OverrideSrc = ""
+	ui.Result = analyzeInput(ui.Program, ui.View)
+}
+
+// layoutOverride는 소스 하나의 대상 디렉토리를 고치는 줄을 그린다.
+func (ui *UI) layoutOverride(gtx C) D {
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			return material.Body1(ui.Theme, tr("destination for ")+filepath.Base(ui.OverrideSrc)+" ").Layout(gtx)
+		}),
+		layout.Flexed(1, func(gtx C) D {
+			return widget.Border{Color: ui.BorderColor, CornerRadius: unit.Dp(1), Width: colors.BorderWidth}.Layout(gtx, func(gtx C) D {
+				return layout.UniformInset(unit.Dp(2)).Layout(gtx, material.Editor(ui.Theme, ui.OverrideEditor, tr("destination folder")).Layout)
+			})
+		}),
+		layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout),
+		layout.Rigid(material.Button(ui.Theme, ui.ApplyDestButton, tr("Apply")).Layout),
+		layout.Rigid(layout.Spacer{Width: unit.Dp(2)}.Layout),
+		layout.Rigid(material.Button(ui.Theme, ui.CloseDestButton, tr("Close")).Layout),
+	)
+}
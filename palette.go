@@ -0,0 +1,71 @@
+package main
+
+import (
+	"image/color"
+
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+)
+
+// palette는 화면에 쓰는 색들과 테두리 두께이다.
+type palette struct {
+	Text    color.NRGBA
+	Path    color.NRGBA
+	Hint    color.NRGBA
+	Error   color.NRGBA
+	Warning color.NRGBA
+	OK      color.NRGBA
+	// Disabled는 수정할 수 없는 에디터의 글자 색이다.
+	Disabled    color.NRGBA
+	Border      color.NRGBA
+	BorderWidth unit.Dp
+	// Scrim은 확인 창이나 진행 상황 아래의 화면을 덮는 색이다.
+	Scrim color.NRGBA
+	// Theme은 버튼과 체크박스 등 Gio 위젯의 색으로, 비어있으면 Gio의 기본 색을 쓴다.
+	Theme *material.Palette
+}
+
+var defaultPalette = palette{
+	Text:        color.NRGBA{A: 255},
+	Path:        color.NRGBA{A: 255, B: 170},
+	Hint:        color.NRGBA{R: 128, G: 128, B: 128, A: 255},
+	Error:       color.NRGBA{R: 192, G: 32, B: 32, A: 255},
+	Warning:     color.NRGBA{R: 192, G: 112, A: 255},
+	OK:          color.NRGBA{G: 128, A: 255},
+	Disabled:    color.NRGBA{R: 160, G: 160, B: 160, A: 255},
+	Border:      color.NRGBA{R: 128, G: 128, B: 128, A: 255},
+	BorderWidth: unit.Dp(1),
+	Scrim:       color.NRGBA{A: 96},
+}
+
+// highContrastPalette는 리뷰실의 화면이나 시력이 약한 사용자를 위해 대비를 높인 색들이다.
+// 흰 바탕에 검은 글자와 두꺼운 검은 테두리를 쓰고, 에러는 더 진한 빨강으로 보여준다.
+var highContrastPalette = palette{
+	Text:        color.NRGBA{A: 255},
+	Path:        color.NRGBA{B: 160, A: 255},
+	Hint:        color.NRGBA{R: 64, G: 64, B: 64, A: 255},
+	Error:       color.NRGBA{R: 200, A: 255},
+	Warning:     color.NRGBA{R: 140, G: 70, A: 255},
+	OK:          color.NRGBA{G: 100, A: 255},
+	Disabled:    color.NRGBA{R: 96, G: 96, B: 96, A: 255},
+	Border:      color.NRGBA{A: 255},
+	BorderWidth: unit.Dp(2),
+	Scrim:       color.NRGBA{A: 160},
+	Theme: &material.Palette{
+		Fg:         color.NRGBA{A: 255},
+		Bg:         color.NRGBA{R: 255, G: 255, B: 255, A: 255},
+		ContrastBg: color.NRGBA{A: 255},
+		ContrastFg: color.NRGBA{R: 255, G: 255, B: 255, A: 255},
+	},
+}
+
+// colors는 지금 쓰는 색들이다. 설정에 따라 main에서 정한다.
+var colors = defaultPalette
+
+// applyPalette는 pal을 지금 쓰는 색으로 정하고 테마 th에도 적용한다.
+func applyPalette(th *material.Theme, pal palette) {
+	colors = pal
+	if pal.Theme != nil {
+		th.Palette = *pal.Theme
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// expandVar는 대상 경로 패턴의 ${...} 안의 표현식 expr을 env를 이용해 값으로 바꾼다.
+//
+// 키 이름 뒤에 콜론(:)으로 구분된 변환을 붙여 값을 바꿀 수 있다. 변환은 왼쪽부터 차례로 적용된다.
+//
+//	${SHOW:lower}    소문자로
+//	${SHOT:upper}    대문자로
+//	${VER:pad3}      끝의 숫자를 3자리가 되도록 0으로 채움 (v7 -> v007)
+//	${SCENE:0:3}     0번째 글자부터 3글자 (길이를 생략하면 끝까지)
+//	${SCENE: -3}     끝에서 3글자 (기본값 문법과 구분하기 위해 음수 앞에는 공백이 필요하다)
+//
+// 키가 없거나 값이 비어있을 때 쓸 기본값을 :- 뒤에 지정할 수 있다.
+// 기본값에는 변환이 적용되지 않는다.
+//
+//	${PART:-main}
+//	${PART:lower:-main}
+//
+// 키 뒤에 +N 또는 -N을 붙이면 값 끝의 숫자에 N을 더하거나 뺀다. 숫자의 자릿수는 유지된다.
+// KEY_NEXT는 KEY+1과 같다. 다음 버전 폴더로 받아들일 때 쓸 수 있다.
+//
+//	${VER+1}         v007 -> v008
+//	${VER_NEXT}      v007 -> v008
+func expandVar(expr string, env map[string]string) (string, error) {
+	expr, fallback, hasFallback := strings.Cut(expr, ":-")
+	name, rest, hasOps := strings.Cut(expr, ":")
+	v, ok := env[name]
+	if !ok {
+		if base, delta, isArith := splitArith(name); isArith {
+			if bv, bok := env[base]; bok && bv != "" {
+				var err error
+				v, err = addNumber(bv, delta)
+				if err != nil {
+					return "", fmt.Errorf("%v: ${%s}", err, name)
+				}
+				ok = true
+			}
+		}
+	}
+	if !ok || v == "" {
+		if hasFallback {
+			return fallback, nil
+		}
+		if !ok {
+			return "", fmt.Errorf("unknown environ variable in dest: $%s", name)
+		}
+	}
+	if !hasOps {
+		return v, nil
+	}
+	ops := strings.Split(rest, ":")
+	for i := 0; i < len(ops); i++ {
+		op := strings.TrimSpace(ops[i])
+		switch {
+		case op == "lower":
+			v = strings.ToLower(v)
+		case op == "upper":
+			v = strings.ToUpper(v)
+		case strings.HasPrefix(op, "pad"):
+			n, err := strconv.Atoi(op[len("pad"):])
+			if err != nil || n <= 0 {
+				return "", fmt.Errorf("invalid transform in dest: %s", op)
+			}
+			v, err = padNumber(v, n)
+			if err != nil {
+				return "", fmt.Errorf("%v: ${%s}", err, expr)
+			}
+		default:
+			off, err := strconv.Atoi(op)
+			if err != nil {
+				return "", fmt.Errorf("invalid transform in dest: %s", op)
+			}
+			length := -1
+			if i+1 < len(ops) {
+				if l, err := strconv.Atoi(strings.TrimSpace(ops[i+1])); err == nil {
+					length = l
+					i++
+				}
+			}
+			v = substring(v, off, length)
+		}
+	}
+	return v, nil
+}
+
+// splitArith는 VER+1, VER-1, VER_NEXT 같은 키 이름을 원래 키와 더할 수로 나눈다.
+func splitArith(name string) (string, int, bool) {
+	if base, ok := strings.CutSuffix(name, "_NEXT"); ok && base != "" {
+		return base, 1, true
+	}
+	i := strings.LastIndexAny(name, "+-")
+	if i <= 0 {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(name[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	if name[i] == '-' {
+		n = -n
+	}
+	return name[:i], n, true
+}
+
+// addNumber는 v 끝의 숫자에 delta를 더한다. 원래 숫자의 자릿수보다 짧아지지 않도록 0을 채운다.
+func addNumber(v string, delta int) (string, error) {
+	i := len(v)
+	for i > 0 && v[i-1] >= '0' && v[i-1] <= '9' {
+		i--
+	}
+	if i == len(v) {
+		return "", fmt.Errorf("no number in %q", v)
+	}
+	n, err := strconv.Atoi(v[i:])
+	if err != nil {
+		return "", err
+	}
+	n += delta
+	if n < 0 {
+		return "", fmt.Errorf("negative number from %q", v)
+	}
+	digits := strconv.Itoa(n)
+	if len(digits) < len(v)-i {
+		digits = strings.Repeat("0", len(v)-i-len(digits)) + digits
+	}
+	return v[:i] + digits, nil
+}
+
+// padNumber는 v 끝의 숫자를 n자리가 되도록 앞에 0을 채운다.
+func padNumber(v string, n int) (string, error) {
+	i := len(v)
+	for i > 0 && v[i-1] >= '0' && v[i-1] <= '9' {
+		i--
+	}
+	if i == len(v) {
+		return "", fmt.Errorf("pad: no number in %q", v)
+	}
+	digits := v[i:]
+	if len(digits) < n {
+		digits = strings.Repeat("0", n-len(digits)) + digits
+	}
+	return v[:i] + digits, nil
+}
+
+// substring은 v의 off번째 글자부터 length 글자를 반환한다.
+// off가 음수면 끝에서부터 센다. length가 음수면 끝까지 반환한다.
+// 범위를 벗어나는 부분은 잘린다.
+func substring(v string, off, length int) string {
+	n := utf8.RuneCountInString(v)
+	if off < 0 {
+		off += n
+		if off < 0 {
+			off = 0
+		}
+	}
+	if off > n {
+		off = n
+	}
+	end := n
+	if length >= 0 && off+length < n {
+		end = off + length
+	}
+	return string([]rune(v)[off:end])
+}
@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func TestExpandVar(t *testing.T) {
+	env := map[string]string{
+		"SHOW":  "Alpha",
+		"SHOT":  "sh020",
+		"VER":   "v007",
+		"SCENE": "S01_0010",
+		"PART":  "",
+		"NAME":  "한글이름",
+	}
+	cases := []struct {
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{"SHOW", "Alpha", false},
+		{"SHOW:lower", "alpha", false},
+		{"SHOT:upper", "SH020", false},
+		{"VER:pad4", "v0007", false},
+		{"VER:pad2", "v007", false},
+		{"SCENE:0:3", "S01", false},
+		{"SCENE: -4", "0010", false},
+		{"SCENE:4", "0010", false},
+		{"NAME:0:2", "한글", false},
+		{"PART:-main", "main", false},
+		{"MISSING:-main", "main", false},
+		{"SHOW:lower:-main", "alpha", false},
+		{"PART", "", false},
+		{"VER+1", "v008", false},
+		{"VER-7", "v000", false},
+		{"VER_NEXT", "v008", false},
+		{"VER-8", "", true},
+		{"SHOW+1", "", true},
+		{"MISSING", "", true},
+		{"SHOW:pad0", "", true},
+		{"SHOW:title", "", true},
+	}
+	for _, c := range cases {
+		got, err := expandVar(c.expr, env)
+		if (err != nil) != c.wantErr {
+			t.Errorf("expandVar(%q) error = %v, want error %v", c.expr, err, c.wantErr)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("expandVar(%q) = %q, want %q", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestAddNumber(t *testing.T) {
+	cases := []struct {
+		v     string
+		delta int
+		want  string
+	}{
+		{"v009", 1, "v010"},
+		{"v999", 1, "v1000"},
+		{"12", -2, "10"},
+		{"sh010", 10, "sh020"},
+	}
+	for _, c := range cases {
+		got, err := addNumber(c.v, c.delta)
+		if err != nil || got != c.want {
+			t.Errorf("addNumber(%q, %d) = %q, %v, want %q", c.v, c.delta, got, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// planSchemaID는 plan.schema.json의 $id이다.
+const planSchemaID = "https://github.com/kzmdstu/takein/plan.schema.json"
+
+// planVersion은 현재 계획 파일 형식의 버전이다.
+// 형식이 호환되지 않게 바뀔 때만 올린다.
+const planVersion = 1
+
+// Plan은 분석 결과로 만들어지는 복사 계획이다.
+// 다른 파이프라인 도구가 읽을 수 있도록 그 형식을 plan.schema.json에 정의한다.
+type Plan struct {
+	Schema      string      `json:"$schema,omitempty"`
+	Version     int         `json:"version"`
+	Created     time.Time   `json:"created"`
+	User        string      `json:"user,omitempty"`
+	Host        string      `json:"host,omitempty"`
+	Method      string      `json:"method"`
+	DestPattern string      `json:"dest_pattern"`
+	Offline     bool        `json:"offline,omitempty"`
+	Entries     []PlanEntry `json:"entries"`
+	NotExists   []string    `json:"not_exists,omitempty"`
+	Invalids    []string    `json:"invalids,omitempty"`
+}
+
+// PlanEntry는 계획에 포함된 소스 하나와 그 대상 디렉토리이다.
+type PlanEntry struct {
+	Src           string `json:"src"`
+	IsDir         bool   `json:"is_dir"`
+	DestDir       string `json:"dest_dir"`
+	DestDirExists bool   `json:"dest_dir_exists"`
+}
+
+// planEntryRequired는 plan.schema.json에서 계획의 각 항목에 반드시 있어야 하는 필드들이다.
+var planEntryRequired = []string{"src", "is_dir", "dest_dir", "dest_dir_exists"}
+
+// Plan은 분석된 프로그램 정보로 복사 계획을 만든다.
+func (p *Program) Plan() *Plan {
+	host, _ := os.Hostname()
+	method := p.Method
+	if method == "" {
+		method = "link"
+	}
+	plan := &Plan{
+		Schema:      planSchemaID,
+		Version:     planVersion,
+		Created:     time.Now(),
+		User:        currentUser(),
+		Host:        host,
+		Method:      method,
+		DestPattern: p.DestPattern,
+		Offline:     p.Offline,
+		Entries:     make([]PlanEntry, 0, len(p.Srcs)),
+		NotExists:   sortedNatural(p.NotExists),
+		Invalids:    sortedNatural(p.Invalids),
+	}
+	for _, src := range p.Srcs {
+		destDir, ok := p.DestDir[src]
+		if !ok || p.SrcSkipped[src] {
+			continue
+		}
+		plan.Entries = append(plan.Entries, PlanEntry{
+			Src:           src,
+			IsDir:         p.SrcIsDir[src],
+			DestDir:       destDir,
+			DestDirExists: p.DestDirExists[destDir],
+		})
+	}
+	return plan
+}
+
+// savePlan은 복사 계획을 설정 디렉토리 아래 plans 디렉토리에 저장하고 그 경로를 반환한다.
+func savePlan(plan *Plan) (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "plans")
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, "plan-"+plan.Created.Format("20060102-150405")+".json")
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(file, append(data, '\n'), 0644)
+	if err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// validatePlan은 data가 plan.schema.json을 따르는 계획인지 검사하고 문제점들을 반환한다.
+func validatePlan(data []byte) []error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	plan := new(Plan)
+	err := dec.Decode(plan)
+	if err != nil {
+		return []error{err}
+	}
+	errs := make([]error, 0)
+	if plan.Version == 0 {
+		errs = append(errs, fmt.Errorf("version: required"))
+	} else if plan.Version != planVersion {
+		errs = append(errs, fmt.Errorf("version: unsupported version %d", plan.Version))
+	}
+	if plan.Created.IsZero() {
+		errs = append(errs, fmt.Errorf("created: required"))
+	}
+	if plan.Method != "link" && plan.Method != "copy" && plan.Method != "catalog" {
+		errs = append(errs, fmt.Errorf("method: must be link, copy or catalog: %q", plan.Method))
+	}
+	if plan.DestPattern == "" {
+		errs = append(errs, fmt.Errorf("dest_pattern: required"))
+	}
+	if plan.Entries == nil {
+		errs = append(errs, fmt.Errorf("entries: required"))
+	}
+	// false는 bool 필드의 빈 값과 구별되지 않으므로 필수 필드가 적혀있는지는 따로 확인한다.
+	var raw struct {
+		Entries []map[string]json.RawMessage `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return append(errs, err)
+	}
+	for i, ent := range raw.Entries {
+		for _, field := range planEntryRequired {
+			if _, ok := ent[field]; !ok {
+				errs = append(errs, fmt.Errorf("entries[%d].%s: required", i, field))
+			}
+		}
+	}
+	for i, ent := range plan.Entries {
+		if !isAbsPath(ent.Src) {
+			errs = append(errs, fmt.Errorf("entries[%d].src: not an absolute path: %q", i, ent.Src))
+		}
+		if !isAbsPath(ent.DestDir) {
+			errs = append(errs, fmt.Errorf("entries[%d].dest_dir: not an absolute path: %q", i, ent.DestDir))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// checkWritable은 각 대상 경로(또는 아직 없다면 가장 가까운 존재하는 부모 디렉토리)에
+// 파일을 만들 수 있는지 검사하고, 만들 수 없는 대상 경로를 p.NotWritable에 기록한다.
+// 복사 도중에 권한 문제로 멈추지 않도록 분석할 때 미리 알리기 위함이다.
+func (p *Program) checkWritable() error {
+	p.NotWritable = make([]string, 0)
+	if p.Method == "catalog" {
+		// 목록만 만들 때는 대상 경로에 쓰지 않는다.
+		return nil
+	}
+	problems := make(map[string]string)
+	for destDir := range p.DestDirSrcs {
+		dir, err := existingAncestor(destDir)
+		if err != nil {
+			return err
+		}
+		prob, checked := problems[dir]
+		if !checked {
+			prob = writeProblem(dir)
+			problems[dir] = prob
+		}
+		if prob == "" {
+			continue
+		}
+		if dir != filepath.Clean(destDir) {
+			prob = "can't create in " + dir + ": " + prob
+		}
+		p.NotWritable = append(p.NotWritable, destDir+" ("+prob+")")
+	}
+	sortNatural(p.NotWritable)
+	return nil
+}
+
+// writeProblem은 디렉토리 dir 안에 파일을 만들어 보고, 만들 수 없다면 그 이유를 반환한다.
+// 만든 파일은 바로 지운다.
+func writeProblem(dir string) string {
+	fi, err := safeStat(dir)
+	if err != nil {
+		return err.Error()
+	}
+	if !fi.IsDir() {
+		return "not a directory"
+	}
+	f, err := withTimeout(dir, func() (*os.File, error) {
+		return os.CreateTemp(dir, ".takein-write-*")
+	})
+	if err != nil {
+		var stale *staleMountError
+		if errors.As(err, &stale) {
+			return err.Error()
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return "permission denied"
+		}
+		var perr *fs.PathError
+		if errors.As(err, &perr) {
+			return perr.Err.Error()
+		}
+		return err.Error()
+	}
+	name := f.Name()
+	f.Close()
+	if err := os.Remove(name); err != nil {
+		return fmt.Sprintf("couldn't remove test file: %v", err)
+	}
+	return ""
+}
+
+// isSameFile은 두 경로가 같은 파일(같은 inode 또는 하드 링크)을 가리키는지 확인한다.
+// 어느 한쪽이라도 없다면 거짓이다.
+func isSameFile(a, b string) bool {
+	afi, err := safeStat(a)
+	if err != nil {
+		return false
+	}
+	bfi, err := safeStat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(afi, bfi)
+}
+
+// realPath는 path의 심볼릭 링크를 따라간 실제 경로이다.
+// path가 아직 없다면 존재하는 가장 가까운 부모 디렉토리까지만 따라간다.
+func realPath(path string) string {
+	path = filepath.Clean(path)
+	rest := ""
+	for {
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			return filepath.Join(real, rest)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(path, rest)
+		}
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
+
+// isWithin은 path가 dir 자신이거나 dir 안의 경로인지 확인한다.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// checkRecursion은 대상 경로가 디렉토리 소스 안에 있거나 디렉토리 소스가 복사될 경로가 소스 자신인 경우를 찾아
+// p.Recursions에 기록한다. 이런 작업은 복사한 파일을 다시 복사하거나 소스를 덮어쓸 수 있으므로 실행할 수 없다.
+func (p *Program) checkRecursion() {
+	p.Recursions = make([]string, 0)
+	destDirs := make([]string, 0, len(p.DestDirSrcs))
+	for dd := range p.DestDirSrcs {
+		destDirs = append(destDirs, dd)
+	}
+	sortNatural(destDirs)
+	for _, src := range p.Srcs {
+		if !p.SrcIsDir[src] {
+			continue
+		}
+		realSrc := realPath(src)
+		for _, dd := range destDirs {
+			if isWithin(realPath(dd), realSrc) {
+				p.Recursions = append(p.Recursions, "destination "+dd+" is inside source "+src)
+			}
+		}
+		// 소스가 복사될 경로가 소스 자신이거나 그 부모라면 소스를 덮어쓴다.
+		if dd, ok := p.DestDir[src]; ok {
+			target := filepath.Join(dd, filepath.Base(src))
+			if isWithin(realSrc, realPath(target)) {
+				p.Recursions = append(p.Recursions, "source "+src+" would be copied onto itself at "+target)
+			}
+		}
+	}
+}
+
+// checkCaseCollisions는 대소문자만 다른 대상 경로들을 찾아 p.CaseCollisions에 기록한다.
+// macOS나 SMB 공유처럼 대소문자를 구분하지 않는 파일시스템에서는 이런 경로들이 모르는 사이에 합쳐진다.
+// 대상 디렉토리끼리, 같은 디렉토리에 복사될 소스 이름끼리, 그리고 checkDisk가 참이면
+// 아직 없는 대상 디렉토리와 이미 있는 디렉토리를 비교한다.
+func (p *Program) checkCaseCollisions(checkDisk bool) {
+	p.CaseCollisions = make([]string, 0)
+	destDirs := make([]string, 0, len(p.DestDirSrcs))
+	for dd := range p.DestDirSrcs {
+		destDirs = append(destDirs, dd)
+	}
+	sortNatural(destDirs)
+	seen := make(map[string]string)
+	for _, dd := range destDirs {
+		key := strings.ToLower(filepath.Clean(dd))
+		if prev, ok := seen[key]; ok && prev != dd {
+			p.CaseCollisions = append(p.CaseCollisions, prev+" and "+dd)
+			continue
+		}
+		seen[key] = dd
+		names := make(map[string]string)
+		for _, src := range p.DestDirSrcs[dd] {
+			name := filepath.Base(src)
+			key := strings.ToLower(name)
+			if prev, ok := names[key]; ok && prev != name {
+				p.CaseCollisions = append(p.CaseCollisions, filepath.Join(dd, prev)+" and "+name)
+				continue
+			}
+			names[key] = name
+		}
+	}
+	if !checkDisk {
+		return
+	}
+	listed := make(map[string][]string)
+	for _, dd := range destDirs {
+		if p.DestDirExists[dd] {
+			continue
+		}
+		dir, err := existingAncestor(dd)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(dir, filepath.Clean(dd))
+		if err != nil || rel == "." {
+			continue
+		}
+		next := strings.Split(rel, string(filepath.Separator))[0]
+		ents, ok := listed[dir]
+		if !ok {
+			des, _ := os.ReadDir(dir)
+			for _, de := range des {
+				ents = append(ents, de.Name())
+			}
+			listed[dir] = ents
+		}
+		for _, name := range ents {
+			if name != next && strings.EqualFold(name, next) {
+				p.CaseCollisions = append(p.CaseCollisions, dd+" and existing "+filepath.Join(dir, name))
+				break
+			}
+		}
+	}
+}
+
+// checkCrossDevice는 복사 방법이 link일 때 소스와 대상 경로가 서로 다른 파일시스템에 있는지 검사하고,
+// 그런 대상 경로를 p.CrossDevice에 기록한다. 하드 링크는 파일시스템을 넘을 수 없으므로
+// 실행 도중에 실패하는 대신 분석할 때 미리 알린다.
+func (p *Program) checkCrossDevice() error {
+	p.CrossDevice = make([]string, 0)
+	if p.Method != "link" {
+		return nil
+	}
+	devs := make(map[string]string)
+	device := func(path string) (string, error) {
+		if id, ok := devs[path]; ok {
+			return id, nil
+		}
+		id, _, err := diskFree(path)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", err, path)
+		}
+		devs[path] = id
+		return id, nil
+	}
+	for destDir, srcs := range p.DestDirSrcs {
+		dir, err := existingAncestor(destDir)
+		if err != nil {
+			return err
+		}
+		destDev, err := device(dir)
+		if err != nil {
+			return err
+		}
+		others := make([]string, 0)
+		for _, src := range srcs {
+			// 파일 소스는 디렉토리별로 한번만 확인한다.
+			at := src
+			if !p.SrcIsDir[src] {
+				at = filepath.Dir(src)
+			}
+			srcDev, err := device(at)
+			if err != nil {
+				return err
+			}
+			if srcDev != destDev {
+				others = append(others, src)
+			}
+		}
+		if len(others) == 0 {
+			continue
+		}
+		sortNatural(others)
+		line := destDir + " is on a different filesystem from " + others[0]
+		if len(others) > 1 {
+			line += fmt.Sprintf(" and %d more", len(others)-1)
+		}
+		p.CrossDevice = append(p.CrossDevice, line)
+	}
+	sortNatural(p.CrossDevice)
+	return nil
+}
+
+// checkDestFiles는 파일 소스들이 복사될 대상 파일이 이미 있는지 확인해 p.SrcDestExists에 기록한다.
+// 이미 있는 파일은 복사할 때 건너뛰므로 미리 알린다. 이미 같은 파일로 링크된 소스는 제외한다.
+// 디렉토리 소스 안의 파일들은 디렉토리를 셀 때 확인한다.
+func (p *Program) checkDestFiles() error {
+	p.SrcDestExists = make(map[string]bool)
+	for destDir, srcs := range p.DestDirSrcs {
+		if !p.DestDirExists[destDir] {
+			continue
+		}
+		files := make([]srcFile, 0, len(srcs))
+		for _, src := range srcs {
+			if !p.SrcIsDir[src] {
+				files = append(files, srcFile{Path: src, Rel: filepath.Base(src), Real: src})
+			}
+		}
+		dests, err := p.destPaths(destDir, files)
+		if err != nil {
+			return err
+		}
+		for i, f := range files {
+			_, err := safeLstat(dests[i])
+			if err == nil {
+				p.SrcDestExists[f.Path] = !p.SrcLinked[f.Path]
+				continue
+			}
+			if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("%w: %s", err, dests[i])
+			}
+		}
+	}
+	return nil
+}
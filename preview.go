@@ -0,0 +1,148 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+
+	"gioui.org/x/richtext"
+)
+
+// tokenKey는 경로 토큰 하나와 그 토큰에 배치된 키이다.
+type tokenKey struct {
+	Token string
+	Key   string
+	// Missing은 키에 배치할 토큰이 모자랐음을 뜻한다.
+	Missing bool
+	// Skipped는 ... 키가 건너뛴 토큰임을 뜻한다.
+	Skipped bool
+}
+
+// alignTokenKeys는 tokens에 keys를 parseEnvs와 같은 방식으로 배치한다.
+// parseEnvs와 달리 토큰과 키의 수가 맞지 않아도 가능한 만큼 배치해서
+// 어느 부분이 어긋났는지 볼 수 있게 한다.
+// 키를 받지 못한 토큰의 키는 비어있고, 값을 받지 못한 키는 빈 토큰과 함께 뒤에 붙는다.
+// ...이 건너뛴 토큰들은 키를 받지 못한 것이 아니므로 Skipped로 표시한다.
+func alignTokenKeys(tokens, keys []string) []tokenKey {
+	res := make([]tokenKey, len(tokens))
+	for i, t := range tokens {
+		res[i].Token = t
+	}
+	idx := -1
+	for i, k := range keys {
+		if k == "..." {
+			idx = i
+			break
+		}
+	}
+	left, right := keys, []string(nil)
+	if idx != -1 {
+		left, right = keys[:idx], keys[idx+1:]
+	}
+	missing := make([]string, 0)
+	for i, k := range left {
+		if i >= len(res) {
+			missing = append(missing, k)
+			continue
+		}
+		res[i].Key = k
+	}
+	for i := range right {
+		k := right[len(right)-1-i]
+		j := len(res) - 1 - i
+		if j < len(left) {
+			missing = append(missing, k)
+			continue
+		}
+		res[j].Key = k
+	}
+	if idx != -1 {
+		for i := len(left); i < len(tokens)-len(right); i++ {
+			res[i].Skipped = true
+		}
+	}
+	for _, k := range missing {
+		res = append(res, tokenKey{Key: k, Missing: true})
+	}
+	return res
+}
+
+// previewSpans는 각 입력 경로가 어떤 토큰으로 나뉘고 각 토큰이 어떤 키의 값이 되는지를 표로 보여준다.
+func previewSpans(p *Program, paths []string) []richtext.SpanStyle {
+	res := make([]richtext.SpanStyle, 0)
+	if len(paths) == 0 {
+		res = append(res, richText("no paths to preview"))
+		return res
+	}
+	for _, src := range paths {
+		res = append(res, richTitle(src))
+		res = append(res, richText("\n"))
+		if _, err := p.ParseEnvsFromSrc(src); err != nil {
+			res = append(res, richChanged(err.Error()))
+			res = append(res, richText("\n"))
+		}
+		if p.ParseMode == "regex" {
+			res = append(res, previewRegex("path", src, p.PathRegexp)...)
+			res = append(res, previewRegex("name", baseName(src), p.NameRegexp)...)
+		} else {
+			res = append(res, previewTokens("path", alignTokenKeys(splitTokens(src, p.PathSeps), p.PathKeys))...)
+			res = append(res, previewTokens("name", alignTokenKeys(splitTokens(baseName(src), p.NameSeps), p.NameKeys))...)
+		}
+		res = append(res, richText("\n"))
+	}
+	return res
+}
+
+// previewTokens는 토큰과 키의 배치를 한 줄에 하나씩 보여준다.
+func previewTokens(part string, tks []tokenKey) []richtext.SpanStyle {
+	res := make([]richtext.SpanStyle, 0)
+	for i, tk := range tks {
+		if tk.Missing {
+			res = append(res, richText("  "+part+" #"+strconv.Itoa(i+1)+"  "))
+			res = append(res, richChanged("(no value)"))
+			res = append(res, richText("  →  "+tk.Key+"\n"))
+			continue
+		}
+		res = append(res, richText("  "+part+" #"+strconv.Itoa(i+1)+"  "+strconv.Quote(tk.Token)+"  →  "))
+		switch {
+		case tk.Skipped:
+			res = append(res, richDim("... (skipped)"))
+		case tk.Key == "":
+			res = append(res, richChanged("(no key)"))
+		case tk.Key == "_":
+			res = append(res, richDim("_ (skipped)"))
+		default:
+			res = append(res, richText(tk.Key))
+		}
+		res = append(res, richText("\n"))
+	}
+	return res
+}
+
+// richDim은 분석에 쓰이지 않는 부분을 흐리게 보여준다.
+func richDim(text string) richtext.SpanStyle {
+	s := richText(text)
+	s.Color = colors.Hint
+	return s
+}
+
+// previewRegex는 정규식의 각 이름 있는 그룹이 찾은 값을 한 줄에 하나씩 보여준다.
+func previewRegex(part, src string, re *regexp.Regexp) []richtext.SpanStyle {
+	res := make([]richtext.SpanStyle, 0)
+	if re == nil {
+		return res
+	}
+	m := re.FindStringSubmatch(src)
+	if m == nil {
+		res = append(res, richText("  "+part+"  "))
+		res = append(res, richChanged("(not matched)"))
+		res = append(res, richText("\n"))
+		return res
+	}
+	for i, name := range re.SubexpNames() {
+		if name == "" {
+			continue
+		}
+		res = append(res, richText("  "+part+" "+name+"  →  "+strconv.Quote(m[i])+"\n"))
+	}
+	return res
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// probeTimeout은 파일 하나를 ffprobe로 분석할 때 기다리는 최대 시간이다.
+const probeTimeout = 30 * time.Second
+
+// probeBudget은 분석 한번에서 모든 파일을 ffprobe로 분석하는 데 쓰는 최대 시간이다.
+// 분석하는 동안에는 창이 멈추므로 파일이 많거나 저장소가 느려도 이 시간 안에 끝낸다.
+// probeWorkers는 동시에 실행하는 ffprobe의 수이다.
+const (
+	probeBudget  = 20 * time.Second
+	probeWorkers = 4
+)
+
+// errProbeBudget은 분석 한번에 쓸 수 있는 시간이 지나 파일을 분석하지 못했음을 뜻한다.
+var errProbeBudget = errors.New("not probed, probing the batch took too long")
+
+// probeResult는 파일 하나를 ffprobe로 분석한 결과이다.
+type probeResult struct {
+	Meta map[string]string
+	Err  error
+}
+
+// probeAll은 paths의 파일들을 동시에 probeWorkers개씩 ffprobe로 분석한다.
+// probeBudget이 지나면 나머지 파일들은 분석하지 않고 errProbeBudget을 결과로 남긴다.
+func probeAll(ffprobe string, paths []string) map[string]probeResult {
+	ctx, cancel := context.WithTimeout(context.Background(), probeBudget)
+	defer cancel()
+	res := make(map[string]probeResult, len(paths))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+	for range min(probeWorkers, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				meta, err := probeMedia(ctx, ffprobe, path)
+				mu.Lock()
+				res[path] = probeResult{Meta: meta, Err: err}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, path := range paths {
+		jobs <- path
+	}
+	close(jobs)
+	wg.Wait()
+	return res
+}
+
+// probeExts는 ffprobe로 분석할 미디어 파일의 확장자들이다.
+var probeExts = map[string]bool{
+	"mov": true, "mp4": true, "m4v": true, "mxf": true, "mkv": true,
+	"avi": true, "mts": true, "webm": true, "wav": true,
+}
+
+// isProbeMedia는 path가 ffprobe로 분석할 미디어 파일인지 확인한다.
+func isProbeMedia(path string) bool {
+	name := baseName(path)
+	i := strings.LastIndex(name, ".")
+	if i <= 0 {
+		return false
+	}
+	return probeExts[strings.ToLower(name[i+1:])]
+}
+
+// probeMedia는 ffprobe로 미디어 파일의 첫 비디오 스트림을 분석해 대상 경로 패턴에 쓸 값들을 만든다.
+//
+//	RES    1920x1080
+//	FPS    23.976
+//	CODEC  prores
+//
+// 비디오 스트림이 없다면 빈 맵을 반환한다. parent가 끝나면 분석을 멈추고 errProbeBudget을 반환한다.
+func probeMedia(parent context.Context, ffprobe, path string) (map[string]string, error) {
+	if ffprobe == "" {
+		ffprobe = "ffprobe"
+	}
+	if parent.Err() != nil {
+		return nil, errProbeBudget
+	}
+	ctx, cancel := context.WithTimeout(parent, probeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, ffprobe,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=codec_name,width,height,avg_frame_rate,r_frame_rate",
+		"-of", "json",
+		path,
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("ffprobe not found: %s", ffprobe)
+		}
+		if parent.Err() != nil {
+			return nil, errProbeBudget
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("ffprobe timed out: %s", path)
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) != 0 {
+			return nil, fmt.Errorf("ffprobe: %s: %s", strings.TrimSpace(string(exitErr.Stderr)), path)
+		}
+		return nil, fmt.Errorf("%v: %s", err, path)
+	}
+	var res struct {
+		Streams []struct {
+			CodecName    string `json:"codec_name"`
+			Width        int    `json:"width"`
+			Height       int    `json:"height"`
+			AvgFrameRate string `json:"avg_frame_rate"`
+			RFrameRate   string `json:"r_frame_rate"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &res); err != nil {
+		return nil, fmt.Errorf("ffprobe: %v: %s", err, path)
+	}
+	meta := make(map[string]string)
+	if len(res.Streams) == 0 {
+		return meta, nil
+	}
+	st := res.Streams[0]
+	if st.Width > 0 && st.Height > 0 {
+		meta["RES"] = strconv.Itoa(st.Width) + "x" + strconv.Itoa(st.Height)
+	}
+	fps := frameRate(st.AvgFrameRate)
+	if fps == "" {
+		fps = frameRate(st.RFrameRate)
+	}
+	if fps != "" {
+		meta["FPS"] = fps
+	}
+	if st.CodecName != "" {
+		meta["CODEC"] = st.CodecName
+	}
+	return meta, nil
+}
+
+// frameRate는 ffprobe의 24000/1001 같은 분수 형식 프레임 레이트를 23.976 같은 숫자로 바꾼다.
+// 알 수 없는 값이라면 빈 문자열을 반환한다.
+func frameRate(rate string) string {
+	num, den, ok := strings.Cut(rate, "/")
+	if !ok {
+		den = "1"
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil || n <= 0 {
+		return ""
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(int(n/d*1000+0.5))/1000, 'f', -1, 64)
+}
+
+// describeMeta는 분석 결과에 보여줄 수 있게 메타데이터 값들을 한 줄로 만든다.
+// EXR 헤더의 값들은 ffprobe의 값들 뒤에 변수 이름과 함께 보여준다.
+func describeMeta(meta map[string]string) string {
+	parts := make([]string, 0, len(meta))
+	for _, k := range []string{"RES", "FPS", "CODEC", "SHOT_DATE"} {
+		if v, ok := meta[k]; ok {
+			switch k {
+			case "FPS":
+				v += " fps"
+			case "SHOT_DATE":
+				v = "shot " + v
+			}
+			parts = append(parts, v)
+		}
+	}
+	exrKeys := make([]string, 0)
+	for k := range meta {
+		if strings.HasPrefix(k, "EXR_") {
+			exrKeys = append(exrKeys, k)
+		}
+	}
+	sort.Strings(exrKeys)
+	for _, k := range exrKeys {
+		parts = append(parts, k+"="+strconv.Quote(meta[k]))
+	}
+	return strings.Join(parts, ", ")
+}
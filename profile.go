@@ -0,0 +1,197 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+)
+
+// Profile은 쇼나 업체마다 다른 키 분석 방법과 대상 경로 패턴을 묶어둔 설정이다.
+// 설정 파일의 [Profiles.이름] 아래에 적으며, 적지 않은 값은 설정 파일 맨 위의 값을 쓴다.
+type Profile struct {
+	// ParseMode는 Config.ParseMode와 같이 "split" 또는 "regex"이다.
+	ParseMode string
+	PathSepBy string
+	PathKeys  string
+	NameSepBy string
+	NameKeys  string
+	PathRegex string
+	NameRegex string
+	Dest      string
+	// FramePad는 Config.FramePad와 같이 시퀀스 프레임 번호를 채울 자릿수이다.
+	FramePad int
+	// LastDest는 이 프로필로 마지막으로 복사를 실행했을 때의 대상 경로 패턴이다.
+	// 다른 프로필의 패턴과 비교하지 않도록 프로필마다 따로 기록하며, 맨 위의 값으로 채우지 않는다.
+	LastDest string
+}
+
+// defaultProfile은 설정 파일 맨 위의 값들을 쓰는 기본 프로필의 이름이다.
+const defaultProfile = ""
+
+// profileNames는 고를 수 있는 프로필들의 이름으로, 기본 프로필이 맨 앞에 온다.
+func (cfg *Config) profileNames() []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		if name != defaultProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{defaultProfile}, names...)
+}
+
+// profile은 이름이 name인 프로필이다. 프로필에 적지 않은 값은 설정 파일 맨 위의 값으로 채운다.
+func (cfg *Config) profile(name string) Profile {
+	pr := Profile{
+		ParseMode: cfg.ParseMode,
+		PathSepBy: cfg.PathSepBy,
+		PathKeys:  cfg.PathKeys,
+		NameSepBy: cfg.NameSepBy,
+		NameKeys:  cfg.NameKeys,
+		PathRegex: cfg.PathRegex,
+		NameRegex: cfg.NameRegex,
+		Dest:      cfg.Dest,
+		FramePad:  cfg.FramePad,
+	}
+	p := cfg.Profiles[name]
+	if name == defaultProfile || p == nil {
+		return pr
+	}
+	fill := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	fill(&pr.ParseMode, p.ParseMode)
+	fill(&pr.PathSepBy, p.PathSepBy)
+	fill(&pr.PathKeys, p.PathKeys)
+	fill(&pr.NameSepBy, p.NameSepBy)
+	fill(&pr.NameKeys, p.NameKeys)
+	fill(&pr.PathRegex, p.PathRegex)
+	fill(&pr.NameRegex, p.NameRegex)
+	fill(&pr.Dest, p.Dest)
+	if p.FramePad > 0 {
+		pr.FramePad = p.FramePad
+	}
+	return pr
+}
+
+// setProfile은 이름이 name인 프로필을 pr로 바꾼다. 기본 프로필이라면 설정 파일 맨 위의 값들을 바꾼다.
+func (cfg *Config) setProfile(name string, pr Profile) {
+	if name != defaultProfile {
+		if cfg.Profiles == nil {
+			cfg.Profiles = make(map[string]*Profile)
+		}
+		if old := cfg.Profiles[name]; old != nil {
+			pr.LastDest = old.LastDest
+		}
+		cfg.Profiles[name] = &pr
+		return
+	}
+	cfg.ParseMode = pr.ParseMode
+	cfg.PathSepBy = pr.PathSepBy
+	cfg.PathKeys = pr.PathKeys
+	cfg.NameSepBy = pr.NameSepBy
+	cfg.NameKeys = pr.NameKeys
+	cfg.PathRegex = pr.PathRegex
+	cfg.NameRegex = pr.NameRegex
+	cfg.Dest = pr.Dest
+	cfg.FramePad = pr.FramePad
+}
+
+// lastDest는 이름이 name인 프로필로 마지막으로 복사를 실행했을 때의 대상 경로 패턴이다.
+func (cfg *Config) lastDest(name string) string {
+	if name == defaultProfile {
+		return cfg.LastDest
+	}
+	if p := cfg.Profiles[name]; p != nil {
+		return p.LastDest
+	}
+	return ""
+}
+
+// setLastDest는 이름이 name인 프로필로 복사를 실행한 대상 경로 패턴 dest를 기록한다.
+func (cfg *Config) setLastDest(name, dest string) {
+	if name == defaultProfile {
+		cfg.LastDest = dest
+		return
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]*Profile)
+	}
+	p := cfg.Profiles[name]
+	if p == nil {
+		pr := cfg.profile(name)
+		p = &pr
+		cfg.Profiles[name] = p
+	}
+	p.LastDest = dest
+}
+
+// editorProfile은 에디터들에 적힌 프로필 설정이다.
+// 자릿수가 잘못 적혀있다면 지금 프로필의 값을 그대로 둔다.
+func (ui *UI) editorProfile() Profile {
+	pad, err := parseFramePad(ui.FramePadEditor.Text())
+	if err != nil {
+		pad = ui.Config.profile(ui.Config.Profile).FramePad
+	}
+	return Profile{
+		ParseMode: ui.ParseRadio.Value,
+		PathSepBy: ui.PathSeparatorEditor.Text(),
+		PathKeys:  ui.PathKeyEditor.Text(),
+		NameSepBy: ui.NameSeparatorEditor.Text(),
+		NameKeys:  ui.NameKeyEditor.Text(),
+		PathRegex: ui.PathRegexEditor.Text(),
+		NameRegex: ui.NameRegexEditor.Text(),
+		Dest:      ui.DestEditor.Text(),
+		FramePad:  pad,
+	}
+}
+
+// loadProfile은 프로필 pr의 설정을 에디터들에 채운다.
+func (ui *UI) loadProfile(pr Profile) {
+	ui.ParseRadio.Value = pr.ParseMode
+	ui.PathSeparatorEditor.SetText(pr.PathSepBy)
+	ui.PathKeyEditor.SetText(pr.PathKeys)
+	ui.NameSeparatorEditor.SetText(pr.NameSepBy)
+	ui.NameKeyEditor.SetText(pr.NameKeys)
+	ui.PathRegexEditor.SetText(pr.PathRegex)
+	ui.NameRegexEditor.SetText(pr.NameRegex)
+	ui.DestEditor.SetText(pr.Dest)
+	pad := ""
+	if pr.FramePad > 0 {
+		pad = strconv.Itoa(pr.FramePad)
+	}
+	ui.FramePadEditor.SetText(pad)
+}
+
+// switchProfile은 지금 프로필의 에디터 값들을 기억해두고, ProfileRadio에서 고른 프로필로 바꾼다.
+// 기억해둔 값은 그 프로필로 복사를 마칠 때 설정 파일에 저장된다.
+func (ui *UI) switchProfile() {
+	ui.Config.setProfile(ui.Config.Profile, ui.editorProfile())
+	ui.Config.Profile = ui.ProfileRadio.Value
+	ui.loadProfile(ui.Config.profile(ui.Config.Profile))
+}
+
+// profileChildren은 프로필이 정의되어 있을 때 프로필을 고르는 버튼들이다. 없다면 아무것도 그리지 않는다.
+func (ui *UI) profileChildren() []layout.FlexChild {
+	if len(ui.Config.Profiles) == 0 {
+		return nil
+	}
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx C) D { return material.Body1(ui.Theme, tr("profile ")).Layout(gtx) }),
+	}
+	for _, name := range ui.Config.profileNames() {
+		label := name
+		if name == defaultProfile {
+			label = tr("Default")
+		}
+		children = append(children, layout.Rigid(func(gtx C) D {
+			return ui.layoutSetting(gtx, material.RadioButton(ui.Theme, ui.ProfileRadio, name, label).Layout)
+		}))
+	}
+	return append(children, layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout))
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+)
+
+// progressWindow는 현재 속도를 구할 때 돌아보는 시간이다.
+const progressWindow = 5 * time.Second
+
+// copyProgress는 진행중인 복사 작업의 진행 상황이다.
+// 복사하는 고루틴이 갱신하고 화면이 읽으므로 모든 메서드는 동시에 불러도 안전하다.
+// nil이라면 아무것도 기록하지 않는다.
+type copyProgress struct {
+	mu         sync.Mutex
+	start      time.Time
+	files      int
+	totalFiles int
+	bytes      int64
+	totalBytes int64
+	// current는 지금 복사하는 파일이고, fileEnd는 그 파일을 다 복사했을 때의 bytes이다.
+	current string
+	fileEnd int64
+	// samples는 최근 progressWindow 동안의 복사한 바이트 수로, 현재 속도를 구하는데 쓴다.
+	samples []progressSample
+}
+
+type progressSample struct {
+	at    time.Time
+	bytes int64
+}
+
+// setTotal은 복사할 파일들의 수와 크기를 정하고 시간을 재기 시작한다.
+func (c *copyProgress) setTotal(files int, bytes int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.start = time.Now()
+	c.totalFiles = files
+	c.totalBytes = bytes
+	c.samples = []progressSample{{at: c.start}}
+}
+
+// startFile은 크기가 size인 파일 path를 복사하기 시작했음을 기록한다.
+func (c *copyProgress) startFile(path string, size int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current = path
+	c.fileEnd = c.bytes + size
+}
+
+// addBytes는 지금 복사하는 파일에서 n 바이트를 더 복사했음을 기록한다.
+func (c *copyProgress) addBytes(n int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bytes += n
+	c.sample()
+}
+
+// finishFile은 지금 복사하는 파일을 마쳤음을 기록한다.
+// 건너뛰거나 링크한 파일, 실패한 파일도 그 크기만큼 마친 것으로 센다.
+func (c *copyProgress) finishFile() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.files++
+	if c.bytes < c.fileEnd {
+		c.bytes = c.fileEnd
+	}
+	c.current = ""
+	c.sample()
+}
+
+// sample은 현재 속도를 구하기 위해 지금까지 복사한 바이트 수를 기록한다. c.mu를 잡고 불러야 한다.
+func (c *copyProgress) sample() {
+	now := time.Now()
+	if last := c.samples[len(c.samples)-1]; now.Sub(last.at) < progressWindow/20 {
+		return
+	}
+	c.samples = append(c.samples, progressSample{at: now, bytes: c.bytes})
+	// 가장 오래된 것이 창 밖에 있더라도 속도를 구할 수 있도록 하나는 남긴다.
+	drop := 0
+	for drop < len(c.samples)-2 && now.Sub(c.samples[drop+1].at) >= progressWindow {
+		drop++
+	}
+	c.samples = c.samples[drop:]
+}
+
+// progressStatus는 어느 한 순간의 진행 상황이다.
+type progressStatus struct {
+	Files      int
+	TotalFiles int
+	Bytes      int64
+	TotalBytes int64
+	Current    string
+	// Rate는 최근의 초당 바이트 수로, 아직 알 수 없다면 0이다.
+	Rate float64
+	// Left는 남은 예상 시간으로, 아직 알 수 없다면 음수이다.
+	Left time.Duration
+}
+
+// status는 지금의 진행 상황과 현재 속도, 남은 예상 시간을 구한다.
+func (c *copyProgress) status() progressStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st := progressStatus{
+		Files:      c.files,
+		TotalFiles: c.totalFiles,
+		Bytes:      c.bytes,
+		TotalBytes: c.totalBytes,
+		Current:    c.current,
+		Left:       -1,
+	}
+	if len(c.samples) == 0 {
+		return st
+	}
+	first := c.samples[0]
+	if d := time.Since(first.at).Seconds(); d >= 1 {
+		st.Rate = float64(c.bytes-first.bytes) / d
+	}
+	if st.Rate > 0 {
+		st.Left = time.Duration(float64(c.totalBytes-c.bytes) / st.Rate * float64(time.Second))
+	}
+	return st
+}
+
+// fraction은 바이트 기준으로 얼마나 복사했는지를 0과 1 사이로 나타낸다.
+func (st progressStatus) fraction() float32 {
+	if st.TotalBytes > 0 {
+		return float32(float64(st.Bytes) / float64(st.TotalBytes))
+	}
+	if st.TotalFiles > 0 {
+		return float32(st.Files) / float32(st.TotalFiles)
+	}
+	return 0
+}
+
+// String은 진행 상황을 "3 of 10 files, 1.2 GB of 4.0 GB" 처럼 나타낸다.
+func (st progressStatus) String() string {
+	return fmt.Sprintf(tr("%d of %d files, %s of %s"), st.Files, st.TotalFiles, formatBytes(st.Bytes), formatBytes(st.TotalBytes))
+}
+
+// speed는 현재 속도와 남은 예상 시간을 "85.3 MB/s, about 2m10s left" 처럼 나타낸다.
+func (st progressStatus) speed() string {
+	if st.Rate <= 0 {
+		return tr("measuring speed...")
+	}
+	line := formatBytes(int64(st.Rate)) + "/s"
+	if st.Left >= 0 {
+		line += fmt.Sprintf(tr(", about %s left"), st.Left.Round(time.Second))
+	}
+	return line
+}
+
+// startCopy는 work를 다른 고루틴에서 실행하고, 끝날 때까지 화면을 막고 진행 상황을 보여준다.
+// 끝나면 ui.Program.Copied에서 from 이후를 이번에 복사한 파일들로 보고 결과를 보여준다.
+func (ui *UI) startCopy(from int, work func() error) {
+	ui.stopWaiting()
+	ui.copyFrom = from
+	ui.progress = new(copyProgress)
+	ui.Program.progress = ui.progress
+	ch := make(chan error, 1)
+	ui.copyDone = ch
+	go func() {
+		ch <- work()
+		ui.Window.Invalidate()
+	}()
+}
+
+// copying은 복사 작업이 진행중인지 확인한다.
+func (ui *UI) copying() bool {
+	return ui.copyDone != nil
+}
+
+// takeCopyDone은 복사 작업이 끝났다면 그 결과를 보여준다.
+func (ui *UI) takeCopyDone() {
+	if ui.copyDone == nil {
+		return
+	}
+	select {
+	case err := <-ui.copyDone:
+		ui.copyDone = nil
+		ui.Program.progress = nil
+		ui.finishRun(ui.copyFrom, err)
+	default:
+	}
+}
+
+// layoutProgress는 복사 작업의 진행 상황을 분석 화면 위에 그린다.
+func (ui *UI) layoutProgress(gtx C, main layout.Widget) D {
+	// 진행 상황이 계속 바뀌므로 주기적으로 다시 그린다.
+	gtx.Execute(op.InvalidateCmd{At: gtx.Now.Add(500 * time.Millisecond)})
+	st := ui.progress.status()
+	title := tr("Copying")
+	if ui.Program.Method == "catalog" {
+		title = tr("Cataloging")
+	}
+	return ui.layoutModal(gtx, main, func(gtx C) D {
+		gtx.Constraints.Min.X = gtx.Constraints.Max.X
+		children := []layout.FlexChild{
+			layout.Rigid(material.H6(ui.Theme, title).Layout),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+		}
+		if st.TotalFiles == 0 {
+			children = append(children, layout.Rigid(material.Body1(ui.Theme, tr("preparing...")).Layout))
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+		}
+		children = append(children,
+			layout.Rigid(material.ProgressBar(ui.Theme, st.fraction()).Layout),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(material.Body1(ui.Theme, st.String()).Layout),
+			layout.Rigid(material.Body1(ui.Theme, st.speed()).Layout),
+		)
+		if st.Current != "" {
+			children = append(children, layout.Rigid(func(gtx C) D {
+				lbl := material.Body2(ui.Theme, st.Current)
+				lbl.MaxLines = 1
+				return lbl.Layout(gtx)
+			}))
+		}
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// quotactl 명령과 구조체들은 linux/quota.h와 linux/fs.h를 따른다.
+const (
+	qGetQuota        = 0x800007
+	usrQuota         = 0
+	prjQuota         = 2
+	fsIocFsGetXattr  = 0x801c581f
+	quotaBlockSize   = 1024
+	qifBLimitsValid  = 1
+	qifSpaceValid    = 2
+	qifBLimitsAndUse = qifBLimitsValid | qifSpaceValid
+)
+
+type ifDqblk struct {
+	BHardLimit uint64
+	BSoftLimit uint64
+	CurSpace   uint64
+	IHardLimit uint64
+	ISoftLimit uint64
+	CurInodes  uint64
+	BTime      uint64
+	ITime      uint64
+	Valid      uint32
+	_          uint32
+}
+
+type fsxattr struct {
+	XFlags     uint32
+	ExtSize    uint32
+	NExtents   uint32
+	ProjID     uint32
+	CowExtSize uint32
+	_          [8]byte
+}
+
+// quotaLeft는 path에 파일을 만들 때 적용되는 사용자 쿼터와 프로젝트 쿼터 중 남은 공간이 더 적은 것을 반환한다.
+// 쿼터가 없거나 확인할 수 없다면 ok가 거짓이다.
+func quotaLeft(path string) (left uint64, kind string, ok bool) {
+	dev := mountDevice(path)
+	if dev == "" {
+		return 0, "", false
+	}
+	if l, found := getQuota(dev, usrQuota, os.Getuid()); found {
+		left, kind, ok = l, "user quota", true
+	}
+	if prj := projectID(path); prj != 0 {
+		if l, found := getQuota(dev, prjQuota, int(prj)); found && (!ok || l < left) {
+			left, kind, ok = l, "project "+strconv.Itoa(int(prj))+" quota", true
+		}
+	}
+	return left, kind, ok
+}
+
+// getQuota는 장치 dev에서 id의 쿼터 중 남은 공간을 반환한다. 한도가 없다면 found가 거짓이다.
+func getQuota(dev string, typ, id int) (left uint64, found bool) {
+	devp, err := unix.BytePtrFromString(dev)
+	if err != nil {
+		return 0, false
+	}
+	var q ifDqblk
+	cmd := qGetQuota<<8 | typ
+	_, _, errno := unix.Syscall6(unix.SYS_QUOTACTL, uintptr(cmd), uintptr(unsafe.Pointer(devp)), uintptr(id), uintptr(unsafe.Pointer(&q)), 0, 0)
+	if errno != 0 || q.Valid&qifBLimitsAndUse != qifBLimitsAndUse {
+		return 0, false
+	}
+	limit := q.BHardLimit
+	if limit == 0 || (q.BSoftLimit != 0 && q.BSoftLimit < limit) {
+		limit = q.BSoftLimit
+	}
+	if limit == 0 {
+		return 0, false
+	}
+	limit *= quotaBlockSize
+	if q.CurSpace >= limit {
+		return 0, true
+	}
+	return limit - q.CurSpace, true
+}
+
+// projectID는 디렉토리 path의 프로젝트 쿼터 아이디로, 없거나 확인할 수 없다면 0이다.
+func projectID(path string) uint32 {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	var x fsxattr
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), fsIocFsGetXattr, uintptr(unsafe.Pointer(&x)))
+	if errno != 0 {
+		return 0
+	}
+	return x.ProjID
+}
+
+// mountDevice는 /proc/self/mounts에서 path가 속한 마운트의 장치 경로를 찾는다.
+func mountDevice(path string) string {
+	data, err := os.ReadFile("/proc/self/mounts")
+	if err != nil {
+		return ""
+	}
+	path = filepath.Clean(path)
+	dev, best := "", ""
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		mnt := unescapeMount(fields[1])
+		if len(mnt) < len(best) {
+			continue
+		}
+		if path == mnt || strings.HasPrefix(path, strings.TrimSuffix(mnt, "/")+"/") {
+			dev, best = unescapeMount(fields[0]), mnt
+		}
+	}
+	return dev
+}
@@ -0,0 +1,8 @@
+//go:build !windows && !linux
+
+package main
+
+// quotaLeft는 쿼터를 확인할 수 없는 운영체제에서는 항상 ok가 거짓이다.
+func quotaLeft(path string) (left uint64, kind string, ok bool) {
+	return 0, "", false
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// quotaLeft는 path가 있는 볼륨에서 디스크 쿼터 때문에 사용자가 쓸 수 있는 공간이 볼륨의 빈 공간보다 적을 때
+// 그 공간을 반환한다. 쿼터가 없거나 확인할 수 없다면 ok가 거짓이다.
+func quotaLeft(path string) (left uint64, kind string, ok bool) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, "", false
+	}
+	var avail, total, free uint64
+	err = windows.GetDiskFreeSpaceEx(p, &avail, &total, &free)
+	if err != nil || avail >= free {
+		return 0, "", false
+	}
+	return avail, "user quota", true
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// loadShowRegistry는 쇼 코드(SHOW)와 그 쇼의 저장소 루트 경로를 대응시킨 쇼 레지스트리를 읽는다.
+//
+// loc은 파일 경로 또는 http(s) 주소이다.
+// 주소라면 JSON 오브젝트를, 파일이라면 확장자가 .json일 때 JSON을, 그 외에는 TOML을 읽는다.
+//
+//	abc = "/mnt/storm/show/abc"
+//	xyz = "/mnt/nearline/show/xyz"
+func loadShowRegistry(loc string) (map[string]string, error) {
+	roots := make(map[string]string)
+	loc = strings.TrimSpace(loc)
+	if loc == "" {
+		return roots, nil
+	}
+	if strings.HasPrefix(loc, "http://") || strings.HasPrefix(loc, "https://") {
+		client := &http.Client{Timeout: 5 * time.Second}
+		resp, err := client.Get(loc)
+		if err != nil {
+			return nil, fmt.Errorf("show registry: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("show registry: %s: %s", resp.Status, loc)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&roots)
+		if err != nil {
+			return nil, fmt.Errorf("show registry: %v", err)
+		}
+		return roots, nil
+	}
+	if strings.HasSuffix(loc, ".json") {
+		data, err := os.ReadFile(loc)
+		if err != nil {
+			return nil, fmt.Errorf("show registry: %v", err)
+		}
+		err = json.Unmarshal(data, &roots)
+		if err != nil {
+			return nil, fmt.Errorf("show registry: %v: %s", err, loc)
+		}
+		return roots, nil
+	}
+	_, err := toml.DecodeFile(loc, &roots)
+	if err != nil {
+		return nil, fmt.Errorf("show registry: %v", err)
+	}
+	return roots, nil
+}
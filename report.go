@@ -0,0 +1,201 @@
+package main
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"gioui.org/io/clipboard"
+	"gioui.org/x/richtext"
+)
+
+// 분석 화면에서 소스들을 묶는 방법
+const (
+	groupByDest   = "dest"
+	groupByShow   = "show"
+	groupBySource = "source"
+)
+
+// 분석 화면을 조작하는 표시들로, 보고서를 텍스트로 만들 때는 뺀다.
+const (
+	markChecked   = "[x] "
+	markUnchecked = "[ ] "
+	markOverride  = " [change destination]"
+	markExpanded  = "▼ "
+	markCollapsed = "► "
+)
+
+// reportView는 분석 화면을 어떻게 보여줄지에 대한 설정이다.
+// 분석 결과와 관계없이 화면에서만 쓰이므로 UI에 둔다.
+type reportView struct {
+	// Collapsed는 접어둔 섹션들이다. 대상 디렉토리 섹션은 "dest:"와 대상 디렉토리를,
+	// 쇼 섹션은 "show:"와 쇼 이름을 이은 키를 쓴다.
+	Collapsed map[string]bool
+	// Filter는 보여줄 소스를 고르는 조건이다. 공백으로 나눈 조건들을 모두 만족하는 소스만 보여준다.
+	// 각 조건은 SHOT=SH010 처럼 경로에서 얻은 키의 값이거나, 소스나 대상 경로에 들어있는 문자열이다.
+	Filter string
+	// Group은 소스들을 묶는 방법이다.
+	Group string
+}
+
+// filtering은 필터가 설정되어 있는지 여부이다.
+func (v *reportView) filtering() bool {
+	return strings.TrimSpace(v.Filter) != ""
+}
+
+// matches는 소스 src가 필터 조건을 모두 만족하는지 확인한다. 대소문자는 구분하지 않는다.
+func (v *reportView) matches(p *Program, src string) bool {
+	var env map[string]string
+	for _, term := range strings.Fields(v.Filter) {
+		if key, val, ok := strings.Cut(term, "="); ok && key != "" {
+			if env == nil {
+				env, _ = p.Env(src)
+			}
+			got, ok := env[key]
+			if !ok {
+				got = env[strings.ToUpper(key)]
+			}
+			if !strings.EqualFold(got, val) {
+				return false
+			}
+			continue
+		}
+		if !containsFold(src, term) && !containsFold(p.DestDir[src], term) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesText는 경로만 있는 목록의 항목 text가 필터의 문자열 조건들을 모두 만족하는지 확인한다.
+// 키 조건은 경로를 분석하지 못한 항목에 쓸 수 없으므로 맞지 않는 것으로 본다.
+func (v *reportView) matchesText(text string) bool {
+	for _, term := range strings.Fields(v.Filter) {
+		if strings.Contains(term, "=") || !containsFold(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(s, sub string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(sub))
+}
+
+// toggle은 key 섹션을 접거나 편다.
+func (v *reportView) toggle(key string) {
+	if v.Collapsed == nil {
+		v.Collapsed = make(map[string]bool)
+	}
+	v.Collapsed[key] = !v.Collapsed[key]
+}
+
+// richToggle은 섹션을 접거나 펴는 표시이다.
+func richToggle(key string, collapsed bool) richtext.SpanStyle {
+	mark := markExpanded
+	if collapsed {
+		mark = markCollapsed
+	}
+	s := richTitle(mark)
+	s.Interactive = true
+	s.Set("collapse", key)
+	return s
+}
+
+// filterSources는 srcs 중 필터 조건을 만족하는 소스들을 반환한다.
+func (v *reportView) filterSources(p *Program, srcs []string) []string {
+	if !v.filtering() {
+		return srcs
+	}
+	matched := make([]string, 0, len(srcs))
+	for _, src := range srcs {
+		if v.matches(p, src) {
+			matched = append(matched, src)
+		}
+	}
+	return matched
+}
+
+// plannedSources는 대상 디렉토리가 정해진 소스들을 정렬해 반환한다.
+func (p *Program) plannedSources() []string {
+	srcs := make([]string, 0, len(p.DestDir))
+	for src := range p.DestDir {
+		srcs = append(srcs, src)
+	}
+	sortNatural(srcs)
+	return srcs
+}
+
+// showGroup은 소스의 SHOW와 SEQ 키 값으로 만든 묶음의 이름이다.
+func showGroup(p *Program, src string) string {
+	env, _ := p.Env(src)
+	parts := make([]string, 0, 2)
+	for _, key := range []string{"SHOW", "SEQ"} {
+		if val := env[key]; val != "" {
+			parts = append(parts, val)
+		}
+	}
+	if len(parts) == 0 {
+		return "(no show)"
+	}
+	return strings.Join(parts, " / ")
+}
+
+// richShowGroups는 소스들을 쇼와 시퀀스별로 묶어 보여준다. 각 소스 아래에 대상 디렉토리를 보여준다.
+func richShowGroups(p *Program, v *reportView) []richtext.SpanStyle {
+	groups := make(map[string][]string)
+	for _, src := range v.filterSources(p, p.plannedSources()) {
+		g := showGroup(p, src)
+		groups[g] = append(groups[g], src)
+	}
+	names := make([]string, 0, len(groups))
+	for g := range groups {
+		names = append(names, g)
+	}
+	sortNatural(names)
+	res := make([]richtext.SpanStyle, 0)
+	for _, g := range names {
+		key := "show:" + g
+		res = append(res, richToggle(key, v.Collapsed[key]))
+		res = append(res, richTitle(g))
+		n := len(groups[g])
+		plural := ""
+		if n != 1 {
+			plural = "s"
+		}
+		res = append(res, richText(" "+strconv.Itoa(n)+" source"+plural+"\n"))
+		if !v.Collapsed[key] {
+			res = append(res, richSources(p, groups[g], true)...)
+		}
+		res = append(res, richText("\n"))
+	}
+	return res
+}
+
+// reportText는 분석 화면의 내용을 일반 텍스트로 만든다. 체크박스처럼 화면을 조작하는 표시들은 뺀다.
+func reportText(spans []richtext.SpanStyle) string {
+	var b strings.Builder
+	for _, s := range spans {
+		switch s.Content {
+		case markChecked, markUnchecked, markOverride, markExpanded, markCollapsed:
+			continue
+		}
+		b.WriteString(s.Content)
+	}
+	return strings.TrimSpace(b.String()) + "\n"
+}
+
+// copyReport는 분석 결과나 복사 결과를 일반 텍스트로 클립보드에 복사한다.
+// 화면에서 접거나 걸러낸 것과 관계없이 전체 내용을 복사한다.
+func (ui *UI) copyReport(gtx C) {
+	var spans []richtext.SpanStyle
+	if ui.Program.Done {
+		spans = analyzeCopy(ui.Program)
+	} else {
+		spans = analyzeInput(ui.Program, &reportView{Group: ui.View.Group})
+	}
+	text := reportText(spans)
+	gtx.Execute(clipboard.WriteCmd{Type: "application/text", Data: io.NopCloser(strings.NewReader(text))})
+	ui.Notifier.SetText(tr("report copied to the clipboard"))
+	ui.NotifyIsError = false
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+
+package main
+
+import (
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// revealPath는 파일 관리자에서 path를 선택한 채로 보여준다.
+// 리눅스에서는 D-Bus의 FileManager1.ShowItems를 쓰고, 이를 지원하는 파일 관리자가 없다면
+// path가 있는 디렉토리를 연다.
+func revealPath(path string) error {
+	if runtime.GOOS == "darwin" {
+		return exec.Command("open", "-R", path).Start()
+	}
+	if runtime.GOOS != "linux" {
+		return openPath(filepath.Dir(path))
+	}
+	uri := (&url.URL{Scheme: "file", Path: path}).String()
+	cmd := exec.Command("dbus-send", "--session", "--print-reply", "--reply-timeout=5000",
+		"--dest=org.freedesktop.FileManager1", "--type=method_call",
+		"/org/freedesktop/FileManager1", "org.freedesktop.FileManager1.ShowItems",
+		"array:string:"+uri, "string:")
+	err := cmd.Start()
+	if err != nil {
+		return openPath(filepath.Dir(path))
+	}
+	// 파일 관리자가 뜰 때까지 화면을 막지 않도록 응답은 따로 기다린다.
+	go func() {
+		if cmd.Wait() != nil {
+			openPath(filepath.Dir(path))
+		}
+	}()
+	return nil
+}
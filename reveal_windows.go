@@ -0,0 +1,14 @@
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// revealPath는 탐색기에서 path를 선택한 채로 보여준다.
+// explorer는 /select, 뒤의 따옴표만 알아들으므로 명령줄을 직접 만든다.
+func revealPath(path string) error {
+	cmd := exec.Command("explorer")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `explorer /select,"` + path + `"`}
+	return cmd.Start()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Route는 특정 소스들을 기본 대상 경로 패턴 대신 다른 패턴으로 보내는 규칙이다.
+// Match와 When이 모두 설정되었다면 둘 다 맞아야 한다.
+type Route struct {
+	// Match는 소스 이름에 맞춰볼 글롭 패턴들로, 공백으로 구분한다. 대소문자는 구분하지 않는다.
+	// 예) "*.exr *.dpx"
+	Match string
+	// When은 분석한 키 값들을 검사하는 식이다. 문법은 parseCondition을 참고한다.
+	// 예) PART == "audio"
+	When string
+	// Dest는 규칙에 맞는 소스에 쓰일 대상 경로 패턴이다.
+	Dest string
+
+	cond condition
+}
+
+// compileRoutes는 규칙들이 올바른지 확인하고, 조건식을 분석해 둔 복사본을 반환한다.
+func compileRoutes(routes []Route) ([]Route, error) {
+	compiled := make([]Route, 0, len(routes))
+	for i, r := range routes {
+		if strings.TrimSpace(r.Dest) == "" {
+			return nil, fmt.Errorf("route %d: empty dest", i+1)
+		}
+		for _, pat := range strings.Fields(r.Match) {
+			if _, err := filepath.Match(pat, ""); err != nil {
+				return nil, fmt.Errorf("route %d: %v: %s", i+1, err, pat)
+			}
+		}
+		if strings.TrimSpace(r.When) != "" {
+			cond, err := parseCondition(r.When)
+			if err != nil {
+				return nil, fmt.Errorf("route %d: %v", i+1, err)
+			}
+			r.cond = cond
+		}
+		compiled = append(compiled, r)
+	}
+	return compiled, nil
+}
+
+// matchName은 소스 이름이 규칙의 글롭 패턴들 중 하나에 맞는지 확인한다.
+// 패턴이 없다면 모든 이름에 맞는다.
+func (r Route) matchName(name string) bool {
+	pats := strings.Fields(r.Match)
+	if len(pats) == 0 {
+		return true
+	}
+	name = strings.ToLower(name)
+	for _, pat := range pats {
+		if ok, _ := filepath.Match(strings.ToLower(pat), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// destPatternFor는 소스에 쓰일 대상 경로 패턴을 반환한다. env는 소스에서 분석한 값들이다.
+// 규칙들은 순서대로 확인되어 처음으로 맞는 규칙의 패턴이 쓰이고, 맞는 규칙이 없다면 기본 패턴이 쓰인다.
+func (p *Program) destPatternFor(src string, env map[string]string) string {
+	name := baseName(src)
+	for _, r := range p.Routes {
+		if !r.matchName(name) {
+			continue
+		}
+		if r.cond != nil && !r.cond.Match(env) {
+			continue
+		}
+		return r.Dest
+	}
+	return p.DestPattern
+}
+
+// expandRoot는 대상 경로 패턴이 @name 으로 시작한다면 그 부분을 roots에 정의된 루트 경로로 바꾼다.
+// 사이트마다 마운트 위치가 달라도 같은 패턴을 쓸 수 있게 한다.
+//
+//	@storm/show/${SHOW}  ->  /mnt/storm/show/${SHOW}
+func expandRoot(pattern string, roots map[string]string) (string, error) {
+	pattern = strings.TrimSpace(pattern)
+	if !strings.HasPrefix(pattern, "@") {
+		return pattern, nil
+	}
+	name, rest := pattern[1:], ""
+	if i := strings.IndexAny(name, `/\`); i >= 0 {
+		name, rest = name[:i], name[i:]
+	}
+	root, ok := roots[name]
+	if !ok {
+		return "", fmt.Errorf("unknown root alias in dest: @%s", name)
+	}
+	return strings.TrimRight(root, `/\`) + rest, nil
+}
+
+// destDirectoryFor는 소스에 맞는 대상 경로 패턴을 골라 소스를 복사할 폴더 경로를 반환한다.
+// 반환되는 경로는 p.Normalize 형식으로 정규화되고, p.Sanitize라면 쓸 수 없는 문자가 바뀐다.
+// 소스를 그 안에 복사한 경로를 만들 수 없다면 에러를 반환한다. 윈도우즈의 이름 규칙은 windowsRules일 때만 검사한다.
+func (p *Program) destDirectoryFor(src string, env map[string]string) (string, error) {
+	pattern, err := expandRoot(p.destPatternFor(src, env), p.Roots)
+	if err != nil {
+		return "", err
+	}
+	dir, err := destDirectory(src, p.localPath(pattern), env)
+	if err != nil {
+		return "", err
+	}
+	dir = normalizeName(dir, p.Normalize)
+	windows := windowsRules(dir)
+	if p.Sanitize {
+		dir = sanitizeDestPath(dir, windows)
+	}
+	if err := checkDestPath(filepath.Join(dir, baseName(src)), windows, p.MaxPathLen); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
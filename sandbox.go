@@ -0,0 +1,38 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// sandboxResult는 test 경로 하나를 현재 설정으로 분석했을 때의 키 값들과 대상 경로를 보여주는 텍스트를 만든다.
+// 실제 입력 경로들과 달리 파일시스템을 확인하지 않으므로, 존재하지 않는 경로로도 패턴을 시험해볼 수 있다.
+func sandboxResult(p *Program, src string) string {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return ""
+	}
+	src = p.sourcePath(src)
+	env, err := p.Env(src)
+	if err != nil {
+		return tr("error: ") + err.Error()
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	envs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		envs = append(envs, k+"="+env[k])
+	}
+	res := strings.Join(envs, "  ") + "\n"
+	if err := p.checkVocabulary(env); err != nil {
+		return res + tr("error: ") + err.Error()
+	}
+	dest, err := p.destDirectoryFor(src, env)
+	if err != nil {
+		return res + tr("error: ") + err.Error()
+	}
+	return res + "→ " + dest
+}
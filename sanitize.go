@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// illegalNameChars는 윈도우즈와 SMB 공유에서 파일 이름에 쓸 수 없는 문자들이다.
+// 백슬래시는 윈도우즈 경로에서는 구분자로 먼저 나뉘므로, 유닉스 경로의 이름에 있을 때만 걸린다.
+const illegalNameChars = `:*?"<>|\`
+
+// windowsFSTypes는 윈도우즈의 이름 규칙을 따르는 리눅스의 파일시스템 종류들이다.
+var windowsFSTypes = map[string]bool{
+	"cifs": true, "smb3": true, "smbfs": true, "ntfs": true, "ntfs3": true,
+	"vfat": true, "msdos": true, "exfat": true, "fuseblk": true,
+}
+
+// windowsRules는 대상 경로 path에 윈도우즈의 이름 규칙(쓸 수 없는 문자와 이름, 경로 길이)을 적용해야 하는지 확인한다.
+// 윈도우즈에서는 항상, 리눅스에서는 path가 SMB 공유나 NTFS, FAT 파일시스템에 있을 때 적용한다.
+// 마운트 목록을 읽지 않는 macOS에서는 외장 디스크와 네트워크 공유가 마운트되는 /Volumes 아래에 적용한다.
+func windowsRules(path string) bool {
+	switch runtime.GOOS {
+	case "windows":
+		return true
+	case "darwin":
+		return strings.HasPrefix(path, "/Volumes/")
+	}
+	if isWindowsPath(path) {
+		return true
+	}
+	m, ok := findMount(path)
+	return ok && windowsFSTypes[m.Type]
+}
+
+// isWindowsPath는 path를 윈도우즈 경로로 다뤄야 하는지 확인한다.
+// 윈도우즈에서는 항상 그렇고, 그 밖에서는 C: 나 \\server\share 로 시작하는 경로만 그렇다.
+func isWindowsPath(path string) bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return strings.HasPrefix(path, `\\`) || (len(path) >= 2 && path[1] == ':')
+}
+
+// maxNameBytes는 대부분의 파일시스템에서 경로 요소 하나의 최대 길이이다.
+const maxNameBytes = 255
+
+// reservedNames는 윈도우즈에서 확장자와 관계없이 파일 이름으로 쓸 수 없는 이름들이다.
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// splitPathRoot는 path를 루트(/, C:, \\server\share)와 나머지 경로 요소들로 나눈다.
+// 루트는 검사하거나 바꾸지 않아야 하는 부분이다.
+// 백슬래시는 윈도우즈 경로에서만 구분자이고, 유닉스 경로에서는 이름의 일부이다.
+func splitPathRoot(path string) (string, []string) {
+	if !isWindowsPath(path) {
+		return "", strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+	}
+	root := ""
+	rest := path
+	switch {
+	case strings.HasPrefix(path, `\\`) || strings.HasPrefix(path, "//"):
+		// UNC 경로의 서버와 공유 이름
+		parts := strings.FieldsFunc(path, isPathSep)
+		n := 2
+		if len(parts) < n {
+			n = len(parts)
+		}
+		root = path[:2] + strings.Join(parts[:n], path[:1])
+		rest = path[len(root):]
+	case len(path) >= 2 && path[1] == ':':
+		root = path[:2]
+		rest = path[2:]
+	}
+	return root, strings.FieldsFunc(rest, isPathSep)
+}
+
+func isPathSep(r rune) bool {
+	return r == '/' || r == '\\'
+}
+
+// nameProblem은 경로 요소 name을 쓸 수 없는 이유를 반환한다. 문제가 없다면 빈 문자열을 반환한다.
+// 제어 문자와 너무 긴 이름은 항상 검사하고, windows가 참이면 윈도우즈나 SMB 공유에서 쓸 수 없는 문자와 이름도 검사한다.
+func nameProblem(name string, windows bool) string {
+	if name == "." || name == ".." {
+		return ""
+	}
+	for _, r := range name {
+		if windows && strings.ContainsRune(illegalNameChars, r) {
+			return "invalid character " + strconv.QuoteRune(r) + " in " + strconv.Quote(name)
+		}
+		if r < 0x20 {
+			return "control character in " + strconv.Quote(name)
+		}
+	}
+	if windows && (strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ")) {
+		return "name ends with a dot or space: " + strconv.Quote(name)
+	}
+	stem, _, _ := strings.Cut(name, ".")
+	if windows && reservedNames[strings.ToUpper(stem)] {
+		return "reserved name: " + strconv.Quote(name)
+	}
+	if len(name) > maxNameBytes {
+		return fmt.Sprintf("name too long (%d bytes): %q", len(name), name)
+	}
+	return ""
+}
+
+// checkDestPath는 대상 경로 path를 만들 수 있는지 검사한다.
+// windows가 참이면 윈도우즈나 SMB 공유의 이름 규칙도 검사하고, maxLen이 0보다 크면 경로 전체의 길이(문자 수)도 검사한다.
+// 경로 길이의 제한은 윈도우즈의 것이므로 windows가 거짓이면 maxLen은 무시한다.
+func checkDestPath(path string, windows bool, maxLen int) error {
+	_, names := splitPathRoot(path)
+	for _, name := range names {
+		if prob := nameProblem(name, windows); prob != "" {
+			return fmt.Errorf("%s", prob)
+		}
+	}
+	if n := len([]rune(path)); windows && maxLen > 0 && n > maxLen {
+		return fmt.Errorf("path too long (%d > %d characters)", n, maxLen)
+	}
+	return nil
+}
+
+// sanitizeDestPath는 대상 경로 path의 각 요소에서 제어 문자를 _ 로 바꾼다.
+// windows가 참이면 윈도우즈나 SMB 공유에서 쓸 수 없는 문자도 _ 로 바꾸고 끝의 점과 공백을 지우며, 예약된 이름에는 _ 를 붙인다.
+// 너무 긴 이름은 바꾸지 않으므로 checkDestPath로 따로 검사해야 한다.
+func sanitizeDestPath(path string, windows bool) string {
+	root, names := splitPathRoot(path)
+	if len(names) == 0 {
+		return path
+	}
+	winPath := isWindowsPath(path)
+	isSep := func(s string, suffix bool) bool {
+		has := strings.HasPrefix
+		if suffix {
+			has = strings.HasSuffix
+		}
+		return has(s, "/") || (winPath && has(s, `\`))
+	}
+	sep := "/"
+	if winPath && strings.Contains(path, `\`) && !strings.Contains(path, "/") {
+		sep = `\`
+	}
+	for i, name := range names {
+		if name == "." || name == ".." {
+			continue
+		}
+		name = strings.Map(func(r rune) rune {
+			if r < 0x20 || (windows && strings.ContainsRune(illegalNameChars, r)) {
+				return '_'
+			}
+			return r
+		}, name)
+		if windows {
+			name = strings.TrimRight(name, ". ")
+			if name == "" {
+				name = "_"
+			}
+			stem, _, _ := strings.Cut(name, ".")
+			if reservedNames[strings.ToUpper(stem)] {
+				name = stem + "_" + name[len(stem):]
+			}
+		}
+		names[i] = name
+	}
+	res := root
+	if isSep(path[len(root):], false) {
+		res += sep
+	}
+	res += strings.Join(names, sep)
+	if isSep(path, true) {
+		res += sep
+	}
+	return res
+}
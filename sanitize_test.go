@@ -0,0 +1,118 @@
+package main
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSplitPathRoot(t *testing.T) {
+	cases := []struct {
+		path  string
+		root  string
+		names []string
+		unix  bool
+	}{
+		{`C:\show\plate`, "C:", []string{"show", "plate"}, false},
+		{`\\server\share\show\plate`, `\\server\share`, []string{"show", "plate"}, false},
+		{"/mnt/storm/show", "", []string{"mnt", "storm", "show"}, true},
+		// 유닉스 경로에서 백슬래시는 이름의 일부이다.
+		{`/mnt/a\b`, "", []string{"mnt", `a\b`}, true},
+	}
+	for _, c := range cases {
+		if c.unix && runtime.GOOS == "windows" {
+			continue
+		}
+		root, names := splitPathRoot(c.path)
+		if root != c.root || !reflect.DeepEqual(names, c.names) {
+			t.Errorf("splitPathRoot(%q) = %q, %q, want %q, %q", c.path, root, names, c.root, c.names)
+		}
+	}
+}
+
+func TestNameProblem(t *testing.T) {
+	cases := []struct {
+		name    string
+		windows bool
+		bad     bool
+	}{
+		{"plate.exr", true, false},
+		{"a:b", true, true},
+		{"a:b", false, false},
+		{"what?", true, true},
+		{"CON", true, true},
+		{"con.txt", true, true},
+		{"CON", false, false},
+		{"console", true, false},
+		{"trailing.", true, true},
+		{"trailing ", true, true},
+		{"trailing.", false, false},
+		{"tab\there", false, true},
+		{strings.Repeat("a", maxNameBytes+1), false, true},
+		{"..", true, false},
+	}
+	for _, c := range cases {
+		got := nameProblem(c.name, c.windows)
+		if (got != "") != c.bad {
+			t.Errorf("nameProblem(%q, %v) = %q, want problem %v", c.name, c.windows, got, c.bad)
+		}
+	}
+}
+
+func TestCheckDestPath(t *testing.T) {
+	long := `C:\` + strings.Repeat("a", 100) + `\` + strings.Repeat("b", 100) + `\` + strings.Repeat("c", 100)
+	cases := []struct {
+		path    string
+		windows bool
+		maxLen  int
+		bad     bool
+		unix    bool
+	}{
+		{`C:\show\plate\plate.1001.exr`, true, 260, false, false},
+		{`C:\show\AUX\plate.exr`, true, 260, true, false},
+		{long, true, 260, true, false},
+		{long, true, 0, false, false},
+		// 경로 길이의 제한은 윈도우즈의 것이다.
+		{"/mnt/" + strings.Repeat("a/", 200), false, 260, false, true},
+		{"/mnt/show/a:b/plate.exr", false, 260, false, true},
+		{"/mnt/show/a:b/plate.exr", true, 260, true, true},
+	}
+	for _, c := range cases {
+		if c.unix && runtime.GOOS == "windows" {
+			continue
+		}
+		err := checkDestPath(c.path, c.windows, c.maxLen)
+		if (err != nil) != c.bad {
+			t.Errorf("checkDestPath(%q, %v, %d) = %v, want error %v", c.path, c.windows, c.maxLen, err, c.bad)
+		}
+	}
+}
+
+func TestSanitizeDestPath(t *testing.T) {
+	cases := []struct {
+		path    string
+		windows bool
+		want    string
+		unix    bool
+	}{
+		{`C:\show\a:b\plate.exr`, true, `C:\show\a_b\plate.exr`, false},
+		{`C:\show\CON\`, true, `C:\show\CON_\`, false},
+		{`C:\show\nul.txt`, true, `C:\show\nul_.txt`, false},
+		{`C:\show\dir. \plate.exr`, true, `C:\show\dir\plate.exr`, false},
+		{`\\server\share\a?b`, true, `\\server\share\a_b`, false},
+		{"/mnt/show/a:b/", true, "/mnt/show/a_b/", true},
+		{"/mnt/show/a:b/", false, "/mnt/show/a:b/", true},
+		{"/mnt/show/a\tb", false, "/mnt/show/a_b", true},
+		// 유닉스 경로의 백슬래시는 구분자가 아니므로 윈도우즈 규칙에서 바꿀 문자이다.
+		{`/mnt/show/a\b`, true, "/mnt/show/a_b", true},
+	}
+	for _, c := range cases {
+		if c.unix && runtime.GOOS == "windows" {
+			continue
+		}
+		if got := sanitizeDestPath(c.path, c.windows); got != c.want {
+			t.Errorf("sanitizeDestPath(%q, %v) = %q, want %q", c.path, c.windows, got, c.want)
+		}
+	}
+}
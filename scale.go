@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math"
+)
+
+// defaultFontSize는 Gio 위젯의 기본 글자 크기(sp)이다.
+const defaultFontSize = 16
+
+// minScale과 maxScale은 화면 배율로 쓸 수 있는 범위이며, scaleStep은 단축키로 한번에 바꾸는 크기이다.
+const (
+	minScale  = 0.5
+	maxScale  = 4
+	scaleStep = 0.1
+)
+
+// scaleMetric은 설정의 화면 배율과 글자 크기를 gtx의 단위에 적용한다.
+// 모든 dp와 sp 값이 UIScale만큼 커지고, sp 값은 FontSize에 맞춰 한번 더 바뀐다.
+// 4K 모니터처럼 운영체제가 알려주는 배율이 맞지 않는 화면을 위한 것이다.
+func (ui *UI) scaleMetric(gtx *C) {
+	scale := uiScale(ui.Config.UIScale)
+	font := float32(1)
+	if ui.Config.FontSize > 0 {
+		font = ui.Config.FontSize / defaultFontSize
+	}
+	gtx.Metric.PxPerDp *= scale
+	gtx.Metric.PxPerSp *= scale * font
+}
+
+// uiScale은 설정의 화면 배율 s를 쓸 수 있는 범위로 맞춘다. 0이면 배율을 바꾸지 않는다.
+func uiScale(s float32) float32 {
+	if s <= 0 {
+		return 1
+	}
+	return min(max(s, minScale), maxScale)
+}
+
+// zoom은 화면 배율을 step 단계만큼 바꾼다. step이 0이면 원래 크기로 되돌린다.
+// 바뀐 배율은 창을 닫을 때 저장된다.
+func (ui *UI) zoom(step int) {
+	if step == 0 {
+		ui.Config.UIScale = 0
+	} else {
+		s := uiScale(ui.Config.UIScale) + float32(step)*scaleStep
+		// 0.1씩 더할 때 생기는 오차가 쌓이지 않도록 반올림한다.
+		ui.Config.UIScale = uiScale(float32(math.Round(float64(s)*10) / 10))
+	}
+	ui.Window.Invalidate()
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// frameName은 plate.1001.exr, plate_1001.exr 처럼 점이나 밑줄 뒤에 프레임 번호가 오고
+// 확장자로 끝나는 파일 이름이다.
+var frameName = regexp.MustCompile(`^(.*[._])(\d+)(\.[^.\d][^.]*)$`)
+
+// seqExts는 프레임마다 파일 하나씩 저장되는 이미지 시퀀스의 확장자들이다.
+// 사진이나 동영상 클립은 이름에 번호가 있어도 시퀀스로 묶지 않는다.
+var seqExts = map[string]bool{
+	"exr": true, "dpx": true, "cin": true, "tif": true, "tiff": true, "png": true,
+	"tga": true, "sgi": true, "rgb": true, "hdr": true, "jp2": true, "j2c": true, "ari": true,
+}
+
+// minFrameDigits는 시퀀스로 묶기 위한 프레임 번호의 최소 자릿수이다.
+// 가장 긴 프레임 번호가 이보다 짧은 묶음은 take_1.png, take_2.png 처럼 번호를 붙인 파일들로 본다.
+const minFrameDigits = 3
+
+// isSeqExt는 ext(예: ".exr")가 이미지 시퀀스의 확장자인지 확인한다.
+func isSeqExt(ext string) bool {
+	return seqExts[strings.ToLower(strings.TrimPrefix(ext, "."))]
+}
+
+// sequence는 같은 디렉토리 안에서 프레임 번호만 다른 파일들의 묶음이다.
+type sequence struct {
+	Dir string
+	// Prefix는 프레임 번호 앞의 이름으로, 구분자를 포함한다. 예) "plate."
+	Prefix string
+	// Ext는 프레임 번호 뒤의 확장자이다. 예) ".exr"
+	Ext string
+	// Pad는 프레임 번호의 자릿수이다. 파일마다 자릿수가 다르다면 0이다.
+	Pad int
+	// Frames와 Paths는 프레임 번호 순서로 정렬된 프레임 번호와 그 파일 경로이다.
+	Frames []int
+	Paths  []string
+}
+
+// parseFrame은 파일 이름을 프레임 번호 앞부분, 프레임 번호, 확장자로 나눈다.
+func parseFrame(name string) (prefix, digits, ext string, ok bool) {
+	m := frameName.FindStringSubmatch(name)
+	if m == nil {
+		return "", "", "", false
+	}
+	return m[1], m[2], m[3], true
+}
+
+// detectSequences는 경로들 중 두 개 이상의 프레임으로 이루어진 이미지 시퀀스들을 찾는다.
+// 시퀀스에 속하지 않은 경로들은 singles로 반환된다.
+func detectSequences(paths []string) (seqs []*sequence, singles []string) {
+	return groupFrames(paths, minFrameDigits)
+}
+
+// groupFrames는 detectSequences와 같지만, 가장 긴 프레임 번호가 minDigits 자리보다 짧은 묶음도
+// 시퀀스로 본다.
+func groupFrames(paths []string, minDigits int) (seqs []*sequence, singles []string) {
+	groups := make(map[string]*sequence)
+	order := make([]string, 0)
+	pads := make(map[string]map[int]bool)
+	for _, path := range paths {
+		dir, name := filepath.Split(path)
+		prefix, digits, ext, ok := parseFrame(name)
+		if !ok || !isSeqExt(ext) {
+			singles = append(singles, path)
+			continue
+		}
+		frame, err := strconv.Atoi(digits)
+		if err != nil {
+			singles = append(singles, path)
+			continue
+		}
+		key := dir + "\x00" + prefix + "\x00" + ext
+		s := groups[key]
+		if s == nil {
+			s = &sequence{Dir: filepath.Clean(dir), Prefix: prefix, Ext: ext}
+			groups[key] = s
+			order = append(order, key)
+			pads[key] = make(map[int]bool)
+		}
+		s.Frames = append(s.Frames, frame)
+		s.Paths = append(s.Paths, path)
+		pads[key][len(digits)] = true
+	}
+	for _, key := range order {
+		s := groups[key]
+		widest := 0
+		for pad := range pads[key] {
+			widest = max(widest, pad)
+		}
+		if len(s.Frames) < 2 || widest < minDigits {
+			singles = append(singles, s.Paths...)
+			continue
+		}
+		if len(pads[key]) == 1 {
+			for pad := range pads[key] {
+				s.Pad = pad
+			}
+		}
+		sort.Sort(byFrame{s})
+		seqs = append(seqs, s)
+	}
+	sortNatural(singles)
+	return seqs, singles
+}
+
+// split은 시퀀스의 프레임들을 key가 같은 것끼리 다시 묶는다.
+// 묶음마다 시퀀스를 다시 찾으므로, 프레임이 하나만 남은 묶음은 singles로 반환된다.
+func (s *sequence) split(key func(path string) string) (seqs []*sequence, singles []string) {
+	groups := make(map[string][]string)
+	order := make([]string, 0)
+	for _, path := range s.Paths {
+		k := key(path)
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], path)
+	}
+	if len(order) == 1 {
+		return []*sequence{s}, nil
+	}
+	for _, k := range order {
+		ss, sg := detectSequences(groups[k])
+		seqs = append(seqs, ss...)
+		singles = append(singles, sg...)
+	}
+	return seqs, singles
+}
+
+// byFrame은 시퀀스의 프레임들을 프레임 번호 순서로 정렬한다.
+type byFrame struct{ s *sequence }
+
+func (b byFrame) Len() int           { return len(b.s.Frames) }
+func (b byFrame) Less(i, j int) bool { return b.s.Frames[i] < b.s.Frames[j] }
+func (b byFrame) Swap(i, j int) {
+	b.s.Frames[i], b.s.Frames[j] = b.s.Frames[j], b.s.Frames[i]
+	b.s.Paths[i], b.s.Paths[j] = b.s.Paths[j], b.s.Paths[i]
+}
+
+// Name은 프레임 번호를 #으로 나타낸 시퀀스의 이름이다. 예) plate.####.exr
+func (s *sequence) Name() string {
+	pad := s.Pad
+	if pad == 0 {
+		pad = 1
+	}
+	return s.Prefix + strings.Repeat("#", pad) + s.Ext
+}
+
+// Range는 시퀀스의 첫 프레임과 마지막 프레임, 프레임 수를 보여준다. 예) 1001-1100, 100 frames
+func (s *sequence) Range() string {
+	first, last := s.Frames[0], s.Frames[len(s.Frames)-1]
+	return strconv.Itoa(first) + "-" + strconv.Itoa(last) + ", " + strconv.Itoa(len(s.Frames)) + " frames"
+}
+
+// String은 시퀀스의 경로와 프레임 범위이다. 예) /show/plate/plate.####.exr (1001-1100, 100 frames)
+func (s *sequence) String() string {
+	return filepath.Join(s.Dir, s.Name()) + " (" + s.Range() + ")"
+}
+
+// Missing은 첫 프레임과 마지막 프레임 사이에서 빠진 프레임들을 1005, 1010-1012 처럼 범위로 묶어 보여준다.
+// 빠진 프레임이 없다면 빈 문자열이다.
+func (s *sequence) Missing() string {
+	gaps := make([]string, 0)
+	for i := 1; i < len(s.Frames); i++ {
+		from, to := s.Frames[i-1]+1, s.Frames[i]-1
+		switch {
+		case from > to:
+			continue
+		case from == to:
+			gaps = append(gaps, strconv.Itoa(from))
+		default:
+			gaps = append(gaps, strconv.Itoa(from)+"-"+strconv.Itoa(to))
+		}
+	}
+	return strings.Join(gaps, ", ")
+}
+
+// renumber는 복사할 때 시퀀스 프레임 번호를 바꾸는 방법이다.
+type renumber struct {
+	// SetStart가 참이면 각 시퀀스의 첫 프레임이 Start가 되도록 번호를 옮기고,
+	// 거짓이면 모든 프레임 번호에 Offset을 더한다.
+	SetStart bool
+	Start    int
+	Offset   int
+	// Pad가 0보다 크면 프레임 번호를 이 자릿수가 되도록 0으로 채운다. (img.1.exr -> img.0001.exr)
+	// 0이면 원래 번호의 자릿수를 따른다.
+	Pad int
+}
+
+// parseRenumber는 "1001" 처럼 시작 프레임을, 또는 "+100", "-10" 처럼 더할 수를 지정한 텍스트를 분석한다.
+// 빈 텍스트는 번호를 바꾸지 않는다.
+func parseRenumber(text string) (renumber, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return renumber{}, nil
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return renumber{}, fmt.Errorf("renumber frames: not a frame or an offset: %s", text)
+	}
+	if strings.HasPrefix(text, "+") || strings.HasPrefix(text, "-") {
+		return renumber{Offset: n}, nil
+	}
+	return renumber{SetStart: true, Start: n}, nil
+}
+
+// active는 프레임 번호를 바꾸는지 확인한다.
+func (r renumber) active() bool {
+	return r.SetStart || r.Offset != 0 || r.Pad > 0
+}
+
+// frame은 시퀀스 seq의 프레임 번호 f의 새 번호를 반환한다.
+func (r renumber) frame(seq *sequence, f int) int {
+	if r.SetStart {
+		return f - seq.Frames[0] + r.Start
+	}
+	return f + r.Offset
+}
+
+// renameFrames는 복사할 파일들 중 시퀀스 프레임의 새 상대 경로들을 Rel을 키로 해서 반환한다.
+// 새 번호는 r.Pad가 없다면 원래 번호의 자릿수를 따른다. 새 번호가 음수가 되는 시퀀스가 있다면 에러를 반환한다.
+func (r renumber) renameFrames(files []srcFile) (map[string]string, error) {
+	renames := make(map[string]string)
+	if !r.active() {
+		return renames, nil
+	}
+	rels := make([]string, 0, len(files))
+	for _, f := range files {
+		rels = append(rels, f.Rel)
+	}
+	// 자릿수를 채우려는 img.1.exr 같은 프레임들도 바꿀 수 있도록 자릿수가 짧은 묶음도 시퀀스로 본다.
+	seqs, _ := groupFrames(rels, 1)
+	for _, seq := range seqs {
+		for i, f := range seq.Frames {
+			nf := r.frame(seq, f)
+			if nf < 0 {
+				return nil, fmt.Errorf("renumber frames: negative frame %d: %s", nf, seq)
+			}
+			_, name := filepath.Split(seq.Paths[i])
+			_, digits, _, _ := parseFrame(name)
+			pad := len(digits)
+			if r.Pad > 0 {
+				pad = r.Pad
+			}
+			nd := strconv.Itoa(nf)
+			if len(nd) < pad {
+				nd = strings.Repeat("0", pad-len(nd)) + nd
+			}
+			renames[seq.Paths[i]] = filepath.Join(filepath.Dir(seq.Paths[i]), seq.Prefix+nd+seq.Ext)
+		}
+	}
+	return renames, nil
+}
+
+// describe는 분석 결과에 보여줄 시퀀스의 새 프레임 범위이다. 번호를 바꾸지 않는다면 빈 문자열이다.
+func (r renumber) describe(seq *sequence) string {
+	desc := ""
+	if r.SetStart || r.Offset != 0 {
+		first := r.frame(seq, seq.Frames[0])
+		last := r.frame(seq, seq.Frames[len(seq.Frames)-1])
+		desc += ", renumbered to " + strconv.Itoa(first) + "-" + strconv.Itoa(last)
+	}
+	if r.Pad > 0 && r.Pad != seq.Pad {
+		desc += ", padded to " + strconv.Itoa(r.Pad)
+	}
+	return desc
+}
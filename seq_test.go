@@ -0,0 +1,207 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectSequences(t *testing.T) {
+	cases := []struct {
+		name    string
+		paths   []string
+		seqs    []string
+		singles []string
+	}{
+		{
+			name: "exr frames",
+			paths: []string{
+				"/show/plate/plate.1002.exr",
+				"/show/plate/plate.1001.exr",
+				"/show/plate/plate.1003.exr",
+			},
+			seqs: []string{"/show/plate/plate.####.exr (1001-1003, 3 frames)"},
+		},
+		{
+			name: "underscore separator",
+			paths: []string{
+				"/show/plate/plate_0001.dpx",
+				"/show/plate/plate_0002.dpx",
+			},
+			seqs: []string{"/show/plate/plate_####.dpx (1-2, 2 frames)"},
+		},
+		{
+			name: "single frame",
+			paths: []string{
+				"/show/plate/plate.1001.exr",
+			},
+			singles: []string{"/show/plate/plate.1001.exr"},
+		},
+		{
+			name: "numbered clips",
+			paths: []string{
+				"/card/ABC_0010.mov",
+				"/card/ABC_0020.mov",
+			},
+			singles: []string{"/card/ABC_0010.mov", "/card/ABC_0020.mov"},
+		},
+		{
+			name: "numbered photos",
+			paths: []string{
+				"/card/IMG_0001.JPG",
+				"/card/IMG_0002.JPG",
+			},
+			singles: []string{"/card/IMG_0001.JPG", "/card/IMG_0002.JPG"},
+		},
+		{
+			name: "too few digits",
+			paths: []string{
+				"/show/take_1.png",
+				"/show/take_2.png",
+			},
+			singles: []string{"/show/take_1.png", "/show/take_2.png"},
+		},
+		{
+			name: "other directory",
+			paths: []string{
+				"/show/a/plate.1001.exr",
+				"/show/b/plate.1002.exr",
+			},
+			singles: []string{"/show/a/plate.1001.exr", "/show/b/plate.1002.exr"},
+		},
+		{
+			name: "mixed padding",
+			paths: []string{
+				"/show/plate.999.tif",
+				"/show/plate.1000.tif",
+			},
+			seqs: []string{"/show/plate.#.tif (999-1000, 2 frames)"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			paths := make([]string, len(c.paths))
+			for i, p := range c.paths {
+				paths[i] = filepath.FromSlash(p)
+			}
+			seqs, singles := detectSequences(paths)
+			got := make([]string, 0)
+			for _, s := range seqs {
+				got = append(got, filepath.ToSlash(s.String()))
+			}
+			want := c.seqs
+			if want == nil {
+				want = []string{}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("seqs = %q, want %q", got, want)
+			}
+			gotSingles := make([]string, 0)
+			for _, p := range singles {
+				gotSingles = append(gotSingles, filepath.ToSlash(p))
+			}
+			wantSingles := c.singles
+			if wantSingles == nil {
+				wantSingles = []string{}
+			}
+			if !reflect.DeepEqual(gotSingles, wantSingles) {
+				t.Errorf("singles = %q, want %q", gotSingles, wantSingles)
+			}
+		})
+	}
+}
+
+func TestSequenceSplit(t *testing.T) {
+	seqs, _ := detectSequences([]string{
+		"/in/S01_0010.1001.exr",
+		"/in/S01_0010.1002.exr",
+		"/in/S01_0010.1003.exr",
+	})
+	if len(seqs) != 1 {
+		t.Fatalf("got %d sequences, want 1", len(seqs))
+	}
+	// 마지막 프레임만 다른 대상 경로로 간다면 나머지 두 프레임만 시퀀스로 남는다.
+	dest := func(path string) string {
+		if filepath.Base(path) == "S01_0010.1003.exr" {
+			return "/b"
+		}
+		return "/a"
+	}
+	parts, singles := seqs[0].split(dest)
+	if len(parts) != 1 || !reflect.DeepEqual(parts[0].Frames, []int{1001, 1002}) {
+		t.Errorf("parts = %v, want one sequence of 1001, 1002", parts)
+	}
+	if !reflect.DeepEqual(singles, []string{"/in/S01_0010.1003.exr"}) {
+		t.Errorf("singles = %q", singles)
+	}
+	same, singles := seqs[0].split(func(string) string { return "/a" })
+	if len(same) != 1 || same[0] != seqs[0] || len(singles) != 0 {
+		t.Errorf("split with one key changed the sequence: %v %q", same, singles)
+	}
+}
+
+func TestSequenceMissing(t *testing.T) {
+	cases := []struct {
+		frames []int
+		want   string
+	}{
+		{[]int{1001, 1002, 1003}, ""},
+		{[]int{1001, 1003}, "1002"},
+		{[]int{1001, 1005, 1006, 1010}, "1002-1004, 1007-1009"},
+	}
+	for _, c := range cases {
+		s := &sequence{Frames: c.frames}
+		if got := s.Missing(); got != c.want {
+			t.Errorf("Missing(%v) = %q, want %q", c.frames, got, c.want)
+		}
+	}
+}
+
+func TestParseRenumber(t *testing.T) {
+	cases := []struct {
+		text    string
+		want    renumber
+		wantErr bool
+	}{
+		{"", renumber{}, false},
+		{"1001", renumber{SetStart: true, Start: 1001}, false},
+		{"+100", renumber{Offset: 100}, false},
+		{"-10", renumber{Offset: -10}, false},
+		{"abc", renumber{}, true},
+	}
+	for _, c := range cases {
+		got, err := parseRenumber(c.text)
+		if (err != nil) != c.wantErr {
+			t.Errorf("parseRenumber(%q) error = %v", c.text, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("parseRenumber(%q) = %+v, want %+v", c.text, got, c.want)
+		}
+	}
+}
+
+func TestRenameFrames(t *testing.T) {
+	files := []srcFile{
+		{Rel: "img.1.exr"},
+		{Rel: "img.2.exr"},
+		{Rel: "img.3.exr"},
+	}
+	r := renumber{SetStart: true, Start: 1001, Pad: 4}
+	got, err := r.renameFrames(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"img.1.exr": "img.1001.exr",
+		"img.2.exr": "img.1002.exr",
+		"img.3.exr": "img.1003.exr",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("renameFrames = %v, want %v", got, want)
+	}
+	_, err = renumber{Offset: -10}.renameFrames(files)
+	if err == nil {
+		t.Error("renameFrames with a negative frame: want error")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"gioui.org/io/key"
+)
+
+// handleShortcuts는 키보드 단축키를 해당하는 버튼을 누른 것으로 바꾼다.
+// 버튼들의 클릭이 처리되기 전에 불러야 한다.
+//
+//	Ctrl/Cmd+Enter  분석하거나, 분석했다면 실행한다. 확인 창에서는 실행을 확인한다.
+//	Esc             확인 창이나 대상 경로 수정을 닫거나, 분석을 취소한다.
+//	Ctrl/Cmd+L      입력한 경로들을 지운다.
+//	Ctrl/Cmd+O      분석 전에는 대상 디렉토리를 고르고, 분석한 뒤에는 첫번째 대상 디렉토리를 연다.
+//	Tab, Shift+Tab  focusOrder의 순서로 포커스를 옮긴다.
+//	Ctrl/Cmd+ +, -  화면을 크게 하거나 작게 한다. Ctrl/Cmd+0은 원래 크기로 되돌린다.
+func (ui *UI) handleShortcuts(gtx C) {
+	for {
+		e, ok := gtx.Event(
+			key.Filter{Name: key.NameReturn, Required: key.ModShortcut},
+			key.Filter{Name: key.NameEnter, Required: key.ModShortcut},
+			key.Filter{Name: key.NameEscape},
+			key.Filter{Name: "L", Required: key.ModShortcut},
+			key.Filter{Name: "O", Required: key.ModShortcut},
+			key.Filter{Name: key.NameTab, Optional: key.ModShift},
+			key.Filter{Name: "+", Required: key.ModShortcut, Optional: key.ModShift},
+			key.Filter{Name: "=", Required: key.ModShortcut, Optional: key.ModShift},
+			key.Filter{Name: "-", Required: key.ModShortcut},
+			key.Filter{Name: "0", Required: key.ModShortcut},
+		)
+		if !ok {
+			break
+		}
+		ke, ok := e.(key.Event)
+		if !ok || ke.State != key.Press || ui.copying() {
+			continue
+		}
+		p := ui.Program
+		switch ke.Name {
+		case key.NameReturn, key.NameEnter:
+			switch {
+			case ui.ConfirmText != "":
+				ui.ConfirmRunButton.Click()
+			case !p.Analyzed:
+				ui.AnalyzeButton.Click()
+			case p.Done:
+				ui.OKButton.Click()
+			case !p.Offline:
+				ui.RunButton.Click()
+			}
+		case key.NameEscape:
+			switch {
+			case ui.ConfirmText != "":
+				ui.ConfirmBackButton.Click()
+			case ui.OverrideSrc != "":
+				ui.CloseDestButton.Click()
+			case p.Analyzed && !p.Done:
+				ui.CancelButton.Click()
+			}
+		case key.NameTab:
+			ui.moveFocus(gtx, ke.Modifiers.Contain(key.ModShift))
+		case "+", "=":
+			ui.zoom(1)
+		case "-":
+			ui.zoom(-1)
+		case "0":
+			ui.zoom(0)
+		case "L":
+			if !p.Analyzed {
+				ui.InputEditor.SetText("")
+			}
+		case "O":
+			if !ui.Locked() {
+				ui.BrowseDestButton.Click()
+				continue
+			}
+			if dd := ui.firstOpenableDest(); dd != "" {
+				err := ui.openResultPath(dd)
+				if err != nil {
+					ui.Notifier.SetText(explainError(err))
+					ui.NotifyIsError = true
+				}
+			}
+		}
+	}
+}
+
+// firstOpenableDest는 열어볼 수 있는 대상 디렉토리 중 이름 순서로 첫번째를 찾는다. 없다면 빈 문자열이다.
+func (ui *UI) firstOpenableDest() string {
+	destDirs := make([]string, 0, len(ui.Program.DestDirSrcs))
+	for dd := range ui.Program.DestDirSrcs {
+		destDirs = append(destDirs, dd)
+	}
+	sortNatural(destDirs)
+	for _, dd := range destDirs {
+		if ui.canOpenDest(dd) {
+			return dd
+		}
+	}
+	return ""
+}
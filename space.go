@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// formatBytes는 바이트 수를 1.5 GB 같이 읽기 쉬운 크기로 나타낸다.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// existingAncestor는 path 또는 그 부모 디렉토리들 중 존재하는 가장 가까운 경로를 찾는다.
+func existingAncestor(path string) (string, error) {
+	path = filepath.Clean(path)
+	for {
+		_, err := safeStat(path)
+		if err == nil {
+			return path, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s", err, path)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", fmt.Errorf("no existing parent: %s", path)
+		}
+		path = parent
+	}
+}
+
+// checkDiskSpace는 복사할 파일들의 크기를 대상 파일시스템별로 합해 빈 공간과 비교하고,
+// 공간이 모자란 파일시스템을 p.NoSpace에 기록한다.
+// 링크는 공간을 쓰지 않으므로 복사 방법이 copy일 때만 검사한다.
+func (p *Program) checkDiskSpace() error {
+	p.NoSpace = make([]string, 0)
+	if p.Method != "copy" {
+		return nil
+	}
+	type fsUsage struct {
+		path    string
+		need    int64
+		free    uint64
+		partial bool
+	}
+	usages := make(map[string]*fsUsage)
+	for destDir, srcs := range p.DestDirSrcs {
+		dir, err := existingAncestor(destDir)
+		if err != nil {
+			return err
+		}
+		id, free, err := diskFree(dir)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, dir)
+		}
+		u := usages[id]
+		if u == nil {
+			u = &fsUsage{path: dir, free: free}
+			usages[id] = u
+		}
+		for _, src := range srcs {
+			u.need += p.SrcBytes[src]
+			if p.SrcCounting[src] {
+				u.partial = true
+			}
+		}
+	}
+	for _, u := range usages {
+		if u.need <= 0 || uint64(u.need) <= u.free {
+			continue
+		}
+		need := formatBytes(u.need)
+		if u.partial {
+			need = "at least " + need
+		}
+		p.NoSpace = append(p.NoSpace, fmt.Sprintf("%s: needs %s, only %s free", u.path, need, formatBytes(int64(u.free))))
+	}
+	sort.Strings(p.NoSpace)
+	return nil
+}
+
+// checkQuota는 복사할 파일들의 크기를 대상 경로에 적용되는 쿼터별로 합해 남은 쿼터와 비교하고,
+// 쿼터를 넘는 대상 경로를 p.OverQuota에 기록한다. 파일시스템의 빈 공간과는 따로 확인한다.
+// 쿼터를 확인할 수 없는 대상 경로는 건너뛴다.
+func (p *Program) checkQuota() error {
+	p.OverQuota = make([]string, 0)
+	if p.Method != "copy" {
+		return nil
+	}
+	type quotaUsage struct {
+		path    string
+		kind    string
+		need    int64
+		left    uint64
+		partial bool
+	}
+	usages := make(map[string]*quotaUsage)
+	for destDir, srcs := range p.DestDirSrcs {
+		dir, err := existingAncestor(destDir)
+		if err != nil {
+			return err
+		}
+		left, kind, ok := quotaLeft(dir)
+		if !ok {
+			continue
+		}
+		id, _, err := diskFree(dir)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, dir)
+		}
+		// 같은 파일시스템의 같은 쿼터를 쓰는 대상 경로들은 함께 센다.
+		key := id + "\x00" + kind
+		u := usages[key]
+		if u == nil {
+			u = &quotaUsage{path: dir, kind: kind, left: left}
+			usages[key] = u
+		}
+		for _, src := range srcs {
+			u.need += p.SrcBytes[src]
+			if p.SrcCounting[src] {
+				u.partial = true
+			}
+		}
+	}
+	for _, u := range usages {
+		if u.need <= 0 || uint64(u.need) <= u.left {
+			continue
+		}
+		need := formatBytes(u.need)
+		if u.partial {
+			need = "at least " + need
+		}
+		p.OverQuota = append(p.OverQuota, fmt.Sprintf("%s: needs %s, only %s left in %s", u.path, need, formatBytes(int64(u.left)), u.kind))
+	}
+	sort.Strings(p.OverQuota)
+	return nil
+}
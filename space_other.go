@@ -0,0 +1,13 @@
+//go:build !windows && !linux && !darwin && !freebsd && !dragonfly && !aix
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// diskFree는 빈 공간을 확인할 수 없는 운영체제에서는 항상 에러를 반환한다.
+func diskFree(path string) (string, uint64, error) {
+	return "", 0, fmt.Errorf("unsupported os: %s", runtime.GOOS)
+}
@@ -0,0 +1,24 @@
+//go:build linux || darwin || freebsd || dragonfly || aix
+
+package main
+
+import (
+	"strconv"
+	"syscall"
+)
+
+// diskFree는 path가 있는 파일시스템의 장치 번호와 사용할 수 있는 빈 공간의 크기를 반환한다.
+// openbsd, netbsd, solaris는 syscall.Statfs의 모양이 달라 space_other.go의 것을 쓴다.
+func diskFree(path string) (string, uint64, error) {
+	var st syscall.Stat_t
+	err := syscall.Stat(path, &st)
+	if err != nil {
+		return "", 0, err
+	}
+	var fs syscall.Statfs_t
+	err = syscall.Statfs(path, &fs)
+	if err != nil {
+		return "", 0, err
+	}
+	return strconv.FormatUint(uint64(st.Dev), 10), uint64(fs.Bavail) * uint64(fs.Bsize), nil
+}
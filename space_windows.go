@@ -0,0 +1,25 @@
+package main
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// diskFree는 path가 있는 볼륨의 이름과 사용할 수 있는 빈 공간의 크기를 반환한다.
+func diskFree(path string) (string, uint64, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return "", 0, err
+	}
+	vol := make([]uint16, windows.MAX_PATH+1)
+	err = windows.GetVolumePathName(p, &vol[0], uint32(len(vol)))
+	if err != nil {
+		return "", 0, err
+	}
+	// 쿼터는 checkQuota에서 따로 확인하므로 사용자별 공간이 아닌 볼륨의 빈 공간을 쓴다.
+	var avail, total, free uint64
+	err = windows.GetDiskFreeSpaceEx(&vol[0], &avail, &total, &free)
+	if err != nil {
+		return "", 0, err
+	}
+	return windows.UTF16ToString(vol), free, nil
+}
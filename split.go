@@ -0,0 +1,80 @@
+package main
+
+import (
+	"image"
+
+	"gioui.org/gesture"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+)
+
+// minSplit과 maxSplit은 분할에서 왼쪽 영역이 차지할 수 있는 비율의 범위이다.
+const (
+	minSplit = 0.2
+	maxSplit = 0.9
+)
+
+// split은 나란히 놓인 두 영역과 그 사이를 끌어서 너비를 나눌 수 있는 손잡이이다.
+type split struct {
+	// Ratio는 전체 너비에서 왼쪽 영역이 차지하는 비율이다. 0이면 오른쪽 영역을 Default의 너비로 둔다.
+	Ratio   float32
+	Default unit.Dp
+	drag    gesture.Drag
+	dragX   float32
+}
+
+// ratio는 전체 너비가 width일 때 왼쪽 영역의 비율이다.
+func (s *split) ratio(gtx C, width int) float32 {
+	r := s.Ratio
+	if r <= 0 && width > 0 {
+		r = 1 - float32(gtx.Dp(s.Default))/float32(width)
+	}
+	return min(max(r, minSplit), maxSplit)
+}
+
+// Layout은 왼쪽과 오른쪽 영역을 손잡이로 나누어 그린다. 손잡이를 끌면 Ratio가 바뀐다.
+func (s *split) Layout(gtx C, left, right layout.Widget) D {
+	width := gtx.Constraints.Max.X
+	bar := gtx.Dp(unit.Dp(6))
+	for {
+		e, ok := s.drag.Update(gtx.Metric, gtx.Source, gesture.Horizontal)
+		if !ok {
+			break
+		}
+		switch e.Kind {
+		case pointer.Press:
+			s.dragX = e.Position.X
+			s.Ratio = s.ratio(gtx, width)
+		case pointer.Drag:
+			if width > 0 {
+				s.Ratio = min(max(s.Ratio+(e.Position.X-s.dragX)/float32(width), minSplit), maxSplit)
+			}
+			s.dragX = e.Position.X
+		}
+	}
+	leftW := max(int(s.ratio(gtx, width)*float32(width))-bar/2, 0)
+	rightX := min(leftW+bar, width)
+	{
+		gtx := gtx
+		gtx.Constraints = layout.Exact(image.Pt(leftW, gtx.Constraints.Max.Y))
+		left(gtx)
+	}
+	{
+		gtx := gtx
+		gtx.Constraints = layout.Exact(image.Pt(width-rightX, gtx.Constraints.Max.Y))
+		defer op.Offset(image.Pt(rightX, 0)).Push(gtx.Ops).Pop()
+		right(gtx)
+	}
+	// 손잡이는 가운데에 선을 그리고, 손잡이 전체에서 끌 수 있게 한다.
+	area := image.Rect(leftW, 0, rightX, gtx.Constraints.Max.Y)
+	line := image.Rect(leftW+bar/2, 0, leftW+bar/2+max(1, gtx.Dp(colors.BorderWidth)), gtx.Constraints.Max.Y)
+	paint.FillShape(gtx.Ops, colors.Border, clip.Rect(line).Op())
+	defer clip.Rect(area).Push(gtx.Ops).Pop()
+	pointer.CursorColResize.Add(gtx.Ops)
+	s.drag.Add(gtx.Ops)
+	return D{Size: image.Pt(width, gtx.Constraints.Max.Y)}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// fileState는 파일이 아직 쓰이고 있는지 비교하기 위한 크기와 수정 시간이다.
+type fileState struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// sourceStates는 복사할 모든 파일의 현재 크기와 수정 시간을 읽는다.
+// 심볼릭 링크를 그대로 재현할 파일은 내용을 읽지 않으므로 제외한다.
+func (p *Program) sourceStates() (map[string]fileState, error) {
+	states := make(map[string]fileState)
+	for _, srcs := range p.DestDirSrcs {
+		for _, src := range srcs {
+			err := p.walkSource(src, func(f srcFile) error {
+				if f.Link != "" {
+					return nil
+				}
+				fi, err := os.Stat(f.Real)
+				if err != nil {
+					return fmt.Errorf("%w: %s", err, f.Real)
+				}
+				states[f.Path] = fileState{Size: fi.Size(), ModTime: fi.ModTime()}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", err, src)
+			}
+		}
+	}
+	return states, nil
+}
+
+// unstableFiles는 wait 동안 크기나 수정 시간이 바뀐 소스 파일들을 찾는다.
+// 렌더나 전송이 아직 끝나지 않아 쓰이고 있는 파일을 반만 복사하지 않기 위함이다.
+func (p *Program) unstableFiles(wait time.Duration) ([]string, error) {
+	before, err := p.sourceStates()
+	if err != nil {
+		return nil, err
+	}
+	time.Sleep(wait)
+	after, err := p.sourceStates()
+	if err != nil {
+		return nil, err
+	}
+	changed := make([]string, 0)
+	for path, st := range before {
+		if now, ok := after[path]; !ok || now != st {
+			changed = append(changed, path)
+		}
+	}
+	// 기다리는 동안 새로 생긴 파일도 아직 쓰이고 있는 것으로 본다.
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sortNatural(changed)
+	return changed, nil
+}
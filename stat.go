@@ -0,0 +1,189 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// statTimeout은 파일 정보를 가져올 때 기다리는 최대 시간이다. 0이면 제한 없이 기다린다.
+// 응답하지 않는 NFS 마운트 때문에 화면 전체가 멈추는 것을 막기 위함이다.
+var statTimeout = 10 * time.Second
+
+// hungMounts는 응답하지 않았던 마운트 지점들이다.
+// 같은 마운트 아래의 경로들은 다음 분석 전까지 다시 기다리지 않고 바로 실패한다.
+var hungMounts sync.Map
+
+// forgetHungMounts는 응답하지 않았던 마운트들을 잊어 다음 확인 때 다시 기다려 보게 한다.
+// 그 사이에 마운트가 바뀌었을 수 있으므로 읽어둔 마운트 목록도 버린다.
+func forgetHungMounts() {
+	hungMounts.Range(func(k, _ any) bool {
+		hungMounts.Delete(k)
+		return true
+	})
+	mountTable.Lock()
+	mountTable.loaded = false
+	mountTable.mounts = nil
+	mountTable.Unlock()
+}
+
+// mount는 /proc/self/mounts의 한 줄로, 마운트 지점과 파일시스템 종류이다.
+type mount struct {
+	Dir  string
+	Type string
+}
+
+// mountTable은 /proc/self/mounts에서 읽은 마운트 목록이다.
+// 경로를 확인할 때마다 다시 읽지 않도록 다음 분석 전까지 유지한다.
+var mountTable struct {
+	sync.Mutex
+	loaded bool
+	mounts []mount
+}
+
+// mounts는 리눅스의 마운트 목록을 반환한다. 목록을 읽을 수 없다면 비어있다.
+func mounts() []mount {
+	mountTable.Lock()
+	defer mountTable.Unlock()
+	if mountTable.loaded {
+		return mountTable.mounts
+	}
+	mountTable.loaded = true
+	data, err := os.ReadFile("/proc/self/mounts")
+	if err != nil {
+		return nil
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		mountTable.mounts = append(mountTable.mounts, mount{Dir: unescapeMount(fields[1]), Type: fields[2]})
+	}
+	return mountTable.mounts
+}
+
+// findMount는 path가 속한 가장 긴 마운트 지점의 마운트를 찾는다. 루트(/) 마운트는 찾지 않는다.
+func findMount(path string) (mount, bool) {
+	path = filepath.Clean(path)
+	best := mount{}
+	for _, m := range mounts() {
+		if m.Dir == "/" || len(m.Dir) <= len(best.Dir) {
+			continue
+		}
+		if path == m.Dir || strings.HasPrefix(path, m.Dir+"/") {
+			best = m
+		}
+	}
+	return best, best.Dir != ""
+}
+
+// staleMountError는 마운트가 제한 시간 안에 응답하지 않았음을 뜻한다.
+type staleMountError struct {
+	Mount string
+}
+
+func (e *staleMountError) Error() string {
+	return "mount not responding: " + e.Mount
+}
+
+// safeStat은 제한 시간 안에 os.Stat의 결과를 반환한다.
+// 제한 시간이 지나면 *staleMountError를 반환하며, 멈춘 os.Stat은 다른 고루틴에 남겨둔다.
+func safeStat(path string) (fs.FileInfo, error) {
+	return statWithTimeout(path, os.Stat)
+}
+
+// safeLstat은 os.Lstat을 쓰는 것 외에는 safeStat과 같다.
+func safeLstat(path string) (fs.FileInfo, error) {
+	return statWithTimeout(path, os.Lstat)
+}
+
+func statWithTimeout(path string, stat func(string) (fs.FileInfo, error)) (fs.FileInfo, error) {
+	return withTimeout(path, func() (fs.FileInfo, error) {
+		return stat(path)
+	})
+}
+
+// safeReadDir은 제한 시간 안에 os.ReadDir의 결과를 반환한다.
+func safeReadDir(dir string) ([]fs.DirEntry, error) {
+	return withTimeout(dir, func() ([]fs.DirEntry, error) {
+		return os.ReadDir(dir)
+	})
+}
+
+// safeReadlink는 제한 시간 안에 os.Readlink의 결과를 반환한다.
+func safeReadlink(path string) (string, error) {
+	return withTimeout(path, func() (string, error) {
+		return os.Readlink(path)
+	})
+}
+
+// withTimeout은 path가 속한 마운트에 접근하는 fn을 statTimeout 안에 끝낸다.
+// 제한 시간이 지나면 *staleMountError를 반환하며, 멈춘 fn은 다른 고루틴에 남겨둔다.
+func withTimeout[T any](path string, fn func() (T, error)) (T, error) {
+	if statTimeout <= 0 {
+		return fn()
+	}
+	var zero T
+	mount := mountPoint(path)
+	if _, hung := hungMounts.Load(mount); hung {
+		return zero, &staleMountError{Mount: mount}
+	}
+	type result struct {
+		v   T
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		v, err := fn()
+		ch <- result{v, err}
+	}()
+	select {
+	case r := <-ch:
+		return r.v, r.err
+	case <-time.After(statTimeout):
+		hungMounts.Store(mount, true)
+		return zero, &staleMountError{Mount: mount}
+	}
+}
+
+// mountPoint는 path가 속한 마운트 지점을 찾는다.
+// 리눅스에서는 /proc/self/mounts에서 가장 긴 마운트 지점을 찾고,
+// 그 외에는 볼륨 이름이나 /mnt/xyz 같은 처음 두 경로 요소를 쓴다.
+func mountPoint(path string) string {
+	path = filepath.Clean(path)
+	if m, ok := findMount(path); ok {
+		return m.Dir
+	}
+	if vol := filepath.VolumeName(path); vol != "" {
+		return vol
+	}
+	parts := strings.SplitN(strings.TrimLeft(filepath.ToSlash(path), "/"), "/", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return filepath.FromSlash("/" + strings.Join(parts, "/"))
+}
+
+// unescapeMount는 /proc/self/mounts에서 \040 처럼 8진수로 표시된 문자들을 되돌린다.
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// srcStats는 소스 하나(디렉토리라면 그 안의 파일들)의 가장 큰 파일과 수정 시간의 범위이다.
+type srcStats struct {
+	Largest      string
+	LargestBytes int64
+	Oldest       time.Time
+	Newest       time.Time
+}
+
+// fileStats는 파일 하나의 srcStats이다.
+func fileStats(path string, fi fs.FileInfo) srcStats {
+	return srcStats{
+		Largest:      path,
+		LargestBytes: fi.Size(),
+		Oldest:       fi.ModTime(),
+		Newest:       fi.ModTime(),
+	}
+}
+
+// add는 다른 소스의 통계 o를 s에 합친다.
+func (s *srcStats) add(o srcStats) {
+	if o.Largest != "" && (s.Largest == "" || o.LargestBytes > s.LargestBytes) {
+		s.Largest = o.Largest
+		s.LargestBytes = o.LargestBytes
+	}
+	if !o.Oldest.IsZero() && (s.Oldest.IsZero() || o.Oldest.Before(s.Oldest)) {
+		s.Oldest = o.Oldest
+	}
+	if o.Newest.After(s.Newest) {
+		s.Newest = o.Newest
+	}
+}
+
+// destSummary는 대상 디렉토리 destDir에 복사될 파일의 수, 전체 크기, 가장 큰 파일, 소스의 수정 시간 범위를 요약한다.
+// 아직 세고 있는 디렉토리 소스가 있다면 지금까지 센 것만 합한다.
+func (p *Program) destSummary(destDir string) string {
+	files := 0
+	var bytes int64
+	var st srcStats
+	partial := false
+	for _, src := range p.DestDirSrcs[destDir] {
+		if p.SrcIsDir[src] {
+			if p.SrcCounting[src] {
+				partial = true
+				continue
+			}
+			files += p.SrcDirFileCount[src]
+		} else {
+			files++
+		}
+		bytes += p.SrcBytes[src]
+		st.add(p.SrcStats[src])
+	}
+	plural := ""
+	if files != 1 {
+		plural = "s"
+	}
+	line := strconv.Itoa(files) + " file" + plural + ", " + formatBytes(bytes)
+	if partial {
+		line = "at least " + line
+	}
+	if st.Largest != "" {
+		line += ", largest " + filepath.Base(st.Largest) + " (" + formatBytes(st.LargestBytes) + ")"
+	}
+	if !st.Oldest.IsZero() {
+		const layout = "2006-01-02 15:04"
+		oldest, newest := st.Oldest.Local().Format(layout), st.Newest.Local().Format(layout)
+		if oldest == newest {
+			line += ", modified " + oldest
+		} else {
+			line += ", modified " + oldest + " to " + newest
+		}
+	}
+	return line
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Suggestion은 샘플 경로들로부터 추정한 경로 분리 설정이다.
+// 각 값은 그대로 해당 에디터에 들어갈 수 있는 형식이다.
+type Suggestion struct {
+	PathSeps string
+	PathKeys string
+	NameSeps string
+	NameKeys string
+}
+
+var (
+	verToken   = regexp.MustCompile(`^[vV]\d+$`)
+	frameToken = regexp.MustCompile(`^\d{3,}$`)
+)
+
+// suggestSplit은 샘플 경로들을 살펴 그럴듯한 구분자와 키 배치를 제안한다.
+//
+// 모든 샘플에서 같은 토큰은 _ 로, 버전이나 프레임, 확장자처럼 보이는 토큰은 VER, FRAME, EXT로,
+// 나머지는 DIR1, KEY1 같은 임시 이름으로 채운다. 임시 이름은 사용자가 원하는 이름으로 바꿔야 한다.
+// 샘플마다 토큰 수가 다르다면 ... 를 사용해 왼쪽과 오른쪽 끝을 기준으로 키를 배치한다.
+func suggestSplit(paths []string) (Suggestion, error) {
+	if len(paths) == 0 {
+		return Suggestion{}, fmt.Errorf("no sample paths")
+	}
+	sug := Suggestion{}
+	// 경로 구분자
+	pathSep := "/"
+	for _, p := range paths {
+		if strings.Contains(p, "\\") && !strings.Contains(p, "/") {
+			pathSep = "\\"
+			break
+		}
+	}
+	sug.PathSeps = pathSep
+	pathToks := make([][]string, 0, len(paths))
+	for _, p := range paths {
+		pathToks = append(pathToks, splitTokens(p, []string{pathSep}))
+	}
+	sug.PathKeys = strings.Join(suggestKeys(pathToks, "DIR", "NAME"), " ")
+	// 이름 구분자: 모든 샘플의 이름에 들어있는 문자들을 많이 쓰인 순서로 고른다.
+	names := make([]string, 0, len(paths))
+	for _, p := range paths {
+		names = append(names, baseName(p))
+	}
+	count := make(map[string]int)
+	for _, cand := range []string{"_", ".", "-"} {
+		for _, n := range names {
+			c := strings.Count(n, cand)
+			if c == 0 {
+				count[cand] = 0
+				break
+			}
+			count[cand] += c
+		}
+	}
+	nameSeps := make([]string, 0)
+	for cand, c := range count {
+		if c > 0 {
+			nameSeps = append(nameSeps, cand)
+		}
+	}
+	sort.Slice(nameSeps, func(i, j int) bool {
+		if count[nameSeps[i]] != count[nameSeps[j]] {
+			return count[nameSeps[i]] > count[nameSeps[j]]
+		}
+		return nameSeps[i] < nameSeps[j]
+	})
+	sug.NameSeps = strings.Join(nameSeps, " ")
+	nameToks := make([][]string, 0, len(names))
+	for _, n := range names {
+		nameToks = append(nameToks, splitTokens(n, nameSeps))
+	}
+	last := "LAST"
+	if count["."] > 0 {
+		last = "EXT"
+	}
+	sug.NameKeys = strings.Join(suggestKeys(nameToks, "KEY", last), " ")
+	return sug, nil
+}
+
+// suggestKeys는 샘플들의 토큰 목록으로 키 배치를 만든다.
+// 마지막 토큰에는 항상 last 키를 쓰고, 그 외 이름을 추측할 수 없는 토큰에는 prefix에 번호를 붙인 키를 쓴다.
+func suggestKeys(toks [][]string, prefix, last string) []string {
+	minN, maxN := len(toks[0]), len(toks[0])
+	for _, t := range toks {
+		if len(t) < minN {
+			minN = len(t)
+		}
+		if len(t) > maxN {
+			maxN = len(t)
+		}
+	}
+	if minN == 0 {
+		return nil
+	}
+	used := make(map[string]bool)
+	unique := func(k string) string {
+		if !used[k] {
+			used[k] = true
+			return k
+		}
+		for i := 2; ; i++ {
+			kk := k + strconv.Itoa(i)
+			if !used[kk] {
+				used[kk] = true
+				return kk
+			}
+		}
+	}
+	keys := make([]string, 0, minN)
+	n := 0
+	for i := 0; i < minN-1; i++ {
+		col := make([]string, 0, len(toks))
+		for _, t := range toks {
+			col = append(col, t[i])
+		}
+		switch {
+		case allSame(col) && (len(toks) > 1 || col[0] == ""):
+			// 샘플이 하나뿐이라면 모든 토큰이 같아 보이므로 빈 토큰만 버린다.
+			keys = append(keys, "_")
+		case allMatch(col, verToken):
+			keys = append(keys, unique("VER"))
+		case allMatch(col, frameToken) && i == minN-2 && minN == maxN:
+			keys = append(keys, unique("FRAME"))
+		default:
+			n++
+			keys = append(keys, unique(prefix+strconv.Itoa(n)))
+		}
+	}
+	if minN != maxN {
+		// 토큰 수가 다르면 오른쪽 끝은 ... 뒤에 둔다.
+		for len(keys) > 0 && keys[len(keys)-1] == "_" {
+			keys = keys[:len(keys)-1]
+		}
+		keys = append(keys, "...")
+	}
+	keys = append(keys, unique(last))
+	return keys
+}
+
+func allSame(vals []string) bool {
+	for _, v := range vals {
+		if v != vals[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func allMatch(vals []string, re *regexp.Regexp) bool {
+	for _, v := range vals {
+		if !re.MatchString(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"gioui.org/x/richtext"
+)
+
+// thumbSize는 썸네일의 긴 변의 길이(픽셀)이다.
+const thumbSize = 64
+
+// thumbDecodeExts는 ffmpeg 없이 바로 읽을 수 있는 이미지 확장자들이다.
+var thumbDecodeExts = map[string]bool{
+	"jpg": true, "jpeg": true, "png": true, "gif": true,
+}
+
+// thumbFFmpegExts는 ffmpeg로 첫 프레임을 읽어 썸네일을 만드는 확장자들이다.
+var thumbFFmpegExts = map[string]bool{
+	"exr": true, "dpx": true, "tif": true, "tiff": true, "tga": true, "bmp": true, "webp": true,
+	"mov": true, "mp4": true, "m4v": true, "mxf": true, "mkv": true, "avi": true, "mts": true, "webm": true,
+}
+
+// thumbExt는 path의 소문자 확장자이다.
+func thumbExt(path string) string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// canThumb은 path의 썸네일을 만들 수 있는지 확장자로 확인한다.
+func canThumb(path string) bool {
+	ext := thumbExt(path)
+	return thumbDecodeExts[ext] || thumbFFmpegExts[ext]
+}
+
+// makeThumb은 이미지나 비디오 파일 path의 썸네일을 만든다.
+// JPEG, PNG, GIF는 직접 읽고, 그 외에는 ffmpeg로 첫 프레임을 줄여서 읽는다.
+func makeThumb(ffmpeg, path string) (image.Image, error) {
+	if thumbDecodeExts[thumbExt(path)] {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		img, _, err := image.Decode(f)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, path)
+		}
+		return shrinkImage(img, thumbSize), nil
+	}
+	if ffmpeg == "" {
+		ffmpeg = "ffmpeg"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	scale := fmt.Sprintf("scale=w=%d:h=%d:force_original_aspect_ratio=decrease", thumbSize, thumbSize)
+	cmd := exec.CommandContext(ctx, ffmpeg, "-v", "error", "-i", path, "-frames:v", "1", "-vf", scale, "-f", "image2pipe", "-c:v", "png", "-")
+	out, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("ffmpeg not found: %s", ffmpeg)
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("ffmpeg timed out: %s", path)
+		}
+		return nil, fmt.Errorf("ffmpeg: %w: %s", err, path)
+	}
+	img, _, err := image.Decode(bytes.NewReader(out))
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w: %s", err, path)
+	}
+	return img, nil
+}
+
+// shrinkImage는 img의 긴 변이 size 이하가 되도록 가장 가까운 픽셀을 골라 줄인다.
+func shrinkImage(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+	tw, th := size, h*size/w
+	if h > w {
+		tw, th = w*size/h, size
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		for x := 0; x < tw; x++ {
+			dst.Set(x, y, img.At(b.Min.X+x*w/tw, b.Min.Y+y*h/th))
+		}
+	}
+	return dst
+}
+
+// thumbnail은 소스 하나의 썸네일이다.
+type thumbnail struct {
+	Path string
+	Op   paint.ImageOp
+	Err  error
+}
+
+// requestThumb은 path의 썸네일을 아직 만들지 않았다면 다른 고루틴에서 만든다.
+// 만든 썸네일은 takeThumbs가 받는다. 같은 저장소를 동시에 너무 많이 읽지 않도록 한번에 몇개씩만 만든다.
+func (ui *UI) requestThumb(path string) {
+	if _, ok := ui.thumbs[path]; ok {
+		return
+	}
+	ui.thumbs[path] = nil
+	ffmpeg := ui.Config.FFmpeg
+	go func() {
+		ui.thumbSlots <- struct{}{}
+		img, err := makeThumb(ffmpeg, path)
+		<-ui.thumbSlots
+		t := &thumbnail{Path: path, Err: err}
+		if err == nil {
+			t.Op = paint.NewImageOp(img)
+		}
+		ui.thumbDone <- t
+		ui.Window.Invalidate()
+	}()
+}
+
+// takeThumbs는 그동안 만들어진 썸네일들을 받는다.
+func (ui *UI) takeThumbs() {
+	for {
+		select {
+		case t := <-ui.thumbDone:
+			if _, ok := ui.thumbs[t.Path]; ok {
+				ui.thumbs[t.Path] = t
+			}
+		default:
+			return
+		}
+	}
+}
+
+// thumbSources는 분석 화면에 보이는 경로와 그 썸네일을 만들 파일을 잇는다.
+// 시퀀스는 첫 프레임으로 썸네일을 만든다.
+func thumbSources(p *Program) map[string]string {
+	srcs := make(map[string]string)
+	for src := range p.DestDir {
+		if seq := p.SrcSeq[src]; seq != nil {
+			if canThumb(seq.Paths[0]) {
+				srcs[filepath.Join(seq.Dir, seq.Name())] = seq.Paths[0]
+			}
+			continue
+		}
+		if !p.SrcIsDir[src] && canThumb(src) {
+			srcs[src] = src
+		}
+	}
+	return srcs
+}
+
+// splitSpanLines는 스타일을 유지한 채로 spans를 줄 단위로 나눈다.
+// 빈 줄은 높이를 유지하도록 공백 하나로 둔다.
+func splitSpanLines(spans []richtext.SpanStyle) [][]richtext.SpanStyle {
+	lines := make([][]richtext.SpanStyle, 0)
+	cur := make([]richtext.SpanStyle, 0)
+	for _, s := range spans {
+		parts := strings.Split(s.Content, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				if len(cur) == 0 {
+					blank := s
+					blank.Content = " "
+					cur = append(cur, blank)
+				}
+				lines = append(lines, cur)
+				cur = make([]richtext.SpanStyle, 0)
+			}
+			if part != "" {
+				piece := s
+				piece.Content = part
+				cur = append(cur, piece)
+			}
+		}
+	}
+	if len(cur) != 0 {
+		lines = append(lines, cur)
+	}
+	return lines
+}
+
+// layoutResultLines는 분석 화면을 줄 단위로 그리고, 이미지나 비디오 소스의 줄 앞에는 썸네일을,
+// 대상 디렉토리 묶음의 첫 줄 옆에는 그 디렉토리를 여는 버튼을 그린다.
+func (ui *UI) layoutResultLines(gtx C) D {
+	lines := splitSpanLines(ui.Result)
+	for len(ui.ResultLines) < len(lines) {
+		ui.ResultLines = append(ui.ResultLines, new(richtext.InteractiveText))
+	}
+	srcs := make(map[string]string)
+	if ui.ThumbCheck.Value && !ui.Program.Done {
+		srcs = thumbSources(ui.Program)
+	}
+	return material.List(ui.Theme, ui.List).Layout(gtx, len(lines), func(gtx C, i int) D {
+		text := func(gtx C) D {
+			return richtext.Text(ui.ResultLines[i], ui.Theme.Shaper, lines[i]...).Layout(gtx)
+		}
+		if dd := lineDest(lines[i]); dd != "" && ui.canOpenDest(dd) {
+			return ui.layoutDestLine(gtx, dd, text)
+		}
+		path := ""
+		for _, s := range lines[i] {
+			if p, ok := srcs[s.Content]; ok {
+				path = p
+				break
+			}
+		}
+		if path == "" {
+			return text(gtx)
+		}
+		ui.requestThumb(path)
+		return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+			layout.Rigid(func(gtx C) D {
+				size := gtx.Dp(unit.Dp(thumbSize))
+				gtx.Constraints = layout.Exact(image.Pt(size, size))
+				t := ui.thumbs[path]
+				if t == nil || t.Err != nil {
+					return D{Size: gtx.Constraints.Min}
+				}
+				return widget.Image{Src: t.Op, Fit: widget.Contain, Position: layout.W}.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(4)}.Layout),
+			layout.Flexed(1, text),
+		)
+	})
+}
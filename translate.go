@@ -0,0 +1,115 @@
+package main
+
+import (
+	"runtime"
+	"strings"
+)
+
+// PathTranslation은 운영체제별로 다르게 마운트된 같은 저장소의 루트 경로들이다.
+// 키는 runtime.GOOS의 값(darwin, linux, windows)이다.
+//
+//	darwin = "/Volumes/storm"
+//	linux = "/mnt/storm"
+//	windows = 'S:\'
+type PathTranslation map[string]string
+
+// isWindowsRoot는 root가 드라이브 문자나 UNC 경로처럼 윈도우즈 형식의 경로인지 확인한다.
+func isWindowsRoot(root string) bool {
+	return strings.HasPrefix(root, `\\`) || (len(root) >= 2 && root[1] == ':')
+}
+
+// translatePath는 다른 운영체제의 루트로 시작하는 path를 goos의 루트로 바꾼다.
+// 나머지 경로의 구분자도 goos에 맞게 바꾼다. 맞는 루트가 없다면 path를 그대로 반환한다.
+func translatePath(path string, trans []PathTranslation, goos string) string {
+	for _, t := range trans {
+		local, ok := t[goos]
+		if !ok {
+			continue
+		}
+		for sys, root := range t {
+			if sys == goos || root == "" {
+				continue
+			}
+			rest, ok := cutRoot(path, root)
+			if !ok {
+				continue
+			}
+			if goos == "windows" {
+				rest = strings.ReplaceAll(rest, "/", `\`)
+			} else if isWindowsRoot(root) {
+				rest = strings.ReplaceAll(rest, `\`, "/")
+			}
+			sep := "/"
+			if goos == "windows" {
+				sep = `\`
+			}
+			local = strings.TrimRight(local, `/\`)
+			rest = strings.TrimLeft(rest, `/\`)
+			if rest == "" {
+				return local + sep
+			}
+			return local + sep + rest
+		}
+	}
+	return path
+}
+
+// cutRoot는 path가 root로 시작한다면 나머지 부분을 반환한다.
+// root가 경로 요소의 일부에만 맞는다면 (/mnt/storm 과 /mnt/stormy) 맞지 않는 것으로 본다.
+// 윈도우즈 형식의 루트는 대소문자를 구분하지 않는다.
+func cutRoot(path, root string) (string, bool) {
+	root = strings.TrimRight(root, `/\`)
+	if len(path) < len(root) {
+		return "", false
+	}
+	head := path[:len(root)]
+	if isWindowsRoot(root) {
+		if !strings.EqualFold(head, root) {
+			return "", false
+		}
+	} else if head != root {
+		return "", false
+	}
+	rest := path[len(root):]
+	if rest != "" && rest[0] != '/' && rest[0] != '\\' {
+		return "", false
+	}
+	return rest, true
+}
+
+// localPath는 path를 이 컴퓨터의 운영체제에 맞는 경로로 바꾼다.
+func (p *Program) localPath(path string) string {
+	return translatePath(path, p.Translations, runtime.GOOS)
+}
+
+// Rewrite는 입력 경로의 앞부분 From을 To로 바꾸는 규칙이다.
+// 더이상 쓰이지 않는 마운트 위치를 가리키는 경로를 현재의 위치로 바꾸기 위함이다.
+type Rewrite struct {
+	From string
+	To   string
+}
+
+// sourcePath는 입력 경로를 이 컴퓨터의 운영체제에 맞게 바꾸고, 바꾼 경로에 처음으로 맞는 Rewrite 규칙을 적용한다.
+func (p *Program) sourcePath(path string) string {
+	path = p.localPath(strings.TrimSpace(path))
+	for _, r := range p.Rewrites {
+		if r.From == "" {
+			continue
+		}
+		rest, ok := cutRoot(path, r.From)
+		if !ok {
+			continue
+		}
+		return strings.TrimRight(r.To, `/\`) + rest
+	}
+	return path
+}
+
+// sourcePaths는 입력 경로들에 sourcePath를 적용한다.
+func (p *Program) sourcePaths(paths []string) []string {
+	srcs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		srcs = append(srcs, p.sourcePath(path))
+	}
+	return srcs
+}
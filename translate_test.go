@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func TestTranslatePath(t *testing.T) {
+	trans := []PathTranslation{{
+		"darwin":  "/Volumes/storm",
+		"linux":   "/mnt/storm",
+		"windows": `S:\`,
+	}}
+	cases := []struct {
+		path string
+		goos string
+		want string
+	}{
+		{"/Volumes/storm/show/abc", "linux", "/mnt/storm/show/abc"},
+		{`S:\show\abc`, "linux", "/mnt/storm/show/abc"},
+		{`s:\show\abc`, "darwin", "/Volumes/storm/show/abc"},
+		{"/mnt/storm/show/abc", "windows", `S:\show\abc`},
+		{"/mnt/storm", "windows", `S:\`},
+		{"/mnt/storm/show/abc", "linux", "/mnt/storm/show/abc"},
+		// 경로 요소의 일부에만 맞는 루트는 바꾸지 않는다.
+		{"/mnt/stormy/show", "darwin", "/mnt/stormy/show"},
+		{"/other/show", "windows", "/other/show"},
+	}
+	for _, c := range cases {
+		if got := translatePath(c.path, trans, c.goos); got != c.want {
+			t.Errorf("translatePath(%q, %s) = %q, want %q", c.path, c.goos, got, c.want)
+		}
+	}
+}
+
+func TestCutRoot(t *testing.T) {
+	cases := []struct {
+		path string
+		root string
+		rest string
+		ok   bool
+	}{
+		{"/mnt/storm/show", "/mnt/storm", "/show", true},
+		{"/mnt/storm/show", "/mnt/storm/", "/show", true},
+		{"/mnt/storm", "/mnt/storm", "", true},
+		{"/mnt/stormy", "/mnt/storm", "", false},
+		{"/MNT/storm/show", "/mnt/storm", "", false},
+		{`s:\show`, `S:\`, `\show`, true},
+		{"/mnt", "/mnt/storm", "", false},
+	}
+	for _, c := range cases {
+		rest, ok := cutRoot(c.path, c.root)
+		if rest != c.rest || ok != c.ok {
+			t.Errorf("cutRoot(%q, %q) = %q, %v, want %q, %v", c.path, c.root, rest, ok, c.rest, c.ok)
+		}
+	}
+}
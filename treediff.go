@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// treeDiff는 소스 디렉토리와 그것이 복사된 대상 디렉토리를 비교한 결과로, 모두 상대 경로이다.
+type treeDiff struct {
+	// Missing은 소스에는 있지만 대상에는 없는 파일들이다.
+	Missing []string
+	// Extra는 대상에만 있는 파일들이다.
+	Extra []string
+	// SizeDiffers는 양쪽에 모두 있지만 크기가 다른 파일들로, 두 크기를 함께 적는다.
+	SizeDiffers []string
+}
+
+// count는 차이의 수이다.
+func (d *treeDiff) count() int {
+	return len(d.Missing) + len(d.Extra) + len(d.SizeDiffers)
+}
+
+// lines는 차이들을 한 줄에 하나씩 나타낸다.
+func (d *treeDiff) lines() []string {
+	lines := make([]string, 0, d.count())
+	for _, rel := range d.Missing {
+		lines = append(lines, "missing: "+rel)
+	}
+	for _, rel := range d.SizeDiffers {
+		lines = append(lines, "size differs: "+rel)
+	}
+	for _, rel := range d.Extra {
+		lines = append(lines, "extra: "+rel)
+	}
+	return lines
+}
+
+// treeFiles는 dir 안의 파일들의 상대 경로와 크기이다. 심볼릭 링크는 따라가지 않고 크기를 -1로 둔다.
+func treeFiles(dir string) (map[string]int64, error) {
+	files := make(map[string]int64)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			files[rel] = -1
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files[rel] = fi.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// diffTrees는 소스 디렉토리 src와 대상 디렉토리 dest 안의 파일들을 비교한다.
+// 예전 작업이 제대로 복사되었는지 확인하기 위한 것으로, 이름과 크기만 비교한다.
+func diffTrees(src, dest string) (*treeDiff, error) {
+	srcFiles, err := treeFiles(src)
+	if err != nil {
+		return nil, err
+	}
+	destFiles, err := treeFiles(dest)
+	if err != nil {
+		return nil, err
+	}
+	d := &treeDiff{
+		Missing:     make([]string, 0),
+		Extra:       make([]string, 0),
+		SizeDiffers: make([]string, 0),
+	}
+	for rel, size := range srcFiles {
+		destSize, ok := destFiles[rel]
+		if !ok {
+			d.Missing = append(d.Missing, rel)
+			continue
+		}
+		if size != destSize && size >= 0 && destSize >= 0 {
+			d.SizeDiffers = append(d.SizeDiffers, fmt.Sprintf("%s (%d != %d bytes)", rel, size, destSize))
+		}
+	}
+	for rel := range destFiles {
+		if _, ok := srcFiles[rel]; !ok {
+			d.Extra = append(d.Extra, rel)
+		}
+	}
+	sortNatural(d.Missing)
+	sortNatural(d.Extra)
+	sortNatural(d.SizeDiffers)
+	return d, nil
+}
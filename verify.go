@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// archiveResult는 보관된 파일 하나를 다시 확인한 결과이다.
+type archiveResult struct {
+	Path string
+	// Status는 "ok", "changed", "missing" 또는 읽지 못한 이유이다.
+	Status string
+}
+
+// verifyArchive는 작업 기록에 해시와 함께 남은 파일들 중 dir 안으로 복사된 파일들을 다시 해시해
+// 복사할 때의 해시와 비교한다. 오래 보관된 저장소에서 파일이 손상되지 않았는지 확인하기 위한 것이다.
+// 같은 경로로 여러번 복사되었다면 가장 최근의 기록과 비교한다. 결과는 경로 순서로 w에도 쓴다.
+func verifyArchive(recs []*IngestRecord, dir string, w io.Writer) ([]archiveResult, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	type stored struct {
+		hash string
+		time time.Time
+	}
+	latest := make(map[string]stored)
+	for _, rec := range recs {
+		for _, f := range rec.Files {
+			if f.Hash == "" || f.Dest == "" || !isWithin(f.Dest, dir) {
+				continue
+			}
+			if prev, ok := latest[f.Dest]; !ok || !rec.Time.Before(prev.time) {
+				latest[f.Dest] = stored{hash: f.Hash, time: rec.Time}
+			}
+		}
+	}
+	paths := make([]string, 0, len(latest))
+	for path := range latest {
+		paths = append(paths, path)
+	}
+	sortNatural(paths)
+	results := make([]archiveResult, 0, len(paths))
+	for _, path := range paths {
+		status := "ok"
+		hash, err := hashFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			status = "missing"
+		case err != nil:
+			status = err.Error()
+		case hash != latest[path].hash:
+			status = "changed"
+		}
+		results = append(results, archiveResult{Path: path, Status: status})
+		fmt.Fprintf(w, "%s: %s\n", status, path)
+	}
+	return results, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// KeyRule은 한 키가 가질 수 있는 값들이다.
+// Values와 Pattern이 모두 설정되었다면 둘 중 하나에 맞으면 된다.
+type KeyRule struct {
+	// Values는 허용되는 값들이다.
+	Values []string
+	// Pattern은 값 전체가 맞아야 하는 정규식이다.
+	Pattern string
+
+	re *regexp.Regexp
+}
+
+// compileVocabulary는 키별 규칙들의 정규식을 분석해 둔 복사본을 반환한다.
+func compileVocabulary(vocab map[string]KeyRule) (map[string]KeyRule, error) {
+	compiled := make(map[string]KeyRule, len(vocab))
+	for k, r := range vocab {
+		if r.Pattern != "" {
+			re, err := regexp.Compile(`^(?:` + r.Pattern + `)$`)
+			if err != nil {
+				return nil, fmt.Errorf("vocabulary %s: %v", k, err)
+			}
+			r.re = re
+		}
+		compiled[k] = r
+	}
+	return compiled, nil
+}
+
+// allow는 v가 규칙에 맞는지 확인한다.
+func (r KeyRule) allow(v string) bool {
+	for _, a := range r.Values {
+		if v == a {
+			return true
+		}
+	}
+	return r.re != nil && r.re.MatchString(v)
+}
+
+// checkVocabulary는 분석한 키 값들이 설정된 규칙에 맞는지 확인한다.
+// 분석되지 않은 키는 검사하지 않는다.
+func (p *Program) checkVocabulary(env map[string]string) error {
+	keys := make([]string, 0, len(p.Vocabulary))
+	for k := range p.Vocabulary {
+		keys = append(keys, k)
+	}
+	// 여러 키가 틀렸을 때 항상 같은 키를 알려주도록 정렬한다.
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, ok := env[k]
+		if !ok {
+			continue
+		}
+		r := p.Vocabulary[k]
+		if !r.allow(v) {
+			return fmt.Errorf("%s %q is not an allowed value", k, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// defaultWaitInterval은 없는 소스가 생겼는지 다시 확인하기까지 기다리는 기본 시간이다.
+const defaultWaitInterval = 5 * time.Second
+
+// appearedPaths는 paths 중 지금은 존재하는 경로들을 반환한다.
+// 글롭 패턴은 맞는 경로가 하나라도 생겼을 때, 일반 경로는 다른 정규화 형식으로라도 생겼을 때 존재하는 것으로 본다.
+func appearedPaths(paths []string) []string {
+	found := make([]string, 0)
+	for _, path := range paths {
+		if hasGlobMeta(path) {
+			if matches, err := expandGlob(path); err == nil && len(matches) != 0 {
+				found = append(found, path)
+			}
+			continue
+		}
+		if ok, _ := statExists(existingForm(path, statExists)); ok {
+			found = append(found, path)
+		}
+	}
+	return found
+}
+
+// watchMissing은 ctx가 취소될 때까지 interval마다 paths가 생겼는지 확인하고,
+// 하나라도 생기면 생긴 경로들을 found로 보낸 뒤 notify를 부르고 멈춘다.
+func watchMissing(ctx context.Context, paths []string, interval time.Duration, found chan<- []string, notify func()) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		appeared := appearedPaths(paths)
+		if len(appeared) == 0 || ctx.Err() != nil {
+			continue
+		}
+		found <- appeared
+		notify()
+		return
+	}
+}
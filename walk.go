@@ -0,0 +1,263 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// srcFile은 소스 안에서 찾은 복사할 파일 하나의 정보이다.
+type srcFile struct {
+	// Path는 소스 경로를 기준으로 조립된 파일 경로이다.
+	Path string
+	// Rel은 소스의 부모 디렉토리를 기준으로 한 상대 경로이다.
+	// 디렉토리 소스라면 소스 디렉토리 이름으로 시작한다.
+	Rel string
+	// Real은 실제로 읽어야 할 파일 경로이다.
+	// 심볼릭 링크를 따라가는 경우 링크가 가리키는 파일이다.
+	Real string
+	// Link는 심볼릭 링크를 그대로 재현할 때 만들 링크의 대상이다.
+	// 비어있다면 Real의 내용을 복사(또는 링크)한다.
+	Link string
+}
+
+// walkSource는 소스 경로 안의 복사할 파일들을 방문한다.
+// 디렉토리가 아닌 소스는 그 자신만 방문한다.
+//
+// 심볼릭 링크는 p.Symlinks에 따라 처리된다.
+// "keep"이면 링크를 따라가지 않고 대상 경로에 같은 링크를 만들도록 하고,
+// 그 외에는 링크를 따라가 그 내용을 복사한다.
+// 링크를 따라갈 때 상위 디렉토리를 다시 가리키는 링크는 무한 반복을 막기 위해 건너뛴다.
+//
+// 디렉토리 소스 안에서 p.Excludes에 맞는 파일과 디렉토리, p.Includes에 맞지 않는 파일,
+// p.MaxDepth보다 깊은 디렉토리, p.SkipHidden일 때 숨김 파일은 방문하지 않고, 그 수를 p.SrcExcluded에 센다.
+//
+// fn이 fs.SkipAll을 반환하면 방문을 멈추고 nil을 반환한다.
+func (p *Program) walkSource(src string, fn func(f srcFile) error) error {
+	fi, err := safeStat(src)
+	if err != nil {
+		return err
+	}
+	if p.SrcExcluded == nil {
+		p.SrcExcluded = make(map[string]int)
+	}
+	p.SrcExcluded[src] = 0
+	if fi.IsDir() {
+		real, rerr := filepath.EvalSymlinks(src)
+		if rerr != nil {
+			return rerr
+		}
+		err = p.walkSourceDir(src, src, filepath.Base(src), 0, map[string]bool{real: true}, fn)
+	} else {
+		err = p.visitSrcFile(src, filepath.Base(src), fn)
+	}
+	if errors.Is(err, fs.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+// walkSourceDir는 소스 src 안의 디렉토리 dir 안의 파일들을 재귀적으로 방문한다.
+// depth는 src로부터 dir의 깊이로, src 자신은 0이다.
+// parents는 지금까지 지나온 디렉토리들의 실제 경로로, 링크 반복을 찾는데 쓰인다.
+func (p *Program) walkSourceDir(src, dir, rel string, depth int, parents map[string]bool, fn func(f srcFile) error) error {
+	// p.MaxDepth보다 깊은 디렉토리에는 들어가지 않는다.
+	deeper := p.MaxDepth > 0 && depth+1 >= p.MaxDepth
+	ents, err := safeReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, ent := range ents {
+		path := filepath.Join(dir, ent.Name())
+		if p.excluded(ent.Name()) || (p.SkipHidden && isHidden(ent.Name(), path)) {
+			p.SrcExcluded[src]++
+			continue
+		}
+		entRel := filepath.Join(rel, ent.Name())
+		if ent.Type()&fs.ModeSymlink != 0 && p.Symlinks != "keep" {
+			fi, err := safeStat(path)
+			var stale *staleMountError
+			if errors.As(err, &stale) {
+				return err
+			}
+			if err != nil {
+				p.ignore(path, "broken symlink")
+				continue
+			}
+			if fi.IsDir() {
+				if deeper {
+					p.SrcExcluded[src]++
+					continue
+				}
+				real, err := filepath.EvalSymlinks(path)
+				if err != nil {
+					return err
+				}
+				if parents[real] {
+					p.ignore(path, "symlink loop")
+					continue
+				}
+				err = p.walkSourceDir(src, path, entRel, depth+1, withParent(parents, real), fn)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if ent.IsDir() {
+			if deeper {
+				p.SrcExcluded[src]++
+				continue
+			}
+			real, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				return err
+			}
+			err = p.walkSourceDir(src, path, entRel, depth+1, withParent(parents, real), fn)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if !p.included(ent.Name()) {
+			p.SrcExcluded[src]++
+			continue
+		}
+		err := p.visitSrcFile(path, entRel, fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visitSrcFile은 디렉토리가 아닌 파일 하나를 방문한다.
+func (p *Program) visitSrcFile(path, rel string, fn func(f srcFile) error) error {
+	lfi, err := safeLstat(path)
+	if err != nil {
+		return err
+	}
+	if lfi.Mode()&fs.ModeSymlink != 0 {
+		if p.Symlinks == "keep" {
+			link, err := safeReadlink(path)
+			if err != nil {
+				return err
+			}
+			return fn(srcFile{Path: path, Rel: rel, Link: link})
+		}
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			p.ignore(path, "broken symlink")
+			return nil
+		}
+		fi, err := safeStat(real)
+		if err != nil {
+			return err
+		}
+		if kind := specialFileKind(fi.Mode()); kind != "" {
+			p.ignore(path, kind)
+			return nil
+		}
+		return fn(srcFile{Path: path, Rel: rel, Real: real})
+	}
+	if kind := specialFileKind(lfi.Mode()); kind != "" {
+		p.ignore(path, kind)
+		return nil
+	}
+	return fn(srcFile{Path: path, Rel: rel, Real: path})
+}
+
+// withParent는 parents에 dir을 더한 새 맵을 반환한다.
+// 형제 디렉토리끼리 서로의 경로를 공유하지 않도록 맵을 복사한다.
+func withParent(parents map[string]bool, dir string) map[string]bool {
+	m := make(map[string]bool, len(parents)+1)
+	for k := range parents {
+		m[k] = true
+	}
+	m[dir] = true
+	return m
+}
+
+// checkGlobs는 글롭 패턴들이 올바른지 확인한다.
+func checkGlobs(pats []string) error {
+	for _, pat := range pats {
+		if _, err := filepath.Match(pat, ""); err != nil {
+			return fmt.Errorf("%w: %s", err, pat)
+		}
+	}
+	return nil
+}
+
+// excluded는 디렉토리 소스 안의 파일이나 디렉토리 이름이 p.Excludes 중 하나에 맞는지 확인한다.
+// 대소문자는 구분하지 않는다.
+func (p *Program) excluded(name string) bool {
+	name = strings.ToLower(name)
+	for _, pat := range p.Excludes {
+		if ok, _ := filepath.Match(strings.ToLower(pat), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// included는 디렉토리 소스 안의 파일 이름이 p.Includes 중 하나에 맞는지 확인한다.
+// p.Includes가 비어있다면 모든 파일이 포함된다. 대소문자는 구분하지 않는다.
+func (p *Program) included(name string) bool {
+	if len(p.Includes) == 0 {
+		return true
+	}
+	name = strings.ToLower(name)
+	for _, pat := range p.Includes {
+		if ok, _ := filepath.Match(strings.ToLower(pat), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isHidden은 점(.)으로 시작하거나 윈도우즈의 숨김 속성이 있는 파일인지 확인한다.
+func isHidden(name, path string) bool {
+	return strings.HasPrefix(name, ".") || hiddenAttr(path)
+}
+
+// flatDest는 디렉토리 구조를 없애고 파일을 destDir 바로 아래에 둘 때의 대상 경로를 반환한다.
+// 다른 디렉토리의 같은 이름 파일과 겹친다면 이름 뒤에 _2, _3 처럼 번호를 붙인다.
+// used는 destDir 안에서 이미 쓰인 이름들로, 대소문자를 구분하지 않는 파일시스템을 위해 소문자로 기록된다.
+func flatDest(destDir, rel string, used map[string]bool) string {
+	name := filepath.Base(rel)
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	cand := name
+	for i := 2; used[strings.ToLower(cand)]; i++ {
+		cand = stem + "_" + strconv.Itoa(i) + ext
+	}
+	used[strings.ToLower(cand)] = true
+	return filepath.Join(destDir, cand)
+}
+
+// destPaths는 대상 디렉토리 destDir에 복사될 files의 대상 경로들을 files와 같은 순서로 반환한다.
+// 시퀀스 프레임 번호 바꾸기, 이름 정규화, 평탄화가 복사할 때와 똑같이 반영된다.
+func (p *Program) destPaths(destDir string, files []srcFile) ([]string, error) {
+	renames, err := p.Renumber.renameFrames(files)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool)
+	dests := make([]string, len(files))
+	for i, f := range files {
+		rel := f.Rel
+		if r, ok := renames[rel]; ok {
+			rel = r
+		}
+		rel = normalizeName(rel, p.Normalize)
+		d := filepath.Join(destDir, rel)
+		if p.Flatten {
+			d = flatDest(destDir, rel, used)
+		}
+		dests[i] = d
+	}
+	return dests, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"image"
+	"io/fs"
+
+	"gioui.org/app"
+	"gioui.org/unit"
+	"github.com/BurntSushi/toml"
+)
+
+// windowGeometry는 창의 크기와 위치이다. 크기는 dp, 위치는 화면의 픽셀 단위이다.
+type windowGeometry struct {
+	Width  float32
+	Height float32
+	// X와 Y는 창의 왼쪽 위 모서리로, HasPosition이 참일 때만 쓴다. 위치는 윈도우즈에서만 기록한다.
+	X           int
+	Y           int
+	HasPosition bool
+	Maximized   bool
+}
+
+// windowState는 창 크기를 저장하기 위해 창에서 받은 설정들이다.
+type windowState struct {
+	size    image.Point
+	mode    app.WindowMode
+	pxPerDp float32
+	sized   bool
+}
+
+// windowOptions는 창을 처음 열 때의 크기와 모드이다.
+// FixedWindow가 거짓이면 지난번에 닫을 때의 크기를, 아니면 WindowWidth, WindowHeight를 쓴다.
+// 둘 다 없다면 Gio의 기본 크기를 쓴다.
+func windowOptions(cfg *Config) []app.Option {
+	opts := make([]app.Option, 0)
+	w, h := cfg.WindowWidth, cfg.WindowHeight
+	last := cfg.LastWindow
+	if !cfg.FixedWindow && last.Width > 0 && last.Height > 0 {
+		w, h = last.Width, last.Height
+	}
+	if w > 0 && h > 0 {
+		opts = append(opts, app.Size(unit.Dp(w), unit.Dp(h)))
+	}
+	if !cfg.FixedWindow && last.Maximized {
+		opts = append(opts, app.Maximized.Option())
+	}
+	return opts
+}
+
+// trackWindow는 창의 크기나 모드가 바뀔 때 그것을 기억해둔다.
+func (ui *UI) trackWindow(c app.Config) {
+	ui.window.mode = c.Mode
+	// 최대화나 전체 화면일 때의 크기는 다음에 창을 열 때 쓰지 않는다.
+	if c.Mode == app.Windowed && c.Size.X > 0 && c.Size.Y > 0 {
+		ui.window.size = c.Size
+		ui.window.sized = true
+	}
+}
+
+// windowGeometry는 지금 창의 크기와 위치이다. 창의 크기를 알 수 없다면 지난번에 저장한 것을 그대로 쓴다.
+func (ui *UI) windowGeometry() windowGeometry {
+	g := ui.Config.LastWindow
+	if ui.window.sized && ui.window.pxPerDp > 0 {
+		g.Width = float32(ui.window.size.X) / ui.window.pxPerDp
+		g.Height = float32(ui.window.size.Y) / ui.window.pxPerDp
+	}
+	g.Maximized = ui.window.mode == app.Maximized
+	if x, y, ok := windowPosition(); ok {
+		g.X, g.Y, g.HasPosition = x, y, true
+	}
+	return g
+}
+
+// saveWindow는 창을 닫을 때 창의 크기와 위치, 입력 영역의 분할을 설정 파일에 저장한다.
+// 설정 파일의 다른 값들은 그대로 두지만, 화면 배율과 언어, 프로필처럼 프로그램 안에서 고른 설정은 함께 저장한다.
+func (ui *UI) saveWindow() error {
+	cfg := *ui.Config
+	_, err := toml.DecodeFile(ui.ConfigFile, &cfg)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	cfg.UIScale = ui.Config.UIScale
+	cfg.Language = ui.Config.Language
+	cfg.Profile = ui.Config.Profile
+	cfg.InputSplit = ui.InputSplit.Ratio
+	cfg.LastWindow = ui.windowGeometry()
+	return writeConfig(ui.ConfigFile, &cfg)
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package main
+
+import "gioui.org/io/event"
+
+// windowPosition은 창의 위치이다. Gio는 창의 위치를 알려주지 않으므로 윈도우즈 외에서는 기록하지 않는다.
+func windowPosition() (x, y int, ok bool) {
+	return 0, 0, false
+}
+
+// placeWindow는 창을 지난번 위치로 옮긴다. 윈도우즈 외에서는 창 관리자가 위치를 정한다.
+func (ui *UI) placeWindow(e event.Event) {}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"sync"
+	"unsafe"
+
+	"gioui.org/app"
+	"gioui.org/io/event"
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32              = windows.NewLazySystemDLL("user32.dll")
+	procSetWindowPos    = user32.NewProc("SetWindowPos")
+	procMonitorFromRect = user32.NewProc("MonitorFromRect")
+	procIsIconic        = user32.NewProc("IsIconic")
+	procIsZoomed        = user32.NewProc("IsZoomed")
+	procGetWindowRect   = user32.NewProc("GetWindowRect")
+)
+
+const (
+	wmWindowPosChanged = 0x0047
+	swpNoSize          = 0x0001
+	swpNoZOrder        = 0x0004
+	swpNoActivate      = 0x0010
+	monitorDefaultNull = 0
+)
+
+// windowPos는 창 스레드에서 기록한 창의 위치이다.
+var windowPos struct {
+	sync.Mutex
+	x, y   int
+	ok     bool
+	placed bool
+}
+
+// rememberPosition은 WM_WINDOWPOSCHANGED를 받았을 때 최소화나 최대화되지 않은 창의 위치를 기록한다.
+func rememberPosition(hwnd uintptr) {
+	if r, _, _ := procIsIconic.Call(hwnd); r != 0 {
+		return
+	}
+	if r, _, _ := procIsZoomed.Call(hwnd); r != 0 {
+		return
+	}
+	var rect windows.Rect
+	if r, _, _ := procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&rect))); r == 0 {
+		return
+	}
+	windowPos.Lock()
+	windowPos.x, windowPos.y, windowPos.ok = int(rect.Left), int(rect.Top), true
+	windowPos.Unlock()
+}
+
+// windowPosition은 창 스레드에서 마지막으로 기록한 창의 위치이다.
+func windowPosition() (x, y int, ok bool) {
+	windowPos.Lock()
+	defer windowPos.Unlock()
+	return windowPos.x, windowPos.y, windowPos.ok
+}
+
+// placeWindow는 네이티브 창이 처음 만들어질 때 지난번에 저장한 위치로 창을 옮긴다.
+// 그 위치가 지금 연결된 어느 모니터에도 없다면 옮기지 않는다.
+func (ui *UI) placeWindow(e event.Event) {
+	ve, ok := e.(app.Win32ViewEvent)
+	if !ok || ve.HWND == 0 {
+		return
+	}
+	last := ui.Config.LastWindow
+	windowPos.Lock()
+	placed := windowPos.placed
+	windowPos.placed = true
+	windowPos.Unlock()
+	if placed || ui.Config.FixedWindow || !last.HasPosition {
+		return
+	}
+	rect := windows.Rect{
+		Left:   int32(last.X),
+		Top:    int32(last.Y),
+		Right:  int32(last.X) + 100,
+		Bottom: int32(last.Y) + 40,
+	}
+	if mon, _, _ := procMonitorFromRect.Call(uintptr(unsafe.Pointer(&rect)), monitorDefaultNull); mon == 0 {
+		return
+	}
+	hwnd := ve.HWND
+	// 창 스레드가 이벤트를 넘겨주는 동안 기다리지 않도록 다른 고루틴에서 요청한다.
+	go ui.Window.Run(func() {
+		procSetWindowPos.Call(hwnd, 0, uintptr(last.X), uintptr(last.Y), 0, 0, swpNoSize|swpNoZOrder|swpNoActivate)
+	})
+}